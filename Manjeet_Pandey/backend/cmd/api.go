@@ -1,26 +1,243 @@
 package main
 
 import (
-	"task_manager/internal/middleware"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	docsv1 "task_manager/cmd/docs/v1"
+	docsv2 "task_manager/cmd/docs/v2"
+	"task_manager/internal/certs"
+	"task_manager/internal/config"
+	"task_manager/internal/events"
+	"task_manager/internal/handler"
+	"task_manager/internal/metrics"
 	"task_manager/internal/routes"
+	"task_manager/internal/service"
+	"task_manager/internal/storage"
+	"task_manager/internal/web"
+	"task_manager/internal/webhook"
+	"task_manager/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 )
 
-func StartApi(db *gorm.DB) {
+// processWorkers is the size of the worker pool that processes the tasks
+// claimed through the API.
+const processWorkers = 5
 
-	r := gin.Default()
-	r.Use(middleware.CORSMiddleware())
-	routes.SetupRoutes(r, db)
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+// StartApi serves the API until ctx is cancelled, then stops accepting
+// connections and gives in-flight requests, and the tasks they queued,
+// cfg.ShutdownTimeout to finish.
+// The frontend is served from the binary, or forwarded to the Vite dev
+// server at devProxy when it is set.
+func StartApi(ctx context.Context, cfg config.Config, db *gorm.DB, bus *events.Bus, dispatcher *webhook.Dispatcher, devProxy *url.URL) {
+	if zerolog.GlobalLevel() > zerolog.DebugLevel {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	store, err := storage.New(ctx, storage.Options{
+		Backend: cfg.AttachmentStorage,
+		Dir:     cfg.AttachmentDir,
+		S3: storage.S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		},
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Cannot open the attachment storage")
+	}
+	go purgeAttachments(ctx, service.NewAttachmentService(db, store, service.AttachmentLimits{}), bus)
+
+	// The REST and gRPC APIs share one pool, so a task is only ever
+	// processed once and shutdown has a single queue to drain.
+	workers := util.NewWorker(processWorkers, service.NewTaskService(db, bus))
+	workers.StartWorker()
+
+	streamsDone := make(chan struct{})
+	r := routes.NewEngine(cfg, db, store, bus, dispatcher, workers, streamsDone)
+	r.GET("/swagger/*any", swaggerHandler())
+	r.GET("/openapi/:file", openAPIHandler())
+	r.NoRoute(frontendHandler(devProxy))
+
+	projectService := service.NewProjectService(db)
+	metrics.RegisterTaskStatus(&projectService)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	srv := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+	// Shutdown waits for handlers to return, so the open-ended event
+	// streams are told to end as soon as it starts.
+	srv.RegisterOnShutdown(func() { close(streamsDone) })
+
+	useTLS := cfg.TLSCertFile != "" || cfg.TLSKeyFile != ""
+	if useTLS {
+		reloader, err := certs.NewReloader(certs.Options{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+			ClientAuth:   cfg.TLSClientAuth,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Cannot load the TLS certificate")
+		}
+		if err := reloader.Watch(); err != nil {
+			log.Fatal().Err(err).Msg("Cannot watch the TLS certificate files")
+		}
+		defer reloader.Close()
+		srv.TLSConfig = reloader.TLSConfig()
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetHTTP2(true)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info().Str("addr", cfg.HTTPAddr).Bool("tls", useTLS).Bool("mtls", useTLS && cfg.TLSClientCAFile != "").Msg("Starting Api")
+		if useTLS {
+			// The certificate comes from srv.TLSConfig.
+			serveErr <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		serveErr <- srv.ListenAndServe()
+	}()
 
-	log.Print("Starting Api on :8080")
+	stopGRPC := startGRPC(cfg, db, bus, workers, srv.TLSConfig)
 
-	if err := r.Run(":8080"); err != nil {
+	select {
+	case err := <-serveErr:
 		log.Fatal().Err(err).Msg("Cannot initialize the server")
+	case <-ctx.Done():
+	}
+
+	log.Info().Dur("timeout", cfg.ShutdownTimeout).Msg("Shutting down Api")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	// Both servers drain at once.
+	grpcStopped := make(chan struct{})
+	go func() {
+		if stopGRPC != nil {
+			stopGRPC(shutdownCtx)
+		}
+		close(grpcStopped)
+	}()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Err(err).Msg("Requests still in flight were cut off")
+		srv.Close()
+	}
+	// Nothing can queue tasks once both servers have stopped.
+	<-grpcStopped
+	if err := workers.Shutdown(shutdownCtx); err != nil {
+		log.Err(err).Msg("Tasks still queued were cut off")
+		return
+	}
+	log.Info().Msg("Api stopped")
+}
+
+// purgeAttachments deletes the attachments of deleted tasks: those left by
+// earlier runs at startup, then those of each task deleted while running.
+func purgeAttachments(ctx context.Context, attachments service.AttachmentService, bus *events.Bus) {
+	sub := bus.Subscribe(events.Filter{})
+	defer sub.Close()
+	purge := func() {
+		purged, err := attachments.PurgeOrphans(ctx)
+		if err != nil {
+			log.Err(err).Msg("Cannot purge the attachments of deleted tasks")
+			return
+		}
+		if purged > 0 {
+			log.Info().Int("count", purged).Msg("Purged the attachments of deleted tasks")
+		}
 	}
+	purge()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-sub.C:
+			if event.Type == events.TaskDeleted {
+				purge()
+			}
+		}
+	}
+}
+
+// openAPIHandler serves the OpenAPI 3.1 document of each version as
+// /openapi/v1.json and /openapi/v2.json.
+func openAPIHandler() gin.HandlerFunc {
+	docs := map[string][]byte{
+		"v1.json": docsv1.OpenAPI,
+		"v2.json": docsv2.OpenAPI,
+	}
+	return func(c *gin.Context) {
+		doc, ok := docs[c.Param("file")]
+		if !ok {
+			handler.NotFoundHandler()(c)
+			return
+		}
+		c.Data(http.StatusOK, "application/json", doc)
+	}
+}
+
+// swaggerHandler serves the API docs of each version under /swagger/v1/
+// and /swagger/v2/, and sends anything else to the latest version.
+func swaggerHandler() gin.HandlerFunc {
+	docs := map[string]gin.HandlerFunc{}
+	for _, v := range []string{"v1", "v2"} {
+		docs[v] = ginSwagger.WrapHandler(swaggerfiles.NewHandler(), ginSwagger.InstanceName(v))
+	}
+	return func(c *gin.Context) {
+		version, _, _ := strings.Cut(strings.TrimPrefix(c.Param("any"), "/"), "/")
+		serve, ok := docs[version]
+		if !ok {
+			c.Redirect(http.StatusFound, "/swagger/v2/index.html")
+			return
+		}
+		serve(c)
+	}
+}
+
+// frontendHandler serves the frontend for paths no route matched, and
+// reports anything else as not found.
+func frontendHandler(devProxy *url.URL) gin.HandlerFunc {
+	notFound := handler.NotFoundHandler()
+	if devProxy != nil {
+		log.Info().Str("target", devProxy.String()).Msg("Forwarding the frontend to the dev server")
+		return web.DevProxy(devProxy)
+	}
+	frontend, ok := web.Embedded()
+	if !ok {
+		log.Info().Msg("The frontend wasn't built into this binary; serving the API only")
+		return notFound
+	}
+	return frontend.Handler(notFound)
+}
+
+// StartMetrics serves /metrics on its own listener, for processes such as
+// the worker daemon that don't run the API.
+func StartMetrics(addr string, counter metrics.StatusCounter) {
+	metrics.RegisterTaskStatus(counter)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	go func() {
+		log.Info().Str("addr", addr).Msg("Serving metrics")
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatal().Err(err).Msg("Cannot start the metrics listener")
+		}
+	}()
 }
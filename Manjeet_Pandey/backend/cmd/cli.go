@@ -1,37 +1,116 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"task_manager/internal/service"
+	"task_manager/internal/tracing"
+	"task_manager/internal/transfer"
+	"task_manager/internal/version"
 	"task_manager/model"
 	"task_manager/util"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
 type CliHandler struct {
-	taskService service.TaskService
+	taskService    service.TaskService
+	commentService service.CommentService
+	projectService service.ProjectService
 }
 
-func NewCliHandler(service service.TaskService) CliHandler {
+func NewCliHandler(service service.TaskService, comments service.CommentService, projects service.ProjectService) CliHandler {
 	return CliHandler{
-		taskService: service,
+		taskService:    service,
+		commentService: comments,
+		projectService: projects,
 	}
 }
 
+// UseProject scopes the task and comment commands to the project whose
+// name or ID is ref.
+func (t *CliHandler) UseProject(ref string) {
+	if ref == "" {
+		return
+	}
+	project, err := t.projectService.FindProject(context.Background(), ref)
+	if err != nil {
+		log.Fatal().Err(err).Str("project", ref).Msg("Cannot find project")
+	}
+	t.taskService = t.taskService.InProject(project.ID)
+	t.commentService = t.commentService.InProject(project.ID)
+}
+
+func (t *CliHandler) ListProjects() {
+	projects, err := t.projectService.ListProjects(context.Background())
+	if err != nil {
+		log.Err(err).Msg("Error listing projects")
+		return
+	}
+	FormatProjectOutput(projects, loadSettings().Project)
+}
+
+func (t *CliHandler) AddProject(req model.CreateProjectRequest) {
+	created, err := t.projectService.CreateProject(context.Background(), req)
+	if err != nil {
+		log.Err(err).Msg("Error creating project")
+		return
+	}
+	FormatProjectOutput([]model.Project{created}, loadSettings().Project)
+}
+
 func FormatListOutput(entries []model.Task) {
 	fmt.Println("  ---------------------------------------------------------------------------------------------")
-	fmt.Printf("| %-20s | %-20s | %-45s |\n", "Name", "Description", "Status")
-	fmt.Println("|----------------------|----------------------|-----------------------------------------------|")
+	fmt.Printf("| %-20s | %-20s | %-9s | %-33s |\n", "Name", "Description", "Status", "Labels")
+	fmt.Println("|----------------------|----------------------|-----------|-----------------------------------|")
 	for _, entry := range entries {
 		name := entry.Name
 		description := entry.Description
 		status := entry.Status
-		fmt.Printf("| %-20s | %-20s | %-45s |\n", name, description, status)
+		labels := make([]string, len(entry.Labels))
+		for i, label := range entry.Labels {
+			labels[i] = label.Name
+		}
+		fmt.Printf("| %-20s | %-20s | %-9s | %-33s |\n", name, description, status, strings.Join(labels, ", "))
+	}
+	fmt.Println("  ---------------------------------------------------------------------------------------------")
+}
+
+func FormatBulkOutput(results []service.BulkResult) {
+	fmt.Println("  ---------------------------------------------------------------------------------------------")
+	fmt.Printf("| %-5s | %-8s | %-36s | %-33s |\n", "#", "Op", "ID", "Result")
+	fmt.Println("|-------|----------|--------------------------------------|-----------------------------------|")
+	for _, result := range results {
+		outcome := "ok"
+		if result.Err != nil {
+			outcome = result.Err.Error()
+		}
+		fmt.Printf("| %-5d | %-8s | %-36s | %-33s |\n", result.Index, result.Op, result.ID, outcome)
 	}
 	fmt.Println("  ---------------------------------------------------------------------------------------------")
 }
 
+// FormatCommentOutput prints comments oldest first, each under a line with
+// its author, time and ID.
+func FormatCommentOutput(comments []model.Comment) {
+	for _, comment := range comments {
+		edited := ""
+		if comment.EditedAt != nil {
+			edited = " (edited)"
+		}
+		fmt.Printf("--- %s, %s%s [%s]\n", comment.Author, comment.CreatedAt.Local().Format(time.DateTime), edited, comment.ID)
+		fmt.Println(comment.Body)
+	}
+	if len(comments) == 0 {
+		fmt.Println("No comments")
+	}
+}
+
 func (t *CliHandler) AddTask(name, description string) {
 	task := model.Task{
 		Name:        name,
@@ -39,16 +118,18 @@ func (t *CliHandler) AddTask(name, description string) {
 		Status:      model.StatusPending,
 	}
 
-	if err := t.taskService.CreateTask(task); err != nil {
+	created, err := t.taskService.CreateTask(context.Background(), task)
+	if err != nil {
 		log.Err(err).Msg("Error creating task")
 		return
 	}
-	FormatListOutput([]model.Task{task})
+	FormatListOutput([]model.Task{created})
 	return
 }
 
-func (t *CliHandler) ListTask() {
-	tasks, err := t.taskService.ListTask()
+// ListTask prints the tasks matching filter.
+func (t *CliHandler) ListTask(filter service.LabelFilter) {
+	tasks, err := t.taskService.ListTask(context.Background(), filter)
 	if err != nil {
 		log.Err(err).Msg("Error Listing Task")
 		return
@@ -57,19 +138,139 @@ func (t *CliHandler) ListTask() {
 	return
 }
 
+// AddComment comments on the task as author.
+func (t *CliHandler) AddComment(taskID uuid.UUID, author, body string) {
+	created, err := t.commentService.CreateComment(context.Background(), taskID, model.CreateCommentRequest{Author: author, Body: body})
+	if err != nil {
+		log.Err(err).Msg("Error creating comment")
+		return
+	}
+	FormatCommentOutput([]model.Comment{created})
+}
+
+// ListComments prints the comments on the task.
+func (t *CliHandler) ListComments(taskID uuid.UUID) {
+	comments, err := t.commentService.ListComments(context.Background(), taskID)
+	if err != nil {
+		log.Err(err).Msg("Error listing comments")
+		return
+	}
+	FormatCommentOutput(comments)
+}
+
+// RunBatch reads a bulk batch as JSON from r and runs it.
+func (t *CliHandler) RunBatch(r io.Reader) {
+	var req service.BulkRequest
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		log.Err(err).Msg("Invalid batch")
+		return
+	}
+	results, err := t.taskService.Bulk(context.Background(), req)
+	if err != nil {
+		log.Err(err).Msg("Error running batch")
+		return
+	}
+	FormatBulkOutput(results)
+	return
+}
+
+// ExportTasks writes every task to w in the given format.
+func (t *CliHandler) ExportTasks(w io.Writer, formatName string) {
+	format, err := transfer.ParseFormat(formatName)
+	if err != nil {
+		log.Err(err).Msg("Invalid format")
+		return
+	}
+	enc := transfer.NewEncoder(format, w)
+	if err := t.taskService.ExportTasks(context.Background(), enc.Encode); err != nil {
+		log.Err(err).Msg("Error exporting tasks")
+		return
+	}
+	if err := enc.Close(); err != nil {
+		log.Err(err).Msg("Error exporting tasks")
+	}
+	return
+}
+
+// ImportTasks loads tasks from r in the given format.
+func (t *CliHandler) ImportTasks(r io.Reader, formatName, onConflict string) {
+	format, err := transfer.ParseFormat(formatName)
+	if err != nil {
+		log.Err(err).Msg("Invalid format")
+		return
+	}
+	mode, err := service.ParseConflictMode(onConflict)
+	if err != nil {
+		log.Err(err).Msg("Invalid conflict mode")
+		return
+	}
+	summary, err := t.taskService.ImportTasks(context.Background(), transfer.NewDecoder(format, r).Decode, mode)
+	if err != nil {
+		log.Err(err).Msg("Error importing tasks")
+		return
+	}
+	fmt.Printf("Read %d, written %d, skipped %d\n", summary.Read, summary.Written, summary.Skipped)
+	return
+}
+
 func (t *CliHandler) ProcessTask() {
 	numWorker := 5
-	tasks, err := t.taskService.GetPendingTasks()
+	t.processPending(numWorker)
+	return
+}
+
+// RunWorker processes pending tasks every interval until ctx is cancelled.
+func (t *CliHandler) RunWorker(ctx context.Context, numWorker int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		t.processPending(numWorker)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processPending runs one pass over the pending tasks of every project,
+// sharing the workers between projects within their concurrency limits.
+// Each pass is its own trace; it doesn't inherit the caller's cancellation
+// so a pass in progress always finishes.
+func (t *CliHandler) processPending(numWorker int) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "worker.processPending")
+	projects, err := t.projectService.ListProjects(ctx)
+	defer func() { tracing.End(span, err) }()
 	if err != nil {
-		log.Err(err).Msg("Cannot get Pending Task")
+		log.Err(err).Msg("Cannot list projects")
 		return
 	}
+	var tasks []model.Task
+	for _, project := range projects {
+		projectTasks := t.taskService.InProject(project.ID)
+		pending, err := projectTasks.ClaimPendingTasks(ctx)
+		if err != nil {
+			log.Err(err).Str("project", project.Name).Msg("Cannot claim Pending Task")
+			continue
+		}
+		tasks = append(tasks, pending...)
+	}
 	workers := util.NewWorker(numWorker, t.taskService)
 
 	workers.StartWorker()
 	for _, task := range tasks {
-		workers.AddToQueue(task.ID)
+		workers.AddToQueue(ctx, task)
 	}
+	workers.Close()
 	workers.Wait()
-	return
+}
+
+// PrintVersion writes the build information served on /version.
+func PrintVersion(w io.Writer) {
+	info := version.Get()
+	fmt.Fprintf(w, "Version:        %s\n", info.Version)
+	fmt.Fprintf(w, "Commit:         %s\n", info.Commit)
+	fmt.Fprintf(w, "Build time:     %s\n", info.BuildTime)
+	fmt.Fprintf(w, "Go version:     %s\n", info.GoVersion)
+	fmt.Fprintf(w, "Schema version: %d\n", info.SchemaVersion)
 }
@@ -0,0 +1,9 @@
+package v1
+
+import _ "embed"
+
+// OpenAPI is this version's document as OpenAPI 3.1, converted from
+// v1_swagger.json by `go run ./cmd/openapi generate`.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,9 @@
+package v2
+
+import _ "embed"
+
+// OpenAPI is this version's document as OpenAPI 3.1, converted from
+// v2_swagger.json by `go run ./cmd/openapi generate`.
+//
+//go:embed openapi.json
+var OpenAPI []byte
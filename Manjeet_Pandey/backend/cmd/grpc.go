@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"task_manager/internal/config"
+	"task_manager/internal/events"
+	"task_manager/internal/rpc"
+	"task_manager/internal/service"
+	taskv1 "task_manager/proto/task/v1"
+	"task_manager/util"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"gorm.io/gorm"
+)
+
+// startGRPC serves the gRPC API on cfg.GRPCAddr, with the API's TLS
+// configuration when tlsConfig is set. Tasks are processed by workers. The returned function stops it,
+// waiting for in-flight calls until ctx is done. It returns nil when the
+// gRPC API is turned off.
+func startGRPC(cfg config.Config, db *gorm.DB, bus *events.Bus, workers *util.Worker, tlsConfig *tls.Config) func(ctx context.Context) {
+	if cfg.GRPCAddr == "off" {
+		return nil
+	}
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Cannot initialize the gRPC server")
+	}
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(rpc.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(rpc.StreamInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+
+	streamsDone := make(chan struct{})
+	taskv1.RegisterTaskServiceServer(srv, rpc.NewTaskServer(service.NewTaskService(db, bus), workers, streamsDone))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(taskv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	reflection.Register(srv)
+
+	go func() {
+		log.Info().Str("addr", cfg.GRPCAddr).Bool("tls", tlsConfig != nil).Msg("Starting gRPC Api")
+		if err := srv.Serve(lis); err != nil {
+			log.Fatal().Err(err).Msg("Cannot initialize the gRPC server")
+		}
+	}()
+
+	return func(ctx context.Context) {
+		healthServer.Shutdown()
+		// GracefulStop waits for streams too, so watchers are told to end
+		// first.
+		close(streamsDone)
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			log.Info().Msg("gRPC Api stopped")
+		case <-ctx.Done():
+			log.Error().Msg("gRPC calls still in flight were cut off")
+			srv.Stop()
+		}
+	}
+}
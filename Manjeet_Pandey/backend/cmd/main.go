@@ -1,45 +1,260 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"net/url"
 	"os"
-	_ "task_manager/cmd/docs"
+	"os/signal"
+	"os/user"
+	"strings"
+	"syscall"
+	"task_manager/internal/config"
 	"task_manager/internal/database"
+	"task_manager/internal/events"
+	"task_manager/internal/logging"
 	"task_manager/internal/service"
+	"task_manager/internal/tracing"
+	"task_manager/internal/webhook"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
-// @title        Task Manager API
-// @version      1.0
-// @description  A simple task management API built with Go and Gin.
-// @host         localhost:8080
-// @BasePath     /
+//go:generate swag init --dir ../internal/handler --exclude ../internal/handler/v2 --generalInfo doc.go --output docs/v1 --instanceName v1 --parseDependency --parseInternal
+//go:generate swag init --dir ../internal/handler/v2 --generalInfo doc.go --output docs/v2 --instanceName v2 --parseDependency --parseInternal
+//go:generate go run ./openapi generate -docs docs
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=task_manager --go-grpc_out=.. --go-grpc_opt=module=task_manager task/v1/task.proto
+
 func main() {
-	db := database.InitDB()
+	cfg, err := config.Load()
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid configuration")
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TraceExporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+	defer flushTraces(shutdownTracing)
 	// var args []string
 	args := os.Args
-	cliHandler := NewCliHandler(service.NewTaskService(db))
 	if len(args) < 2 {
 		log.Fatal().Msg("Not enough argument")
 	}
+	if args[1] == "version" {
+		// Answer without opening, and possibly migrating, the database.
+		PrintVersion(os.Stdout)
+		return
+	}
+	if cfg.APIURL != "" && runRemote(cfg, args) {
+		return
+	}
+	db := database.InitDB()
+	defer database.Close(db)
+
+	bus := events.NewBus()
+	projectService := service.NewProjectService(db)
+	cliHandler := NewCliHandler(service.NewTaskService(db, bus), service.NewCommentService(db), projectService)
 	switch args[1] {
 	case "api":
-		StartApi(db)
+		flags := flag.NewFlagSet("api", flag.ExitOnError)
+		devProxy := flags.String("dev-proxy", "", "forward the frontend to the Vite dev server at this URL, e.g. http://localhost:5173")
+		flags.Parse(args[2:])
+		var devProxyURL *url.URL
+		if *devProxy != "" {
+			if devProxyURL, err = url.Parse(*devProxy); err != nil || devProxyURL.Host == "" {
+				log.Fatal().Str("dev_proxy", *devProxy).Msg("Invalid --dev-proxy URL")
+			}
+		}
+		dispatcher := startWebhooks(db, bus)
+		defer drainWebhooks(dispatcher)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		StartApi(ctx, cfg, db, bus, dispatcher, devProxyURL)
 	case "list":
-		cliHandler.ListTask()
+		filter, project := parseListFlags(args[2:])
+		cliHandler.UseProject(project)
+		cliHandler.ListTask(filter)
 	case "add":
-		if len(args) < 4 {
-			log.Fatal().Msg("Not enough argument, Usage ./task_manager add <name> <description>")
-			return
-		}
-		name := args[2]
-		description := args[3]
+		name, description, project := parseAddFlags(args[2:])
+		cliHandler.UseProject(project)
 		cliHandler.AddTask(name, description)
+	case "comment":
+		runComment(&cliHandler, args[2:])
+	case "project":
+		runProject(&cliHandler, args[2:])
 	case "process":
 		cliHandler.ProcessTask()
+	case "worker":
+		flags := flag.NewFlagSet("worker", flag.ExitOnError)
+		workers := flags.Int("workers", 5, "number of concurrent workers")
+		interval := flags.Duration("interval", 5*time.Second, "how often to look for pending tasks")
+		metricsAddr := flags.String("metrics-addr", "", "address to serve /metrics on, e.g. :9091")
+		flags.Parse(args[2:])
+		if *metricsAddr != "" {
+			StartMetrics(*metricsAddr, &projectService)
+		}
+		defer drainWebhooks(startWebhooks(db, bus))
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		cliHandler.RunWorker(ctx, *workers, *interval)
+	case "export":
+		flags := flag.NewFlagSet("export", flag.ExitOnError)
+		format := flags.String("format", "json", "output format: json, csv or ndjson")
+		project := projectFlag(flags)
+		flags.Parse(args[2:])
+		cliHandler.UseProject(*project)
+		cliHandler.ExportTasks(os.Stdout, *format)
+	case "import":
+		flags := flag.NewFlagSet("import", flag.ExitOnError)
+		format := flags.String("format", "", "input format: json, csv or ndjson (omit to read a bulk batch)")
+		onConflict := flags.String("on-conflict", "fail", "existing IDs: fail, skip or update")
+		project := projectFlag(flags)
+		flags.Parse(args[2:])
+		cliHandler.UseProject(*project)
+		if *format == "" {
+			cliHandler.RunBatch(os.Stdin)
+			return
+		}
+		cliHandler.ImportTasks(os.Stdin, *format, *onConflict)
 	default:
 		log.Fatal().Msg("Don;t know what to do")
 	}
 }
+
+// runRemote runs the commands that support remote mode against the API at
+// cfg.APIURL. It reports false for the others, which use the database.
+func runRemote(cfg config.Config, args []string) bool {
+	remote := NewRemoteHandler(cfg.APIURL)
+	switch args[1] {
+	case "list":
+		filter, project := parseListFlags(args[2:])
+		remote.UseProject(project)
+		remote.ListTask(filter)
+	case "add":
+		name, description, project := parseAddFlags(args[2:])
+		remote.UseProject(project)
+		remote.AddTask(name, description)
+	case "comment":
+		runComment(&remote, args[2:])
+	case "project":
+		runProject(&remote, args[2:])
+	default:
+		return false
+	}
+	return true
+}
+
+// labelFlags collects the values of a repeated flag.
+type labelFlags []string
+
+func (l *labelFlags) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *labelFlags) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseListFlags reads "list [--project name] [--label name]... [--any]".
+// Tasks must carry every label given, or one of them with --any.
+func parseListFlags(args []string) (service.LabelFilter, string) {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	project := projectFlag(flags)
+	var labels labelFlags
+	flags.Var(&labels, "label", "only list tasks with this label; repeat or separate with commas for several")
+	anyLabel := flags.Bool("any", false, "list tasks with any of the labels rather than all of them")
+	flags.Parse(args)
+	match := service.MatchAll
+	if *anyLabel {
+		match = service.MatchAny
+	}
+	filter, err := service.ParseLabelFilter(labels, string(match))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid label filter")
+	}
+	return filter, *project
+}
+
+// commenter is implemented by the local and remote CLI handlers.
+type commenter interface {
+	UseProject(ref string)
+	AddComment(taskID uuid.UUID, author, body string)
+	ListComments(taskID uuid.UUID)
+}
+
+// runComment runs "comment add [--project name] [--author name] <task-id>
+// <body>" and "comment list [--project name] <task-id>". The author defaults
+// to the current user.
+func runComment(h commenter, args []string) {
+	const usage = "Usage ./task_manager comment add [--project <name>] [--author <name>] <task-id> <body> | comment list [--project <name>] <task-id>"
+	if len(args) < 1 {
+		log.Fatal().Msg("Not enough argument, " + usage)
+	}
+	flags := flag.NewFlagSet("comment "+args[0], flag.ExitOnError)
+	author := flags.String("author", currentUser(), "name to comment as")
+	project := projectFlag(flags)
+	flags.Parse(args[1:])
+	rest := flags.Args()
+	if len(rest) < 1 {
+		log.Fatal().Msg("Not enough argument, " + usage)
+	}
+	taskID, err := uuid.Parse(rest[0])
+	if err != nil {
+		log.Fatal().Str("task_id", rest[0]).Msg("Invalid task id")
+	}
+	h.UseProject(*project)
+	switch args[0] {
+	case "add":
+		if len(rest) < 2 {
+			log.Fatal().Msg("Not enough argument, " + usage)
+		}
+		h.AddComment(taskID, *author, rest[1])
+	case "list":
+		h.ListComments(taskID)
+	default:
+		log.Fatal().Msg(usage)
+	}
+}
+
+// currentUser is the login name of the user running the CLI, if known.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// startWebhooks starts delivering the events of the webhook outbox. Only the
+// long-running commands deliver; the events the others write wait in the
+// outbox for a running api or worker.
+func startWebhooks(db *gorm.DB, bus *events.Bus) *webhook.Dispatcher {
+	dispatcher := webhook.NewDispatcher(service.NewWebhookService(db), bus, webhook.Options{})
+	dispatcher.Start()
+	return dispatcher
+}
+
+// drainWebhooks gives queued webhook deliveries a chance to finish before
+// the process exits.
+func drainWebhooks(dispatcher *webhook.Dispatcher) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		log.Err(err).Msg("Abandoned pending webhook deliveries")
+	}
+}
+
+// flushTraces exports the spans still buffered when the process exits.
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.Err(err).Msg("Cannot flush traces")
+	}
+}
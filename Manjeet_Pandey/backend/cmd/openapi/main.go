@@ -0,0 +1,71 @@
+// Command openapi keeps the OpenAPI 3.1 documents in step with the code.
+//
+//	go run ./cmd/openapi generate   convert cmd/docs/*/*_swagger.json to openapi.json
+//
+// TestContract in internal/routes fails when openapi.json is stale or the
+// API doesn't match it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"task_manager/internal/openapi"
+)
+
+// versions are the API versions with a document under the docs directory.
+var versions = []string{"v1", "v2"}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	docsDir := flags.String("docs", "cmd/docs", "directory holding a swag output directory per version")
+	flags.Parse(os.Args[2:])
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = generate(*docsDir)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: openapi generate [-docs dir]")
+	os.Exit(2)
+}
+
+// convert returns the OpenAPI document of version, converted from its
+// Swagger document.
+func convert(docsDir, version string) ([]byte, error) {
+	swagger, err := os.ReadFile(filepath.Join(docsDir, version, version+"_swagger.json"))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi.Convert(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", version, err)
+	}
+	return doc, nil
+}
+
+func generate(docsDir string) error {
+	for _, version := range versions {
+		doc, err := convert(docsDir, version)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(docsDir, version, "openapi.json"), doc, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
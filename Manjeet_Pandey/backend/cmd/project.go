@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"task_manager/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// settings are the CLI preferences saved between runs.
+type settings struct {
+	// Project is the name or ID of the project commands use when --project
+	// isn't given. Empty means the default project.
+	Project string `json:"project,omitempty"`
+}
+
+// settingsPath is where the settings are saved, under the user's
+// configuration directory.
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "task_manager", "cli.json"), nil
+}
+
+// loadSettings reads the saved settings. Missing or unreadable settings
+// leave every preference at its default.
+func loadSettings() settings {
+	var s settings
+	path, err := settingsPath()
+	if err != nil {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Cannot read CLI settings")
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Invalid CLI settings")
+	}
+	return s
+}
+
+func saveSettings(s settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// projectFlag registers --project on flags, defaulting to the saved
+// project.
+func projectFlag(flags *flag.FlagSet) *string {
+	return flags.String("project", loadSettings().Project, "name or id of the project to use instead of the saved one")
+}
+
+// parseAddFlags reads "add [--project name] <name> <description>".
+func parseAddFlags(args []string) (name, description, project string) {
+	flags := flag.NewFlagSet("add", flag.ExitOnError)
+	projectRef := projectFlag(flags)
+	flags.Parse(args)
+	rest := flags.Args()
+	if len(rest) < 2 {
+		log.Fatal().Msg("Not enough argument, Usage ./task_manager add [--project <name>] <name> <description>")
+	}
+	return rest[0], rest[1], *projectRef
+}
+
+func FormatProjectOutput(projects []model.Project, current string) {
+	fmt.Println("  ------------------------------------------------------------------------")
+	fmt.Printf("| %1s | %-20s | %-33s | %-9s |\n", "", "Name", "Description", "Limit")
+	fmt.Println("  ------------------------------------------------------------------------")
+	for _, project := range projects {
+		marker := ""
+		if project.ID.String() == current || project.Name == current || (current == "" && project.ID == model.DefaultProjectID) {
+			marker = "*"
+		}
+		limit := "-"
+		if project.MaxConcurrency > 0 {
+			limit = fmt.Sprint(project.MaxConcurrency)
+		}
+		fmt.Printf("| %1s | %-20s | %-33s | %-9s |\n", marker, project.Name, project.Description, limit)
+	}
+	fmt.Println("  ------------------------------------------------------------------------")
+}
+
+// projectManager is implemented by the local and remote CLI handlers.
+type projectManager interface {
+	// UseProject scopes the task commands to the project whose name or ID
+	// is ref, exiting if there is none. An empty ref is the default
+	// project.
+	UseProject(ref string)
+	ListProjects()
+	AddProject(req model.CreateProjectRequest)
+}
+
+// runProject runs "project list", "project add [--description text]
+// [--max-concurrency n] <name>" and "project use <name|id>" or "project use
+// --clear". The project marked in the list is the one used when --project
+// isn't given.
+func runProject(h projectManager, args []string) {
+	const usage = "Usage ./task_manager project list | project add [--description <text>] [--max-concurrency <n>] <name> | project use <name|id> | project use --clear"
+	if len(args) < 1 {
+		log.Fatal().Msg("Not enough argument, " + usage)
+	}
+	flags := flag.NewFlagSet("project "+args[0], flag.ExitOnError)
+	switch args[0] {
+	case "list":
+		flags.Parse(args[1:])
+		h.ListProjects()
+	case "add":
+		description := flags.String("description", "", "what the project is about")
+		maxConcurrency := flags.Int("max-concurrency", 0, "how many of the project's tasks a worker processes at once, 0 for no limit")
+		flags.Parse(args[1:])
+		if flags.NArg() < 1 {
+			log.Fatal().Msg("Not enough argument, " + usage)
+		}
+		h.AddProject(model.CreateProjectRequest{Name: flags.Arg(0), Description: *description, MaxConcurrency: *maxConcurrency})
+	case "use":
+		clear := flags.Bool("clear", false, "go back to the default project")
+		flags.Parse(args[1:])
+		s := loadSettings()
+		switch {
+		case *clear:
+			s.Project = ""
+		case flags.NArg() == 1:
+			h.UseProject(flags.Arg(0))
+			s.Project = flags.Arg(0)
+		default:
+			log.Fatal().Msg(usage)
+		}
+		if err := saveSettings(s); err != nil {
+			log.Fatal().Err(err).Msg("Cannot save CLI settings")
+		}
+		if s.Project == "" {
+			fmt.Println("Using the default project")
+		} else {
+			fmt.Printf("Using project %s\n", s.Project)
+		}
+	default:
+		log.Fatal().Msg(usage)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	v2 "task_manager/internal/handler/v2"
+	"task_manager/internal/middleware"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	remoteAttempts = 4
+	remoteTimeout  = 30 * time.Second
+	// maxRetryDelay caps how long a Retry-After header can make us wait.
+	maxRetryDelay = 30 * time.Second
+)
+
+// RemoteHandler runs CLI commands against a running API server instead of
+// opening the database, for use when TASK_MANAGER_API_URL is set.
+type RemoteHandler struct {
+	baseURL string
+	client  *http.Client
+	// tasksPath is where the tasks of the project in use are served.
+	tasksPath string
+}
+
+func NewRemoteHandler(baseURL string) RemoteHandler {
+	return RemoteHandler{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		client:    &http.Client{Timeout: remoteTimeout},
+		tasksPath: "/api/v2/tasks",
+	}
+}
+
+// UseProject scopes the task and comment commands to the project whose
+// name or ID is ref. The API has no lookup by name, so the projects are
+// listed.
+func (r *RemoteHandler) UseProject(ref string) {
+	if ref == "" {
+		return
+	}
+	var projects v2.ProjectList
+	if err := r.do(http.MethodGet, "/api/v2/projects", nil, "", &projects); err != nil {
+		log.Fatal().Err(err).Msg("Error listing projects")
+	}
+	for _, project := range projects.Items {
+		if project.ID.String() == ref || project.Name == ref {
+			r.tasksPath = "/api/v2/projects/" + project.ID.String() + "/tasks"
+			return
+		}
+	}
+	log.Fatal().Str("project", ref).Msg("Cannot find project")
+}
+
+func (r *RemoteHandler) ListProjects() {
+	var projects v2.ProjectList
+	if err := r.do(http.MethodGet, "/api/v2/projects", nil, "", &projects); err != nil {
+		log.Err(err).Msg("Error listing projects")
+		return
+	}
+	FormatProjectOutput(projects.Items, loadSettings().Project)
+}
+
+// AddProject creates a project through the API, with an Idempotency-Key
+// like AddTask.
+func (r *RemoteHandler) AddProject(req model.CreateProjectRequest) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.Err(err).Msg("Error creating project")
+		return
+	}
+	var created model.Project
+	if err := r.do(http.MethodPost, "/api/v2/projects", body, uuid.NewString(), &created); err != nil {
+		log.Err(err).Msg("Error creating project")
+		return
+	}
+	FormatProjectOutput([]model.Project{created}, loadSettings().Project)
+}
+
+// AddTask creates a task through the API. The request carries an
+// Idempotency-Key, so it is retried after timeouts and server errors
+// without risking a duplicate task.
+func (r *RemoteHandler) AddTask(name, description string) {
+	body, err := json.Marshal(model.TaskRequest{Name: name, Description: description})
+	if err != nil {
+		log.Err(err).Msg("Error creating task")
+		return
+	}
+	var created model.Task
+	if err := r.do(http.MethodPost, r.tasksPath, body, uuid.NewString(), &created); err != nil {
+		log.Err(err).Msg("Error creating task")
+		return
+	}
+	FormatListOutput([]model.Task{created})
+}
+
+func (r *RemoteHandler) ListTask(filter service.LabelFilter) {
+	path := r.tasksPath
+	if len(filter.Labels) > 0 {
+		path += "?" + url.Values{"label": filter.Labels, "match": {string(filter.Match)}}.Encode()
+	}
+	var tasks v2.TaskList
+	if err := r.do(http.MethodGet, path, nil, "", &tasks); err != nil {
+		log.Err(err).Msg("Error Listing Task")
+		return
+	}
+	FormatListOutput(tasks.Items)
+}
+
+// AddComment comments on the task through the API, with an
+// Idempotency-Key like AddTask.
+func (r *RemoteHandler) AddComment(taskID uuid.UUID, author, body string) {
+	payload, err := json.Marshal(model.CreateCommentRequest{Author: author, Body: body})
+	if err != nil {
+		log.Err(err).Msg("Error creating comment")
+		return
+	}
+	var created model.Comment
+	if err := r.do(http.MethodPost, r.tasksPath+"/"+taskID.String()+"/comments", payload, uuid.NewString(), &created); err != nil {
+		log.Err(err).Msg("Error creating comment")
+		return
+	}
+	FormatCommentOutput([]model.Comment{created})
+}
+
+func (r *RemoteHandler) ListComments(taskID uuid.UUID) {
+	var comments v2.CommentList
+	if err := r.do(http.MethodGet, r.tasksPath+"/"+taskID.String()+"/comments", nil, "", &comments); err != nil {
+		log.Err(err).Msg("Error listing comments")
+		return
+	}
+	FormatCommentOutput(comments.Items)
+}
+
+// do sends the request and decodes the response into out. Network
+// errors, 429 and 5xx responses are retried when the request is safe to
+// repeat: GETs, and anything sent with an idempotency key.
+func (r *RemoteHandler) do(method, path string, body []byte, idempotencyKey string, out any) error {
+	retryable := method == http.MethodGet || idempotencyKey != ""
+	delay := 500 * time.Millisecond
+	var lastErr error
+	for attempt := 1; attempt <= remoteAttempts; attempt++ {
+		if attempt > 1 {
+			log.Warn().Err(lastErr).Int("attempt", attempt).Dur("delay", delay).Msg("Retrying request")
+			time.Sleep(delay)
+			delay *= 2
+		}
+		req, err := http.NewRequestWithContext(context.Background(), method, r.baseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json, "+response.ProblemContentType)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, idempotencyKey)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			lastErr = err
+			if !retryable {
+				return err
+			}
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = fmt.Errorf("%s %s: %s", method, path, resp.Status)
+			if !retryable {
+				return lastErr
+			}
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				delay = min(time.Duration(seconds)*time.Second, maxRetryDelay)
+			}
+			continue
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			var problem response.Problem
+			json.Unmarshal(data, &problem)
+			return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, problem.Detail)
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%s %s: unexpected response: %w", method, path, err)
+		}
+		return nil
+	}
+	return lastErr
+}
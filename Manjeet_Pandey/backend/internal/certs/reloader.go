@@ -0,0 +1,190 @@
+// Package certs builds the API server's TLS configuration and keeps it in
+// sync with the certificate files on disk, so rotated certificates are
+// picked up without a restart.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
+// reloadDelay lets a rotation that rewrites several files settle before
+// they are read.
+const reloadDelay = 500 * time.Millisecond
+
+// Options names the files the server's TLS configuration is read from.
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS: client certificates are verified
+	// against the CAs it contains.
+	ClientCAFile string
+	// ClientAuth is require (the default) to reject clients without a
+	// certificate, or optional to only verify the ones presented.
+	ClientAuth string
+}
+
+type material struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// Reloader serves the most recently loaded certificate and client CAs.
+// When a reload fails, the previous files stay in use.
+type Reloader struct {
+	opts       Options
+	clientAuth tls.ClientAuthType
+
+	mu      sync.RWMutex
+	current material
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewReloader loads the files named by opts. Call Watch to follow changes.
+func NewReloader(opts Options) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("both a certificate and a key file are required")
+	}
+	if opts.ClientCAFile == "" && opts.ClientAuth != "" {
+		return nil, errors.New("client auth needs a client CA file")
+	}
+	r := &Reloader{opts: opts, clientAuth: tls.NoClientCert}
+	if opts.ClientCAFile != "" {
+		switch opts.ClientAuth {
+		case "", ClientAuthRequire:
+			r.clientAuth = tls.RequireAndVerifyClientCert
+		case ClientAuthOptional:
+			r.clientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unknown client auth %q: must be require or optional", opts.ClientAuth)
+		}
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CA files again.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	next := material{cert: &cert}
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("load client CA: %w", err)
+		}
+		next.clientCAs = x509.NewCertPool()
+		if !next.clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("load client CA: no certificates found in %s", r.opts.ClientCAFile)
+		}
+	}
+	r.mu.Lock()
+	r.current = next
+	r.mu.Unlock()
+	return nil
+}
+
+// TLSConfig returns a server configuration that always uses the latest
+// certificate and client CAs.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			current := r.current
+			r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*current.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    current.clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// Watch reloads the files whenever they change until Close is called. The
+// directories are watched rather than the files, so rotations that replace
+// the files or swap a symlink, as Kubernetes secret volumes do, are seen.
+func (r *Reloader) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dirs := map[string]bool{}
+	for _, file := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+	r.watcher = watcher
+	r.done = make(chan struct{})
+	go r.watch()
+	return nil
+}
+
+func (r *Reloader) watch() {
+	defer close(r.done)
+	var timer <-chan time.Time
+	for {
+		select {
+		case _, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			timer = time.After(reloadDelay)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Err(err).Msg("Error watching certificate files")
+		case <-timer:
+			timer = nil
+			if err := r.Reload(); err != nil {
+				log.Err(err).Msg("Cannot reload certificates, keeping the previous ones")
+				continue
+			}
+			log.Info().Str("cert_file", r.opts.CertFile).Msg("Reloaded certificates")
+		}
+	}
+}
+
+// Close stops watching for changes.
+func (r *Reloader) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+	err := r.watcher.Close()
+	<-r.done
+	return err
+}
@@ -0,0 +1,280 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"task_manager/internal/certs"
+	"task_manager/internal/logging"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	logging.Setup("disabled", "console")
+	os.Exit(m.Run())
+}
+
+// authority is a CA that issues certificates for the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) authority {
+	t.Helper()
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// issue returns a certificate named name for 127.0.0.1, and its key, as
+// PEM.
+func (a authority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key := newKey(t)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile replaces a file the way rotations do, so the reloader never
+// reads it half written.
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve runs an HTTPS server with the reloader's configuration.
+func serve(t *testing.T, r *certs.Reloader) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	srv.TLS = r.TLSConfig()
+	// Rejected handshakes are expected.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// servedName connects to srv, trusting ca, and returns the name on the
+// certificate it presents.
+func servedName(t *testing.T, srv *httptest.Server, ca authority) string {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{RootCAs: roots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestRotationIsPickedUp(t *testing.T) {
+	ca := newAuthority(t, "server CA")
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	cert, key := ca.issue(t, "first", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert)
+	writeFile(t, keyFile, key)
+
+	r, err := certs.NewReloader(certs.Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	srv := serve(t, r)
+	if name := servedName(t, srv, ca); name != "first" {
+		t.Fatalf("serving %q, want first", name)
+	}
+
+	cert, key = ca.issue(t, "second", x509.ExtKeyUsageServerAuth)
+	writeFile(t, keyFile, key)
+	writeFile(t, certFile, cert)
+	deadline := time.Now().Add(5 * time.Second)
+	for servedName(t, srv, ca) != "second" {
+		if time.Now().After(deadline) {
+			t.Fatal("the rotated certificate wasn't picked up")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestBadPairKeepsPreviousCertificate(t *testing.T) {
+	ca := newAuthority(t, "server CA")
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	cert, key := ca.issue(t, "good", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert)
+	writeFile(t, keyFile, key)
+
+	r, err := certs.NewReloader(certs.Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	srv := serve(t, r)
+
+	// A new certificate next to the old key doesn't make a pair.
+	mismatched, _ := ca.issue(t, "mismatched", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, mismatched)
+	if err := r.Reload(); err == nil {
+		t.Fatal("reloading a mismatched pair succeeded")
+	}
+	// Give the watcher time to try too.
+	time.Sleep(time.Second)
+	if name := servedName(t, srv, ca); name != "good" {
+		t.Errorf("serving %q after a bad rotation, want good", name)
+	}
+
+	writeFile(t, certFile, []byte("not a certificate"))
+	if err := r.Reload(); err == nil {
+		t.Fatal("reloading garbage succeeded")
+	}
+	if name := servedName(t, srv, ca); name != "good" {
+		t.Errorf("serving %q after a bad rotation, want good", name)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	serverCA := newAuthority(t, "server CA")
+	clientCA := newAuthority(t, "client CA")
+	foreignCA := newAuthority(t, "foreign CA")
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	cert, key := serverCA.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert)
+	writeFile(t, keyFile, key)
+	writeFile(t, caFile, clientCA.pem)
+
+	trusted := clientPair(t, clientCA)
+	foreign := clientPair(t, foreignCA)
+	for _, tc := range []struct {
+		clientAuth string
+		cert       *tls.Certificate
+		ok         bool
+	}{
+		{certs.ClientAuthRequire, trusted, true},
+		{certs.ClientAuthRequire, foreign, false},
+		{certs.ClientAuthRequire, nil, false},
+		{certs.ClientAuthOptional, trusted, true},
+		{certs.ClientAuthOptional, foreign, false},
+		{certs.ClientAuthOptional, nil, true},
+	} {
+		name := "none"
+		if tc.cert != nil {
+			name = tc.cert.Leaf.Subject.CommonName
+		}
+		t.Run(tc.clientAuth+"/"+name, func(t *testing.T) {
+			r, err := certs.NewReloader(certs.Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: tc.clientAuth})
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := serve(t, r)
+			roots := x509.NewCertPool()
+			roots.AddCert(serverCA.cert)
+			config := &tls.Config{
+				RootCAs: roots,
+				// Send the certificate whatever CAs the server asks for, so the server is
+				// the one to reject a foreign certificate.
+				GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					if tc.cert == nil {
+						return &tls.Certificate{}, nil
+					}
+					return tc.cert, nil
+				},
+			}
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+			// With TLS 1.3 the server checks the client certificate after
+			// the client's handshake is done, so only a request tells.
+			resp, err := client.Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if ok := err == nil; ok != tc.ok {
+				t.Errorf("request succeeded: %v, want %v (%v)", ok, tc.ok, err)
+			}
+		})
+	}
+}
+
+// clientPair issues a client certificate named after its CA.
+func clientPair(t *testing.T, ca authority) *tls.Certificate {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, "client of "+ca.cert.Subject.CommonName, x509.ExtKeyUsageClientAuth)
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pair
+}
@@ -0,0 +1,284 @@
+// Package config reads runtime settings from TASK_MANAGER_* environment
+// variables. Unset variables fall back to defaults suitable for local use.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "TASK_MANAGER_"
+
+// defaultAttachmentTypes covers screenshots, logs and the usual archives
+// and documents.
+const defaultAttachmentTypes = "image/png,image/jpeg,image/gif,image/webp,text/plain,text/csv,application/json,application/pdf,application/zip,application/x-gzip"
+
+type Config struct {
+	// LogLevel is a zerolog level name: trace, debug, info, warn or error.
+	LogLevel string
+	// LogFormat is json for machine readable logs or console for humans.
+	LogFormat string
+	// TraceExporter is where spans go: none, stdout or otlp. The OTLP
+	// endpoint is read from the standard OTEL_EXPORTER_OTLP_* variables.
+	TraceExporter string
+
+	// APIURL switches the add and list commands to a running API server
+	// instead of the local database.
+	APIURL string
+
+	// HTTPAddr is the address the API listens on.
+	HTTPAddr string
+	// GRPCAddr is the address the gRPC API listens on; off disables it.
+	GRPCAddr string
+	// ReadHeaderTimeout bounds reading the request line and headers.
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout bounds reading the whole request, body included.
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing the response. Streaming endpoints such as
+	// /events and /tasks/export lift it for their own requests.
+	WriteTimeout time.Duration
+	// IdleTimeout is how long a keep-alive connection may sit unused.
+	IdleTimeout time.Duration
+	// MaxHeaderBytes caps the size of the request headers.
+	MaxHeaderBytes int
+	// TLSCertFile and TLSKeyFile switch the API to HTTPS, with HTTP/2.
+	// Both files are reloaded when they change.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile enables mutual TLS against the CAs it contains.
+	TLSClientCAFile string
+	// TLSClientAuth is require or optional; it only applies with a client CA.
+	TLSClientAuth string
+	// RateLimitRead, RateLimitWrite and RateLimitBulk limit each client
+	// on the read, write and bulk route groups.
+	RateLimitRead  RateLimit
+	RateLimitWrite RateLimit
+	RateLimitBulk  RateLimit
+	// TrustedProxies lists the IP addresses or CIDR ranges of the reverse
+	// proxies whose X-Forwarded-For header gives the client's address.
+	// Without any, clients are told apart by the connection's address.
+	TrustedProxies []string
+	// MaxBodyBytes caps request bodies; MaxImportBytes applies to
+	// /tasks/import instead.
+	MaxBodyBytes   int64
+	MaxImportBytes int64
+	// IdempotencyTTL is how long responses to requests carrying an
+	// Idempotency-Key are kept for replay.
+	IdempotencyTTL time.Duration
+	// APIV1Sunset is when version 1 of the API, and the unversioned routes
+	// that alias it, will be removed. It is announced in a Sunset header.
+	APIV1Sunset time.Time
+	// ShutdownTimeout is how long in-flight requests get to finish after
+	// SIGINT or SIGTERM before their connections are closed.
+	ShutdownTimeout time.Duration
+
+	// AttachmentStorage is where attachment contents are kept: local, in
+	// AttachmentDir, or s3, in the bucket described by the S3 fields.
+	AttachmentStorage string
+	AttachmentDir     string
+	// MaxAttachmentBytes caps the size of one attachment. AttachmentTypes
+	// lists the content types accepted, such as image/png or image/*.
+	MaxAttachmentBytes int64
+	AttachmentTypes    []string
+	// S3Endpoint is the host and port of AWS S3 or a compatible server
+	// such as MinIO. S3UseSSL connects to it over HTTPS.
+	S3Endpoint  string
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// Load reads the configuration. It fails when a variable is set to a value
+// that can't be parsed.
+func Load() (Config, error) {
+	var errs []error
+	cfg := Config{
+		LogLevel:      getString("LOG_LEVEL", "info"),
+		LogFormat:     getString("LOG_FORMAT", "json"),
+		TraceExporter: getString("TRACE_EXPORTER", "none"),
+
+		APIURL: getString("API_URL", ""),
+
+		HTTPAddr:          getString("HTTP_ADDR", ":8080"),
+		GRPCAddr:          getString("GRPC_ADDR", ":9090"),
+		ReadHeaderTimeout: getDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second, &errs),
+		ReadTimeout:       getDuration("HTTP_READ_TIMEOUT", 30*time.Second, &errs),
+		WriteTimeout:      getDuration("HTTP_WRITE_TIMEOUT", 30*time.Second, &errs),
+		IdleTimeout:       getDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute, &errs),
+		MaxHeaderBytes:    getInt("HTTP_MAX_HEADER_BYTES", 1<<20, &errs),
+		TLSCertFile:       getString("TLS_CERT_FILE", ""),
+		TLSKeyFile:        getString("TLS_KEY_FILE", ""),
+		TLSClientCAFile:   getString("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:     getString("TLS_CLIENT_AUTH", ""),
+		RateLimitRead:     getRateLimit("RATE_LIMIT_READ", "50/s:100", &errs),
+		RateLimitWrite:    getRateLimit("RATE_LIMIT_WRITE", "10/s:20", &errs),
+		RateLimitBulk:     getRateLimit("RATE_LIMIT_BULK", "30/m:5", &errs),
+		TrustedProxies:    getProxies("TRUSTED_PROXIES", &errs),
+		MaxBodyBytes:      int64(getInt("MAX_BODY_BYTES", 1<<20, &errs)),
+		MaxImportBytes:    int64(getInt("MAX_IMPORT_BYTES", 64<<20, &errs)),
+		IdempotencyTTL:    getDuration("IDEMPOTENCY_TTL", 24*time.Hour, &errs),
+		APIV1Sunset:       getTime("API_V1_SUNSET", &errs),
+		ShutdownTimeout:   getDuration("SHUTDOWN_TIMEOUT", 30*time.Second, &errs),
+
+		AttachmentStorage:  getString("ATTACHMENT_STORAGE", "local"),
+		AttachmentDir:      getString("ATTACHMENT_DIR", "attachments"),
+		MaxAttachmentBytes: int64(getInt("MAX_ATTACHMENT_BYTES", 25<<20, &errs)),
+		AttachmentTypes:    getList("ATTACHMENT_TYPES", defaultAttachmentTypes),
+		S3Endpoint:         getString("S3_ENDPOINT", ""),
+		S3Bucket:           getString("S3_BUCKET", ""),
+		S3Region:           getString("S3_REGION", ""),
+		S3AccessKey:        getString("S3_ACCESS_KEY", ""),
+		S3SecretKey:        getString("S3_SECRET_KEY", ""),
+		S3UseSSL:           getBool("S3_USE_SSL", true, &errs),
+	}
+	return cfg, errors.Join(errs...)
+}
+
+// RateLimit is a token bucket: up to Burst requests at once, refilled at
+// Requests per Period. The zero value means unlimited.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0
+}
+
+// ParseRateLimit reads "<requests>/<s|m|h>[:<burst>]", e.g. "10/s:20" or
+// "600/m". The burst defaults to the request count. "off" disables the limit.
+func ParseRateLimit(v string) (RateLimit, error) {
+	if v == "off" {
+		return RateLimit{}, nil
+	}
+	invalid := fmt.Errorf("%q is not a rate limit such as 10/s:20", v)
+	spec, burstSpec, hasBurst := strings.Cut(v, ":")
+	count, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RateLimit{}, invalid
+	}
+	var limit RateLimit
+	var err error
+	if limit.Requests, err = strconv.Atoi(count); err != nil || limit.Requests <= 0 {
+		return RateLimit{}, invalid
+	}
+	switch unit {
+	case "s":
+		limit.Period = time.Second
+	case "m":
+		limit.Period = time.Minute
+	case "h":
+		limit.Period = time.Hour
+	default:
+		return RateLimit{}, invalid
+	}
+	limit.Burst = limit.Requests
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burstSpec); err != nil || limit.Burst <= 0 {
+			return RateLimit{}, invalid
+		}
+	}
+	return limit, nil
+}
+
+func getString(key, fallback string) string {
+	if v, ok := os.LookupEnv(envPrefix + key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration, errs *[]error) time.Duration {
+	v := getString(key, "")
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		*errs = append(*errs, fmt.Errorf("%s%s: %q is not a duration such as 30s", envPrefix, key, v))
+		return fallback
+	}
+	return d
+}
+
+// getTime reads a date such as 2027-06-30, taken as midnight UTC, or an
+// RFC 3339 timestamp. Unset variables give the zero time.
+func getTime(key string, errs *[]error) time.Time {
+	v := getString(key, "")
+	if v == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	*errs = append(*errs, fmt.Errorf("%s%s: %q is not a date such as 2027-06-30", envPrefix, key, v))
+	return time.Time{}
+}
+
+func getInt(key string, fallback int, errs *[]error) int {
+	v := getString(key, "")
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		*errs = append(*errs, fmt.Errorf("%s%s: %q is not a positive integer", envPrefix, key, v))
+		return fallback
+	}
+	return n
+}
+
+func getBool(key string, fallback bool, errs *[]error) bool {
+	v := getString(key, "")
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s%s: %q is not true or false", envPrefix, key, v))
+		return fallback
+	}
+	return b
+}
+
+// getList reads a comma separated list, dropping empty entries.
+func getList(key, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(getString(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getProxies reads a comma separated list of IP addresses and CIDR ranges.
+func getProxies(key string, errs *[]error) []string {
+	proxies := getList(key, "")
+	for _, proxy := range proxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			*errs = append(*errs, fmt.Errorf("%s%s: %q is not an IP address or CIDR range", envPrefix, key, proxy))
+			return nil
+		}
+	}
+	return proxies
+}
+
+func getRateLimit(key, fallback string, errs *[]error) RateLimit {
+	limit, err := ParseRateLimit(getString(key, fallback))
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s%s: %w", envPrefix, key, err))
+		limit, _ = ParseRateLimit(fallback)
+	}
+	return limit
+}
@@ -1,19 +1,51 @@
 package database
 
 import (
-	"task_manager/model"
+	"fmt"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// DSNOptions make concurrent writers, such as the worker and the webhook
+// dispatcher next to the requests, wait for each other instead of failing
+// with "database is locked". The write-ahead log lets readers and writers
+// run side by side, so a long read, such as an export streaming its rows,
+// doesn't hold up writes.
+const DSNOptions = "?_busy_timeout=5000&_txlock=immediate&_journal_mode=WAL"
+
 func InitDB() *gorm.DB {
-	db, err := gorm.Open(sqlite.Open("tasks.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Info)})
+	db, err := Open("tasks.db" + DSNOptions)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+		log.Fatal().Err(err).Msg("Failed to open the database")
 	}
-	db.AutoMigrate(&model.Task{})
 	return db
 }
+
+// Open opens the SQLite database at path and migrates it.
+func Open(path string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: zerologLogger{}, TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("connect to the database: %w", err)
+	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("install the tracing plugin: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		return nil, fmt.Errorf("migrate the database: %w", err)
+	}
+	return db, nil
+}
+
+// Close releases the connection pool so SQLite can checkpoint and release
+// its files. Call it once nothing uses db any more.
+func Close(db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		log.Err(err).Msg("Cannot close the database")
+	}
+}
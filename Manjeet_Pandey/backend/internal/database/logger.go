@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"task_manager/internal/logging"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration above which queries are logged as
+// warnings.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// zerologLogger sends GORM logs through the logger carried by the query
+// context, so SQL entries share the request ID and output format of the
+// rest of the application. Statements are logged at debug level.
+type zerologLogger struct{}
+
+func (l zerologLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l zerologLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	logging.Ctx(ctx).Info().Msgf(msg, data...)
+}
+
+func (l zerologLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	logging.Ctx(ctx).Warn().Msgf(msg, data...)
+}
+
+func (l zerologLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	logging.Ctx(ctx).Error().Msgf(msg, data...)
+}
+
+func (l zerologLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	log := logging.Ctx(ctx)
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		log.Error().Err(err).Str("sql", sql).Int64("rows", rows).Dur("elapsed", elapsed).Msg("Query failed")
+	case elapsed > slowQueryThreshold:
+		sql, rows := fc()
+		log.Warn().Str("sql", sql).Int64("rows", rows).Dur("elapsed", elapsed).Msg("Slow query")
+	default:
+		if e := log.Debug(); e.Enabled() {
+			sql, rows := fc()
+			e.Str("sql", sql).Int64("rows", rows).Dur("elapsed", elapsed).Msg("Query")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manager/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchemaVersion is the schema this build migrates the database to. Bump it
+// whenever a model change needs a migration.
+const SchemaVersion = 10
+
+// schemaVersion is the single row recording the last schema migrated to.
+type schemaVersion struct {
+	ID        uint `gorm:"primaryKey"`
+	Version   int  `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// migrate brings the schema up to date and records SchemaVersion once every
+// table has been migrated. A database already migrated by a newer build
+// keeps its version.
+func migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(&model.Task{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.WebhookEvent{}, &model.IdempotencyRecord{}, &model.Comment{}, &model.Activity{}, &model.Attachment{}, &model.Label{}, &model.Project{}, &schemaVersion{})
+	if err != nil {
+		return err
+	}
+	if err := migrateProjects(db); err != nil {
+		return err
+	}
+	applied, err := AppliedSchemaVersion(context.Background(), db)
+	if err != nil || applied >= SchemaVersion {
+		return err
+	}
+	return db.Save(&schemaVersion{ID: 1, Version: SchemaVersion, AppliedAt: time.Now()}).Error
+}
+
+// migrateProjects creates the default project and moves the tasks created
+// before projects existed into it.
+func migrateProjects(db *gorm.DB) error {
+	project := model.Project{ID: model.DefaultProjectID, Name: model.DefaultProjectName}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&project).Error; err != nil {
+		return err
+	}
+	return db.Exec("UPDATE tasks SET project_id = ? WHERE project_id IS NULL", model.DefaultProjectID).Error
+}
+
+// AppliedSchemaVersion returns the schema version recorded in the database,
+// or 0 when it has never been migrated.
+func AppliedSchemaVersion(ctx context.Context, db *gorm.DB) (int, error) {
+	var row schemaVersion
+	err := db.WithContext(ctx).First(&row, 1).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return row.Version, nil
+}
+
+// CheckReady reports whether the database answers and is migrated to at
+// least SchemaVersion.
+func CheckReady(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+	applied, err := AppliedSchemaVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("cannot read schema version: %w", err)
+	}
+	if applied < SchemaVersion {
+		return fmt.Errorf("schema version is %d, want %d", applied, SchemaVersion)
+	}
+	return nil
+}
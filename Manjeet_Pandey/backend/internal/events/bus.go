@@ -0,0 +1,129 @@
+// Package events is an in-process publish/subscribe bus for task changes.
+package events
+
+import (
+	"sync"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	TaskCreated    Type = "task.created"
+	TaskUpdated    Type = "task.updated"
+	TaskDeleted    Type = "task.deleted"
+	TaskProcessing Type = "task.processing"
+	TaskCompleted  Type = "task.completed"
+	TaskFailed     Type = "task.failed"
+	TasksImported  Type = "tasks.imported"
+)
+
+// subscriberBuffer is how many events a slow subscriber may lag behind
+// before further events are dropped for it.
+const subscriberBuffer = 64
+
+// Event describes a change to a task. Task holds the state after the change
+// and is nil for deletions and imports.
+type Event struct {
+	ID     uint64      `json:"id"`
+	Type   Type        `json:"type"`
+	TaskID uuid.UUID   `json:"task_id"`
+	Task   *model.Task `json:"task,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Time   time.Time   `json:"time"`
+}
+
+// Filter selects the events a subscriber receives. Empty fields match
+// everything. Status filters only match events that carry a task.
+type Filter struct {
+	Statuses []model.TaskStatus
+	TaskIDs  []uuid.UUID
+}
+
+func (f Filter) Match(e Event) bool {
+	if len(f.TaskIDs) > 0 && !contains(f.TaskIDs, e.TaskID) {
+		return false
+	}
+	if len(f.Statuses) > 0 && (e.Task == nil || !contains(f.Statuses, e.Task.Status)) {
+		return false
+	}
+	return true
+}
+
+func contains[T comparable](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription delivers matching events on C until Close is called.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+	once   sync.Once
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// Bus fans events out to subscribers. A nil *Bus is valid and discards
+// everything published to it.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	nextID uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish stamps the event and hands it to every matching subscriber
+// without blocking. Subscribers that have fallen behind miss the event.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	e.ID = b.nextID
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Task != nil && e.TaskID == uuid.Nil {
+		e.TaskID = e.Task.ID
+	}
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+func (b *Bus) Subscribe(filter Filter) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
@@ -0,0 +1,115 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"task_manager/internal/service"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// activityLoader loads the activity of tasks fetched together, such as a
+// page of them, the first time any of them is asked for it. Selecting the
+// activity of a page then costs two queries rather than two per task.
+type activityLoader struct {
+	service service.ActivityService
+	taskIDs []uuid.UUID
+
+	once    sync.Once
+	entries map[uuid.UUID][]model.Activity
+	err     error
+}
+
+func (l *activityLoader) load(ctx context.Context, taskID uuid.UUID) ([]model.Activity, error) {
+	l.once.Do(func() {
+		l.entries, l.err = l.service.ListActivityOf(ctx, l.taskIDs)
+	})
+	return l.entries[taskID], l.err
+}
+
+func (t *TaskResolver) Activity(ctx context.Context) ([]*ActivityResolver, error) {
+	entries, err := t.activity.load(ctx, t.task.ID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	resolvers := make([]*ActivityResolver, len(entries))
+	for i, entry := range entries {
+		resolvers[i] = &ActivityResolver{entry: entry}
+	}
+	return resolvers, nil
+}
+
+type ActivityResolver struct {
+	entry model.Activity
+}
+
+func (a *ActivityResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatUint(a.entry.ID, 10))
+}
+
+// Type turns "status_changed" into STATUS_CHANGED.
+func (a *ActivityResolver) Type() string {
+	return strings.ToUpper(string(a.entry.Type))
+}
+
+func (a *ActivityResolver) FromStatus() *string {
+	return optionalStatus(a.entry.FromStatus)
+}
+
+func (a *ActivityResolver) ToStatus() *string {
+	return optionalStatus(a.entry.ToStatus)
+}
+
+func (a *ActivityResolver) Comment() *CommentResolver {
+	if a.entry.Comment == nil {
+		return nil
+	}
+	return &CommentResolver{comment: *a.entry.Comment}
+}
+
+func (a *ActivityResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: a.entry.CreatedAt}
+}
+
+func optionalStatus(status model.TaskStatus) *string {
+	if status == "" {
+		return nil
+	}
+	s := string(status)
+	return &s
+}
+
+type CommentResolver struct {
+	comment model.Comment
+}
+
+func (c *CommentResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(c.comment.ID.String())
+}
+
+func (c *CommentResolver) Author() string {
+	return c.comment.Author
+}
+
+func (c *CommentResolver) Body() string {
+	return c.comment.Body
+}
+
+func (c *CommentResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: c.comment.CreatedAt}
+}
+
+func (c *CommentResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: c.comment.UpdatedAt}
+}
+
+func (c *CommentResolver) EditedAt() *graphqlgo.Time {
+	if c.comment.EditedAt == nil {
+		return nil
+	}
+	return &graphqlgo.Time{Time: *c.comment.EditedAt}
+}
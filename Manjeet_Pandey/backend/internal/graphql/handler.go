@@ -0,0 +1,274 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"task_manager/internal/logging"
+	"task_manager/internal/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed graphiql.html
+var graphiqlPage []byte
+
+// transportProtocol is the graphql-ws subprotocol used for subscriptions.
+const transportProtocol = "graphql-transport-ws"
+
+// keepAliveInterval is how often idle subscriptions are pinged so proxies
+// don't close them.
+const keepAliveInterval = 15 * time.Second
+
+// initTimeout is how long a client has to send connection_init.
+const initTimeout = 10 * time.Second
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{transportProtocol},
+	// The API already allows any origin through CORSMiddleware.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Handler struct {
+	schema   *graphqlgo.Schema
+	shutdown <-chan struct{}
+}
+
+// NewHandler serves schema. Open subscriptions end when shutdown is closed,
+// so they don't hold up a graceful server shutdown.
+func NewHandler(schema *graphqlgo.Schema, shutdown <-chan struct{}) Handler {
+	return Handler{schema: schema, shutdown: shutdown}
+}
+
+// QueryHandler executes a query or mutation sent as JSON.
+func (h *Handler) QueryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid GraphQL request"))
+			return
+		}
+		if req.Query == "" {
+			response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "query is required"))
+			return
+		}
+		c.JSON(http.StatusOK, h.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+	}
+}
+
+// PlaygroundHandler upgrades graphql-transport-ws connections for
+// subscriptions. Otherwise, when devMode is set, it serves GraphiQL.
+func (h *Handler) PlaygroundHandler(devMode bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if websocket.IsWebSocketUpgrade(c.Request) {
+			h.serveWebSocket(c)
+			return
+		}
+		if !devMode {
+			response.SendProblem(c, response.NewErrorResponse(http.StatusMethodNotAllowed, "Send GraphQL queries as POST requests"))
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", graphiqlPage)
+	}
+}
+
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// connection speaks graphql-transport-ws. Writes come from several
+// goroutines, one per operation, so they are serialised.
+type connection struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *connection) send(id, typ string, payload any) error {
+	msg := message{ID: id, Type: typ}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = raw
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *connection) control(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteControl(messageType, data, time.Now().Add(time.Second))
+}
+
+func (c *connection) close(code int, reason string) {
+	c.control(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+}
+
+func (h *Handler) serveWebSocket(c *gin.Context) {
+	log := logging.Ctx(c.Request.Context())
+	rc := http.NewResponseController(c.Writer)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Err(err).Msg("Error upgrading GraphQL websocket")
+		return
+	}
+	defer ws.Close()
+	conn := &connection{conn: ws}
+	if ws.Subprotocol() != transportProtocol {
+		conn.close(4406, "Subprotocol not acceptable")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// The read loop handles client messages; closed tells the write side the
+	// client has gone away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		h.readMessages(ctx, conn, ws)
+	}()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-h.shutdown:
+			conn.close(websocket.CloseGoingAway, "server shutting down")
+			return
+		case <-ticker.C:
+			if err := conn.control(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) readMessages(ctx context.Context, conn *connection, ws *websocket.Conn) {
+	log := logging.Ctx(ctx)
+	var (
+		mu          sync.Mutex
+		operations  = map[string]context.CancelFunc{}
+		initialised bool
+	)
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, cancel := range operations {
+			cancel()
+		}
+	}()
+
+	ws.SetReadDeadline(time.Now().Add(initTimeout))
+	for {
+		var msg message
+		if err := ws.ReadJSON(&msg); err != nil {
+			if !initialised {
+				conn.close(4408, "Connection initialisation timeout")
+			}
+			return
+		}
+		switch msg.Type {
+		case "connection_init":
+			if initialised {
+				conn.close(4429, "Too many initialisation requests")
+				return
+			}
+			initialised = true
+			ws.SetReadDeadline(time.Time{})
+			conn.send("", "connection_ack", nil)
+		case "ping":
+			conn.send("", "pong", nil)
+		case "pong":
+		case "subscribe":
+			if !initialised {
+				conn.close(4401, "Unauthorized")
+				return
+			}
+			var req request
+			if err := json.Unmarshal(msg.Payload, &req); err != nil || msg.ID == "" {
+				conn.close(4400, "Invalid subscribe message")
+				return
+			}
+			mu.Lock()
+			if _, ok := operations[msg.ID]; ok {
+				mu.Unlock()
+				conn.close(4409, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+			opCtx, cancel := context.WithCancel(ctx)
+			operations[msg.ID] = cancel
+			mu.Unlock()
+
+			go func(id string) {
+				defer func() {
+					mu.Lock()
+					delete(operations, id)
+					mu.Unlock()
+					cancel()
+				}()
+				if err := h.run(opCtx, conn, id, req); err != nil {
+					log.Err(err).Str("operation", id).Msg("Error writing GraphQL result")
+				}
+			}(msg.ID)
+		case "complete":
+			mu.Lock()
+			if cancel, ok := operations[msg.ID]; ok {
+				cancel()
+				delete(operations, msg.ID)
+			}
+			mu.Unlock()
+		default:
+			conn.close(4400, "Unknown message type "+msg.Type)
+			return
+		}
+	}
+}
+
+// run sends each result of an operation, then complete unless the client
+// completed it first.
+func (h *Handler) run(ctx context.Context, conn *connection, id string, req request) error {
+	results, err := h.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		return conn.send(id, "error", []map[string]string{{"message": err.Error()}})
+	}
+	for result := range results {
+		resp, ok := result.(*graphqlgo.Response)
+		if !ok {
+			continue
+		}
+		// Errors without data mean the operation never started.
+		if resp.Data == nil && len(resp.Errors) > 0 {
+			return conn.send(id, "error", resp.Errors)
+		}
+		if err := conn.send(id, "next", resp); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return conn.send(id, "complete", nil)
+}
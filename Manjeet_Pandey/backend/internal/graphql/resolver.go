@@ -0,0 +1,360 @@
+// Package graphql serves a GraphQL API over tasks at /graphql, backed by the
+// same TaskService as the REST API.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"task_manager/internal/logging"
+	"task_manager/internal/service"
+	"task_manager/internal/validation"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	maxDepth       = 10
+	maxParallelism = 10
+	maxQueryLength = 10000
+)
+
+// NewSchema parses the schema and binds it to the task and activity
+// services.
+func NewSchema(tasks service.TaskService, activity service.ActivityService) *graphqlgo.Schema {
+	return graphqlgo.MustParseSchema(schemaSDL, &Resolver{tasks: tasks, activity: activity},
+		graphqlgo.MaxDepth(maxDepth),
+		graphqlgo.MaxParallelism(maxParallelism),
+		graphqlgo.MaxQueryLength(maxQueryLength),
+		graphqlgo.Tracer(otel.DefaultTracer()),
+		graphqlgo.UseStringDescriptions(),
+	)
+}
+
+// Resolver is the root of the schema.
+type Resolver struct {
+	tasks    service.TaskService
+	activity service.ActivityService
+}
+
+// taskResolvers resolves tasks fetched together, so their activity is
+// loaded together too.
+func (r *Resolver) taskResolvers(tasks ...model.Task) []*TaskResolver {
+	loader := &activityLoader{service: r.activity}
+	resolvers := make([]*TaskResolver, len(tasks))
+	for i, task := range tasks {
+		loader.taskIDs = append(loader.taskIDs, task.ID)
+		resolvers[i] = &TaskResolver{task: task, activity: loader}
+	}
+	return resolvers
+}
+
+func (r *Resolver) taskResolver(task model.Task) *TaskResolver {
+	return r.taskResolvers(task)[0]
+}
+
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphqlgo.ID }) (*TaskResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := r.tasks.GetTask(ctx, id)
+	var notFound *service.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return r.taskResolver(*task), nil
+}
+
+type taskFilter struct {
+	Status *[]string
+	Search *string
+}
+
+func (r *Resolver) Tasks(ctx context.Context, args struct {
+	Filter *taskFilter
+	First  int32
+	After  *string
+}) (*TaskConnectionResolver, error) {
+	q := service.TaskQuery{Limit: int(args.First)}
+	if args.First < 0 || args.First > service.MaxPageSize {
+		return nil, invalidArgument(fmt.Sprintf("first must be between 0 and %d", service.MaxPageSize))
+	}
+	if args.After != nil {
+		offset, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		q.Offset = offset + 1
+	}
+	if f := args.Filter; f != nil {
+		if f.Status != nil {
+			for _, status := range *f.Status {
+				q.Statuses = append(q.Statuses, model.TaskStatus(status))
+			}
+		}
+		if f.Search != nil {
+			q.Search = *f.Search
+		}
+	}
+	if args.First == 0 {
+		// Only the count and page info were asked for.
+		q.Limit = 1
+	}
+	page, err := r.tasks.FindTasks(ctx, q)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	if args.First == 0 {
+		page.HasMore = len(page.Tasks) > 0
+		page.Tasks = nil
+	}
+	return &TaskConnectionResolver{page: page, nodes: r.taskResolvers(page.Tasks...), offset: q.Offset}, nil
+}
+
+type createTaskInput struct {
+	Name        string
+	Description *string
+	Status      *string
+}
+
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (*TaskResolver, error) {
+	task := model.Task{Name: args.Input.Name}
+	if args.Input.Description != nil {
+		task.Description = *args.Input.Description
+	}
+	if args.Input.Status != nil {
+		task.Status = model.TaskStatus(*args.Input.Status)
+	}
+	created, err := r.tasks.CreateTask(ctx, task)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return r.taskResolver(created), nil
+}
+
+type updateTaskInput struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+}
+
+// UpdateTask applies the fields that were given as a merge patch, so it is
+// validated like PATCH /tasks/{id}.
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Input updateTaskInput
+}) (*TaskResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(args.Input)
+	if err != nil {
+		return nil, err
+	}
+	patch, _ := service.NewPatch(service.MergePatchContentType, body)
+	updated, err := r.tasks.PatchTask(ctx, id, patch)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return r.taskResolver(updated), nil
+}
+
+func (r *Resolver) DeleteTask(ctx context.Context, args struct{ ID graphqlgo.ID }) (graphqlgo.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := r.tasks.DeleteTask(ctx, id); err != nil {
+		return "", resolverError(ctx, err)
+	}
+	return args.ID, nil
+}
+
+func (r *Resolver) CompleteTask(ctx context.Context, args struct{ ID graphqlgo.ID }) (*TaskResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := r.tasks.UpdateStatus(ctx, id, model.StatusCompleted)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return r.taskResolver(updated), nil
+}
+
+type TaskResolver struct {
+	task     model.Task
+	activity *activityLoader
+}
+
+func (t *TaskResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(t.task.ID.String())
+}
+
+func (t *TaskResolver) Name() string {
+	return t.task.Name
+}
+
+func (t *TaskResolver) Description() string {
+	return t.task.Description
+}
+
+func (t *TaskResolver) Status() string {
+	return string(t.task.Status)
+}
+
+func (t *TaskResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: t.task.CreatedAt}
+}
+
+func (t *TaskResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: t.task.UpdatedAt}
+}
+
+// TaskConnectionResolver resolves a page of tasks. Cursors encode the
+// position of a task in the filtered list.
+type TaskConnectionResolver struct {
+	page   service.TaskPage
+	nodes  []*TaskResolver
+	offset int
+}
+
+func (c *TaskConnectionResolver) Edges() []*TaskEdgeResolver {
+	edges := make([]*TaskEdgeResolver, len(c.nodes))
+	for i, node := range c.nodes {
+		edges[i] = &TaskEdgeResolver{cursor: encodeCursor(c.offset + i), node: node}
+	}
+	return edges
+}
+
+func (c *TaskConnectionResolver) Nodes() []*TaskResolver {
+	return c.nodes
+}
+
+func (c *TaskConnectionResolver) PageInfo() *PageInfoResolver {
+	info := &PageInfoResolver{hasNextPage: c.page.HasMore}
+	if n := len(c.page.Tasks); n > 0 {
+		cursor := encodeCursor(c.offset + n - 1)
+		info.endCursor = &cursor
+	}
+	return info
+}
+
+func (c *TaskConnectionResolver) TotalCount() int32 {
+	return int32(c.page.Total)
+}
+
+type TaskEdgeResolver struct {
+	cursor string
+	node   *TaskResolver
+}
+
+func (e *TaskEdgeResolver) Cursor() string {
+	return e.cursor
+}
+
+func (e *TaskEdgeResolver) Node() *TaskResolver {
+	return e.node
+}
+
+type PageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *PageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *PageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+const cursorPrefix = "task:"
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil && len(raw) > len(cursorPrefix) && string(raw[:len(cursorPrefix)]) == cursorPrefix {
+		if offset, err := strconv.Atoi(string(raw[len(cursorPrefix):])); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, invalidArgument("after is not a valid cursor")
+}
+
+func parseID(id graphqlgo.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, invalidArgument("Invalid task id")
+	}
+	return parsed, nil
+}
+
+// Error is a resolver error with a machine readable code, and the invalid
+// fields for validation errors, in its extensions.
+type Error struct {
+	Message string
+	Code    string
+	Fields  validation.Errors
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		ext["fields"] = e.Fields
+	}
+	return ext
+}
+
+func invalidArgument(msg string) *Error {
+	return &Error{Message: msg, Code: "BAD_USER_INPUT"}
+}
+
+// resolverError maps service errors onto error codes. Unrecognised errors
+// are reported without their details so internals don't leak.
+func resolverError(ctx context.Context, err error) error {
+	var (
+		notFound   *service.NotFoundError
+		invalid    *service.ValidationError
+		conflict   *service.ConflictError
+		fieldError validation.Errors
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return &Error{Message: notFound.Error(), Code: "NOT_FOUND"}
+	case errors.As(err, &invalid):
+		gqlErr := invalidArgument(invalid.Error())
+		if errors.As(err, &fieldError) {
+			gqlErr.Fields = fieldError
+		}
+		return gqlErr
+	case errors.As(err, &conflict):
+		return &Error{Message: conflict.Error(), Code: "CONFLICT"}
+	default:
+		logging.Ctx(ctx).Err(err).Msg("GraphQL resolver failed")
+		return &Error{Message: "Internal server error", Code: "INTERNAL_SERVER_ERROR"}
+	}
+}
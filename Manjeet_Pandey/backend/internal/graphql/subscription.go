@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"task_manager/internal/events"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+func (r *Resolver) TaskChanged(ctx context.Context, args struct {
+	Status *[]string
+	TaskID *[]graphqlgo.ID
+}) (<-chan *TaskEventResolver, error) {
+	var filter events.Filter
+	if args.Status != nil {
+		for _, status := range *args.Status {
+			filter.Statuses = append(filter.Statuses, model.TaskStatus(status))
+		}
+	}
+	if args.TaskID != nil {
+		for _, raw := range *args.TaskID {
+			id, err := parseID(raw)
+			if err != nil {
+				return nil, err
+			}
+			filter.TaskIDs = append(filter.TaskIDs, id)
+		}
+	}
+	bus := r.tasks.Events()
+	if bus == nil {
+		return nil, &Error{Message: "Task events are not available", Code: "UNAVAILABLE"}
+	}
+	sub := bus.Subscribe(filter)
+	out := make(chan *TaskEventResolver)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				select {
+				case out <- &TaskEventResolver{event: e, resolver: r}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+type TaskEventResolver struct {
+	event    events.Event
+	resolver *Resolver
+}
+
+func (e *TaskEventResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatUint(e.event.ID, 10))
+}
+
+// Type turns "task.created" into CREATED and "tasks.imported" into
+// IMPORTED.
+func (e *TaskEventResolver) Type() string {
+	_, name, _ := strings.Cut(string(e.event.Type), ".")
+	return strings.ToUpper(name)
+}
+
+func (e *TaskEventResolver) TaskID() *graphqlgo.ID {
+	if e.event.TaskID == uuid.Nil {
+		return nil
+	}
+	id := graphqlgo.ID(e.event.TaskID.String())
+	return &id
+}
+
+func (e *TaskEventResolver) Task() *TaskResolver {
+	if e.event.Task == nil {
+		return nil
+	}
+	return e.resolver.taskResolver(*e.event.Task)
+}
+
+func (e *TaskEventResolver) Error() *string {
+	if e.event.Error == "" {
+		return nil
+	}
+	return &e.event.Error
+}
+
+func (e *TaskEventResolver) Time() graphqlgo.Time {
+	return graphqlgo.Time{Time: e.event.Time}
+}
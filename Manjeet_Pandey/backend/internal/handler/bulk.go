@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkOperationResult is the outcome of one operation in a bulk request.
+type BulkOperationResult struct {
+	service.BulkResult
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkResponse summarises a bulk request. Committed is false when an atomic
+// batch was rolled back.
+type BulkResponse struct {
+	Committed bool                  `json:"committed"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BulkOperationResult `json:"results"`
+}
+
+// NewBulkResponse converts service results into their HTTP representation.
+func NewBulkResponse(atomic bool, results []service.BulkResult) BulkResponse {
+	resp := BulkResponse{Committed: true, Results: make([]BulkOperationResult, len(results))}
+	for i, result := range results {
+		out := BulkOperationResult{BulkResult: result, Status: http.StatusOK}
+		if result.Op == service.BulkCreate {
+			out.Status = http.StatusCreated
+		}
+		if result.Err != nil {
+			out.Status, out.Error = bulkErrorStatus(result.Err)
+			resp.Failed++
+			if atomic {
+				resp.Committed = false
+			}
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results[i] = out
+	}
+	return resp
+}
+
+func bulkErrorStatus(err error) (int, string) {
+	if errors.Is(err, service.ErrRolledBack) || errors.Is(err, service.ErrSkipped) {
+		return http.StatusFailedDependency, err.Error()
+	}
+	return errorStatus(err, "Operation failed")
+}
+
+// BulkTaskHandler runs a batch of task operations in one transaction.
+// @Summary      Run bulk task operations
+// @Description  Runs a batch of create, update, delete and status operations in a single transaction. With atomic set, any failure rolls back the whole batch; otherwise each operation succeeds or fails on its own.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        batch  body      service.BulkRequest  true  "Batch of operations"
+// @Success      200    {object}  response.Response{data=handler.BulkResponse}  "Per-operation results"
+// @Failure      400    {object}  response.Problem   "Invalid batch"
+// @Failure      500    {object}  response.Problem   "Failed to run batch"
+// @Router       /api/v1/tasks/bulk [post]
+// @ID BulkTasks
+func (t *TaskHandler) BulkTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req service.BulkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		results, err := t.taskService.Bulk(c.Request.Context(), req)
+		if err != nil {
+			logger(c).Err(err).Msg("Error running bulk operations")
+			sendError(c, err, "Failed to run batch")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, NewBulkResponse(req.Atomic, results)))
+	}
+}
@@ -0,0 +1,11 @@
+// Package handler implements version 1 of the HTTP API, served under
+// /api/v1 and, for existing clients, at the root. Responses are wrapped in
+// a {"status", "data"} envelope. The probe and problem type endpoints,
+// which aren't versioned, live here too.
+//
+// @title        Task Manager API
+// @version      1.0
+// @description  A simple task management API built with Go and Gin. Version 1 wraps every response in a {"status", "data"} envelope and is deprecated in favour of version 2.
+// @host         localhost:8080
+// @BasePath     /
+package handler
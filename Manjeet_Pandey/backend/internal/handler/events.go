@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"task_manager/internal/events"
+	"task_manager/internal/response"
+	"task_manager/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// keepAliveInterval is how often idle streams are pinged so proxies don't
+// close them.
+const keepAliveInterval = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	// The API already allows any origin through CORSMiddleware.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type EventsHandler struct {
+	bus      *events.Bus
+	shutdown <-chan struct{}
+}
+
+// NewEventsHandler streams events published on bus. Open streams end when
+// shutdown is closed, so they don't hold up a graceful server shutdown.
+func NewEventsHandler(bus *events.Bus, shutdown <-chan struct{}) EventsHandler {
+	return EventsHandler{bus: bus, shutdown: shutdown}
+}
+
+// ClearDeadlines lifts the server's read and write timeouts for requests
+// that legitimately stay open longer, such as streams and large transfers.
+func ClearDeadlines(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		logger(c).Debug().Err(err).Msg("Cannot clear the read deadline")
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger(c).Debug().Err(err).Msg("Cannot clear the write deadline")
+	}
+}
+
+// parseFilter reads repeated or comma separated status and task_id query
+// parameters.
+func parseFilter(c *gin.Context) (events.Filter, error) {
+	var filter events.Filter
+	for _, status := range splitQuery(c, "status") {
+		s := model.TaskStatus(status)
+		if err := s.Validate(); err != nil {
+			return events.Filter{}, err
+		}
+		filter.Statuses = append(filter.Statuses, s)
+	}
+	for _, raw := range splitQuery(c, "task_id") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return events.Filter{}, err
+		}
+		filter.TaskIDs = append(filter.TaskIDs, id)
+	}
+	return filter, nil
+}
+
+func splitQuery(c *gin.Context, key string) []string {
+	var values []string
+	for _, v := range c.QueryArray(key) {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// StreamHandler streams task events as Server-Sent Events.
+// @Summary      Stream task events
+// @Description  Streams task changes as Server-Sent Events. Each event is named after its type and carries an events.Event as JSON.
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        status   query     []string  false  "Only events for tasks in these statuses"  collectionFormat(multi)
+// @Param        task_id  query     []string  false  "Only events for these task IDs"  collectionFormat(multi)
+// @Success      200      {object}  events.Event  "Event stream"
+// @Failure      400      {object}  response.Problem   "Invalid filter"
+// @Router       /api/v1/events [get]
+// @ID StreamEvents
+func (h *EventsHandler) StreamHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseFilter(c)
+		if err != nil {
+			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		sub := h.bus.Subscribe(filter)
+		defer sub.Close()
+
+		ClearDeadlines(c)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no")
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case <-h.shutdown:
+				return false
+			case <-ticker.C:
+				_, err := io.WriteString(w, ": keep-alive\n\n")
+				return err == nil
+			case event, ok := <-sub.C:
+				if !ok {
+					return false
+				}
+				c.SSEvent(string(event.Type), event)
+				return true
+			}
+		})
+	}
+}
+
+// WebSocketHandler streams task events over a WebSocket.
+// @Summary      Stream task events over WebSocket
+// @Description  Upgrades to a WebSocket and sends each task change as a JSON events.Event text message.
+// @Tags         events
+// @Param        status   query     []string  false  "Only events for tasks in these statuses"  collectionFormat(multi)
+// @Param        task_id  query     []string  false  "Only events for these task IDs"  collectionFormat(multi)
+// @Success      101      {object}  events.Event  "Switching protocols"
+// @Failure      400      {object}  response.Problem   "Invalid filter"
+// @Router       /api/v1/ws [get]
+// @ID WatchEvents
+func (h *EventsHandler) WebSocketHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseFilter(c)
+		if err != nil {
+			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		ClearDeadlines(c)
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger(c).Err(err).Msg("Error upgrading websocket")
+			return
+		}
+		defer conn.Close()
+
+		sub := h.bus.Subscribe(filter)
+		defer sub.Close()
+
+		// The read loop only exists to notice the client going away.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-h.shutdown:
+				msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+					return
+				}
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if err := conn.WriteJSON(event); err != nil {
+					logger(c).Err(err).Msg("Error writing websocket event")
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"task_manager/internal/database"
+	"task_manager/internal/response"
+	"task_manager/internal/version"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// readyTimeout bounds the readiness check so a stuck database fails the
+// probe instead of hanging it.
+const readyTimeout = 2 * time.Second
+
+// HealthStatus is the body returned by the probe endpoints.
+type HealthStatus struct {
+	Status string `json:"status" example:"ok"`
+}
+
+type HealthHandler struct {
+	db *gorm.DB
+}
+
+func NewHealthHandler(db *gorm.DB) HealthHandler {
+	return HealthHandler{db: db}
+}
+
+// LivenessHandler reports that the process is running.
+// @Summary      Liveness probe
+// @Description  Succeeds as long as the server can answer requests. It doesn't touch the database.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  response.Response{data=handler.HealthStatus}  "Alive"
+// @Router       /healthz [get]
+// @ID Healthz
+func (h *HealthHandler) LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, HealthStatus{Status: "ok"}))
+	}
+}
+
+// ReadinessHandler reports whether the server can serve traffic.
+// @Summary      Readiness probe
+// @Description  Pings the database and checks that its schema is migrated to the version this build expects.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  response.Response{data=handler.HealthStatus}  "Ready"
+// @Failure      503  {object}  response.Problem   "Not ready, with the reason"
+// @Router       /readyz [get]
+// @ID Readyz
+func (h *HealthHandler) ReadinessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+		defer cancel()
+		if err := database.CheckReady(ctx, h.db); err != nil {
+			logger(c).Warn().Err(err).Msg("Not ready")
+			sendResponse(c, response.NewErrorResponse(http.StatusServiceUnavailable, err.Error()))
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, HealthStatus{Status: "ready"}))
+	}
+}
+
+// VersionHandler returns the build information.
+// @Summary      Build version
+// @Description  Returns the version, commit and build time injected at build time, and the database schema version the build expects.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  response.Response{data=version.Info}  "Build information"
+// @Router       /version [get]
+// @ID GetVersion
+func (h *HealthHandler) VersionHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, version.Get()))
+	}
+}
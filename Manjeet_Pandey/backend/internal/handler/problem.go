@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"task_manager/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemTypeHandler describes a problem type, so the type URIs in error
+// responses resolve to documentation.
+// @Summary      Describe a problem type
+// @Description  Returns the title and meaning of a problem type URI found in an error response.
+// @Tags         errors
+// @Produce      json
+// @Param        type  path      string  true  "Problem type, e.g. not-found"
+// @Success      200   {object}  response.Response{data=response.ProblemType}  "Problem type"
+// @Failure      404   {object}  response.Problem  "Unknown problem type"
+// @Router       /problems/{type} [get]
+// @ID GetProblemType
+func ProblemTypeHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		problemType, ok := response.ProblemTypes[c.Param("type")]
+		if !ok {
+			sendResponse(c, response.NewErrorResponse(http.StatusNotFound, "Unknown problem type"))
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, problemType))
+	}
+}
+
+// NotFoundHandler answers requests for unknown routes.
+func NotFoundHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sendResponse(c, response.NewErrorResponse(http.StatusNotFound, "No route for "+c.Request.Method+" "+c.Request.URL.Path))
+	}
+}
+
+// RecoveryHandler answers a request whose handler panicked. gin.CustomRecovery
+// has already logged the panic.
+func RecoveryHandler(c *gin.Context, _ any) {
+	response.Abort(c, http.StatusInternalServerError, "Internal server error")
+}
@@ -1,73 +1,185 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"task_manager/internal/logging"
 	"task_manager/internal/response"
 	"task_manager/internal/service"
+	"task_manager/internal/validation"
 	"task_manager/model"
+	"task_manager/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func sendResponse(c *gin.Context, resp response.Response) {
-	c.JSON(resp.Status, resp)
+	response.Send(c, resp)
+}
+
+// logger returns the request-scoped logger, which tags entries with the
+// request ID.
+func logger(c *gin.Context) *zerolog.Logger {
+	return logging.Ctx(c.Request.Context())
+}
+
+// sendError maps service errors onto HTTP status codes. Unrecognised errors
+// are reported as 500 with the given message so internals don't leak.
+func sendError(c *gin.Context, err error, msg string) {
+	sendResponse(c, ErrorResponse(err, msg))
+}
+
+// ErrorResponse describes a service error as an error response, with the
+// invalid fields of a validation error. Unrecognised errors become a 500
+// with msg.
+func ErrorResponse(err error, msg string) response.Response {
+	status, msg := errorStatus(err, msg)
+	resp := response.NewErrorResponse(status, msg)
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		resp.Errors = fields
+	}
+	return resp
+}
+
+func errorStatus(err error, msg string) (int, string) {
+	var (
+		notFound   *service.NotFoundError
+		validation *service.ValidationError
+		conflict   *service.ConflictError
+		tooLarge   *http.MaxBytesError
+		tooBig     *service.TooLargeError
+		badType    *service.UnsupportedTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large, the limit is %d bytes", tooLarge.Limit)
+	case errors.As(err, &tooBig):
+		return http.StatusRequestEntityTooLarge, tooBig.Error()
+	case errors.As(err, &badType):
+		return http.StatusUnsupportedMediaType, badType.Error()
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, notFound.Error()
+	case errors.As(err, &validation):
+		return http.StatusBadRequest, validation.Error()
+	case errors.As(err, &conflict):
+		return http.StatusConflict, conflict.Error()
+	default:
+		return http.StatusInternalServerError, msg
+	}
+}
+
+// sendBindError reports a request body that could not be read or decoded.
+func sendBindError(c *gin.Context, err error) {
+	logger(c).Err(err).Msg("Invalid payload")
+	sendResponse(c, BindErrorResponse(err))
+}
+
+// BindErrorResponse describes a request body that could not be read or
+// decoded. A value of the wrong type is reported as a field error.
+func BindErrorResponse(err error) response.Response {
+	if status, msg := errorStatus(err, ""); status == http.StatusRequestEntityTooLarge {
+		return response.NewErrorResponse(status, msg)
+	}
+	resp := response.NewErrorResponse(http.StatusBadRequest, err.Error())
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		msg := fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type.Kind())
+		resp.Error = msg
+		resp.Errors = validation.Errors{{Field: typeErr.Field, Rule: "type", Message: msg}}
+	}
+	return resp
+}
+
+// parseID reads the ID path parameter, writing a 400 response if it is not
+// a valid UUID.
+func parseID(c *gin.Context, resource string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid "+resource+" id"))
+		return uuid.Nil, false
+	}
+	return id, true
 }
 
 type TaskHandler struct {
 	taskService service.TaskService
+	workers     *util.Worker
 }
 
-func NewTaskHandler(service service.TaskService) TaskHandler {
+// NewTaskHandler serves tasks. ProcessTasksHandler hands the tasks it
+// claims to workers, which the server owns and shuts down.
+func NewTaskHandler(service service.TaskService, workers *util.Worker) TaskHandler {
 	return TaskHandler{
 		taskService: service,
+		workers:     workers,
 	}
 }
 
 // CreateTaskHandler creates a new task in the system.
 // @Summary      Create a new task
-// @Description  Creates a task with the provided name and status.
+// @Description  Creates a task with the provided name and status. Send an Idempotency-Key to make retries safe: a retry with the same key and body gets the original response, marked with Idempotent-Replayed.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
-// @Param        task  body      model.Task  true  "Task object to create"
-// @Success      201   {object}  response.Response{data=model.Task}  "Created task"
-// @Failure      400   {object}  response.Response  "Invalid request payload"
-// @Failure      500   {object}  response.Response  "Failed to create task"
-// @Router       /tasks [post]
+// @Param        task             body      model.TaskRequest        true   "Task to create"
+// @Param        Idempotency-Key  header    string      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  response.Response{data=model.Task}  "Created task"
+// @Failure      400              {object}  response.Problem   "Invalid request payload, with the invalid fields in errors"
+// @Failure      409              {object}  response.Problem   "Task already exists, or a request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem   "Request body too large"
+// @Failure      422              {object}  response.Problem   "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem   "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem   "Failed to create task"
+// @Router       /api/v1/tasks [post]
 // @ID CreateTask
 func (t *TaskHandler) CreateTaskHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var task model.Task
-		if err := c.ShouldBindJSON(&task); err != nil {
-			log.Err(err).Msg("Invalid payload")
-			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		var req model.TaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
 			return
 		}
-		if err := t.taskService.CreateTask(task); err != nil {
-			log.Err(err).Msg("Error creating task")
-			sendResponse(c, response.NewErrorResponse(http.StatusInternalServerError, "Failed to create task"))
+		created, err := t.taskService.CreateTask(c.Request.Context(), req.Task())
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating task")
+			sendError(c, err, "Failed to create task")
 			return
 		}
-		sendResponse(c, response.NewSuccessResponse(http.StatusCreated, task))
+		sendResponse(c, response.NewSuccessResponse(http.StatusCreated, created))
 	}
 }
 
-// GetTasksHandler retrieves all tasks from the system.
+// GetTasksHandler retrieves all tasks from the system, or those with the
+// given labels.
 // @Summary      List all tasks
-// @Description  Retrieves a list of all tasks stored in the database.
+// @Description  Retrieves a list of all tasks stored in the database, with their labels. With label, only tasks carrying every one of the named labels are listed, or any of them with match=any.
 // @Tags         tasks
 // @Produce      json
+// @Param        label  query     []string  false  "Only tasks with these labels, by name"  collectionFormat(multi)
+// @Param        match  query     string    false  "Whether tasks need all the labels or any of them"  Enums(all, any)  default(all)
 // @Success      200   {object}  response.Response{data=[]model.Task}  "List of tasks"
-// @Failure      500   {object}  response.Response  "Failed to retrieve tasks"
-// @Router       /tasks [get]
+// @Failure      400   {object}  response.Problem   "Invalid label filter"
+// @Failure      500   {object}  response.Problem   "Failed to retrieve tasks"
+// @Router       /api/v1/tasks [get]
 // @ID ListTasks
 func (t *TaskHandler) GetTasksHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tasks, err := t.taskService.ListTask()
+		filter, err := service.ParseLabelFilter(c.QueryArray("label"), c.Query("match"))
+		if err != nil {
+			sendError(c, err, "Invalid label filter")
+			return
+		}
+		tasks, err := t.taskService.ListTask(c.Request.Context(), filter)
 		if err != nil {
-			log.Err(err).Msg("Error retreiving tasks")
+			logger(c).Err(err).Msg("Error retreiving tasks")
 			sendResponse(c, response.NewErrorResponse(http.StatusInternalServerError, "Failed to retrieve tasks"))
 			return
 		}
@@ -82,84 +194,155 @@ func (t *TaskHandler) GetTasksHandler() gin.HandlerFunc {
 // @Produce      json
 // @Param        id   path      string       true  "Task ID (UUID)"
 // @Success      200  {object}  response.Response{data=model.Task}  "Task details"
-// @Failure      404  {object}  response.Response  "Task not found"
-// @Router       /tasks/{id} [get]
+// @Failure      400  {object}  response.Problem   "Invalid task id"
+// @Failure      404  {object}  response.Problem   "Task not found"
+// @Failure      500  {object}  response.Problem   "Failed to retrieve task"
+// @Router       /api/v1/tasks/{id} [get]
 // @ID GetTaskByID
 func (t *TaskHandler) GetTaskHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-
-		id, err := uuid.Parse(c.Param("id"))
-		if err != nil {
-			log.Err(err).Msg("Error parsing uuid")
-			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid task id"))
+		id, ok := parseID(c, "task")
+		if !ok {
+			return
 		}
-		task, err := t.taskService.GetTask(id)
+		task, err := t.taskService.GetTask(c.Request.Context(), id)
 		if err != nil {
-			log.Err(err).Msg("Task not found")
-			sendResponse(c, response.NewErrorResponse(http.StatusNotFound, "Task not found"))
+			logger(c).Err(err).Msg("Error retrieving task")
+			sendError(c, err, "Failed to retrieve task")
 			return
 		}
 		sendResponse(c, response.NewSuccessResponse(http.StatusOK, task))
 	}
 }
 
-// UpdateTaskHandler updates an existing task.
-// @Summary      Update a task
-// @Description  Updates the details of a task identified by its ID.
+// UpdateTaskHandler replaces an existing task.
+// @Summary      Replace a task
+// @Description  Replaces all writable fields of the task identified by its ID. Omitted fields are reset to their defaults.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
-// @Param        id    path      string       true  "Task ID (UUID)"
-// @Param        task  body      model.Task  true  "Updated task object"
+// @Param        id    path      string                   true  "Task ID (UUID)"
+// @Param        task  body      model.TaskRequest        true  "Updated task"
 // @Success      200   {object}  response.Response{data=model.Task}  "Updated task"
-// @Failure      400   {object}  response.Response  "Invalid request payload"
-// @Failure      404   {object}  response.Response  "Task not found"
-// @Failure      500   {object}  response.Response  "Failed to update task"
-// @Router       /tasks/{id} [put]
+// @Failure      400   {object}  response.Problem   "Invalid request payload, with the invalid fields in errors"
+// @Failure      404   {object}  response.Problem   "Task not found"
+// @Failure      500   {object}  response.Problem   "Failed to update task"
+// @Router       /api/v1/tasks/{id} [put]
 // @ID UpdateTask
 func (t *TaskHandler) UpdateTaskHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var task model.Task
-		id, err := uuid.Parse(c.Param("id"))
+		var req model.TaskRequest
+		id, ok := parseID(c, "task")
+		if !ok {
+			return
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		updated, err := t.taskService.ReplaceTask(c.Request.Context(), id, req.Task())
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to update Task")
+			sendError(c, err, "Failed to update task")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, updated))
+	}
+}
+
+// PatchTaskHandler partially updates an existing task.
+// @Summary      Patch a task
+// @Description  Applies an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON patch (application/json-patch+json) to the task identified by its ID.
+// @Tags         tasks
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        id     path      string  true  "Task ID (UUID)"
+// @Param        patch  body      object  true  "Merge patch or JSON patch document"
+// @Success      200    {object}  response.Response{data=model.Task}  "Patched task"
+// @Failure      400    {object}  response.Problem   "Invalid patch document, or a patched task with the invalid fields in errors"
+// @Failure      404    {object}  response.Problem   "Task not found"
+// @Failure      415    {object}  response.Problem   "Unsupported patch format"
+// @Failure      500    {object}  response.Problem   "Failed to patch task"
+// @Router       /api/v1/tasks/{id} [patch]
+// @ID PatchTask
+func (t *TaskHandler) PatchTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "task")
+		if !ok {
+			return
+		}
+		body, err := c.GetRawData()
 		if err != nil {
-			log.Err(err).Msg("Error parsing uuid")
-			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid task id"))
+			sendBindError(c, err)
+			return
 		}
-		if err := c.ShouldBindJSON(&task); err != nil {
-			log.Err(err).Msg("Error binding payload")
-			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		patch, ok := service.NewPatch(c.ContentType(), body)
+		if !ok {
+			sendResponse(c, response.NewErrorResponse(http.StatusUnsupportedMediaType, "Unsupported patch format"))
 			return
 		}
-		if err := t.taskService.UpdateTask(id, task); err != nil {
-			log.Err(err).Msg("Failed to update Task")
-			sendResponse(c, response.NewErrorResponse(http.StatusInternalServerError, "Failed to update task"))
+		task, err := t.taskService.PatchTask(c.Request.Context(), id, patch)
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to patch task")
+			sendError(c, err, "Failed to patch task")
 			return
 		}
 		sendResponse(c, response.NewSuccessResponse(http.StatusOK, task))
 	}
 }
 
+// ProcessTasksHandler claims every pending task and queues it for the
+// server's worker pool. Tasks already claimed by an earlier call are left
+// out, so concurrent calls never process a task twice.
+// @Summary      Process pending tasks
+// @Description  Claims every pending task not already being processed, queues it for the in-process workers and returns immediately. Tasks claimed by an earlier call are left out. Progress is published on /events and /ws.
+// @Tags         tasks
+// @Produce      json
+// @Success      202  {object}  response.Response{data=[]model.Task}  "Queued tasks"
+// @Failure      500  {object}  response.Problem   "Failed to queue tasks"
+// @Router       /api/v1/tasks/process [post]
+// @ID ProcessTasks
+func (t *TaskHandler) ProcessTasksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, err := t.taskService.ClaimPendingTasks(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Cannot claim Pending Task")
+			sendError(c, err, "Failed to queue tasks")
+			return
+		}
+		// The request context is cancelled once the response is written; the
+		// jobs only keep its span context so their spans join this trace.
+		queued := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(c.Request.Context()))
+		if !t.workers.Submit(queued, tasks) {
+			// The claims lapse after service.ClaimTimeout.
+			sendResponse(c, response.NewErrorResponse(http.StatusServiceUnavailable, "The server is shutting down"))
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusAccepted, tasks))
+	}
+}
+
 // DeleteTaskHandler deletes a task by ID.
 // @Summary      Delete a task
 // @Description  Deletes a task identified by its unique identifier.
 // @Tags         tasks
 // @Produce      json
 // @Param        id   path      string       true  "Task ID (UUID)"
-// @Success      204  {object}  response.Response  "Task deleted successfully"
-// @Failure      404  {object}  response.Response  "Task not found"
-// @Failure      500  {object}  response.Response  "Failed to delete task"
-// @Router       /tasks/{id} [delete]
+// @Success      200  {object}  response.Response  "Task deleted successfully"
+// @Failure      400  {object}  response.Problem   "Invalid task id"
+// @Failure      404  {object}  response.Problem   "Task not found"
+// @Failure      500  {object}  response.Problem   "Failed to delete task"
+// @Router       /api/v1/tasks/{id} [delete]
 // @ID DeleteTask
 func (t *TaskHandler) DeleteTaskHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := uuid.Parse(c.Param("id"))
-		if err != nil {
-			log.Err(err).Msg("Error parsing uuid")
-			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid task id"))
+		id, ok := parseID(c, "task")
+		if !ok {
+			return
 		}
-		if err := t.taskService.DeleteTask(id); err != nil {
-			log.Err(err).Msg("Failed to delete task")
-			sendResponse(c, response.NewErrorResponse(http.StatusInternalServerError, "Failed to delete task"))
+		if err := t.taskService.DeleteTask(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete task")
+			sendError(c, err, "Failed to delete task")
 			return
 		}
 		sendResponse(c, response.NewSuccessResponse(http.StatusOK, "Deleted successfully"))
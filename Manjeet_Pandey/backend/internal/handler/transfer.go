@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/internal/transfer"
+	"task_manager/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportTasksHandler streams every task in the requested format.
+// @Summary      Export tasks
+// @Description  Streams every task, including IDs and timestamps, as a JSON array, CSV or newline-delimited JSON.
+// @Tags         tasks
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        format  query     string  false  "Output format"  Enums(json, csv, ndjson)  default(json)
+// @Success      200     {array}   model.Task  "Exported tasks"
+// @Failure      400     {object}  response.Problem   "Unknown format"
+// @Router       /api/v1/tasks/export [get]
+// @ID ExportTasks
+func (t *TaskHandler) ExportTasksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, err := transfer.ParseFormat(c.DefaultQuery("format", string(transfer.FormatJSON)))
+		if err != nil {
+			sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		ClearDeadlines(c)
+		c.Header("Content-Type", format.ContentType())
+		c.Header("Content-Disposition", `attachment; filename="tasks.`+string(format)+`"`)
+		c.Status(http.StatusOK)
+
+		enc := transfer.NewEncoder(format, c.Writer)
+		err = t.taskService.ExportTasks(c.Request.Context(), func(task model.Task) error {
+			return enc.Encode(task)
+		})
+		if err == nil {
+			err = enc.Close()
+		}
+		if err != nil {
+			// Headers are already sent, so the client only sees a truncated body.
+			logger(c).Err(err).Msg("Error exporting tasks")
+		}
+	}
+}
+
+// ImportTasksHandler loads tasks streamed in the request body.
+// @Summary      Import tasks
+// @Description  Loads tasks from a JSON array, CSV or newline-delimited JSON body, 500 tasks per transaction. A failed import keeps the tasks written before the failing record. IDs and timestamps are kept. The format defaults to the request content type.
+// @Tags         tasks
+// @Accept       json,text/csv,application/x-ndjson
+// @Produce      json
+// @Param        format       query     string  false  "Input format"  Enums(json, csv, ndjson)
+// @Param        on_conflict  query     string  false  "What to do with tasks whose ID already exists"  Enums(fail, skip, update)  default(fail)
+// @Param        tasks        body      []model.Task  true  "Tasks to import"
+// @Success      200          {object}  response.Response{data=service.ImportSummary}  "Import summary"
+// @Failure      400          {object}  response.Problem   "Invalid input"
+// @Failure      409          {object}  response.Problem   "Task already exists"
+// @Failure      500          {object}  response.Problem   "Failed to import tasks"
+// @Router       /api/v1/tasks/import [post]
+// @ID ImportTasks
+func (t *TaskHandler) ImportTasksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := transfer.FormatFromContentType(c.ContentType())
+		if name := c.Query("format"); name != "" {
+			var err error
+			if format, err = transfer.ParseFormat(name); err != nil {
+				sendResponse(c, response.NewErrorResponse(http.StatusBadRequest, err.Error()))
+				return
+			}
+		}
+		mode, err := service.ParseConflictMode(c.Query("on_conflict"))
+		if err != nil {
+			sendError(c, err, "Invalid conflict mode")
+			return
+		}
+
+		ClearDeadlines(c)
+		dec := transfer.NewDecoder(format, c.Request.Body)
+		summary, err := t.taskService.ImportTasks(c.Request.Context(), dec.Decode, mode)
+		if err != nil {
+			logger(c).Err(err).Msg("Error importing tasks")
+			sendError(c, err, "Failed to import tasks")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, summary))
+	}
+}
@@ -0,0 +1,403 @@
+package v2
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"task_manager/internal/handler"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// attachmentField is the multipart form field carrying the file.
+const attachmentField = "file"
+
+// inlineTypes are shown by the browser; every other type is downloaded.
+var inlineTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// AttachmentList is the body of an attachment listing.
+type AttachmentList struct {
+	Items []model.Attachment `json:"items"`
+}
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(attachments service.AttachmentService) AttachmentHandler {
+	return AttachmentHandler{attachmentService: attachments}
+}
+
+// attachments returns the attachment service scoped to the project of the
+// request, writing a 400 response if its ID is invalid.
+func (h *AttachmentHandler) attachments(c *gin.Context) (service.AttachmentService, bool) {
+	projectID, ok := projectOf(c)
+	return h.attachmentService.InProject(projectID), ok
+}
+
+// parseAttachmentID reads the attachmentId path parameter, writing a 400
+// response if it is not a valid UUID.
+func parseAttachmentID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid attachment id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateAttachmentHandler uploads a file and attaches it to a task.
+// @Summary      Attach a file to a task
+// @Description  Streams the file in the file field of a multipart/form-data body to storage. The content type is detected from the contents and must be one of the accepted types; the SHA-256 digest is computed on the way. Idempotency-Key is not honoured here.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Task ID (UUID)"
+// @Param        file  formData  file    true  "File to attach"
+// @Success      201   {object}  model.Attachment  "Created attachment"
+// @Header       201   {string}  Location          "URL of the new attachment"
+// @Failure      400   {object}  response.Problem  "Invalid task id, or no file in the body"
+// @Failure      404   {object}  response.Problem  "Task not found"
+// @Failure      413   {object}  response.Problem  "File too large"
+// @Failure      415   {object}  response.Problem  "Not a multipart body, or a file type that isn't accepted"
+// @Failure      429   {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500   {object}  response.Problem  "Failed to store attachment"
+// @Router       /api/v2/tasks/{id}/attachments [post]
+// @ID CreateAttachment
+func (h *AttachmentHandler) CreateAttachmentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attachments, ok := h.attachments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			logger(c).Err(err).Msg("Invalid payload")
+			response.SendProblem(c, response.NewErrorResponse(http.StatusUnsupportedMediaType, "Expected a multipart/form-data body"))
+			return
+		}
+		handler.ClearDeadlines(c)
+		part, err := filePart(reader)
+		if err != nil {
+			sendBindError(c, err)
+			return
+		}
+		defer part.Close()
+		created, err := attachments.CreateAttachment(c.Request.Context(), taskID, part.FileName(), part.Header.Get("Content-Type"), part)
+		if err != nil {
+			logger(c).Err(err).Msg("Error storing attachment")
+			sendError(c, err, "Failed to store attachment")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+created.ID.String())
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// filePart skips to the part holding the file.
+func filePart(reader *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the body has no " + attachmentField + " field")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == attachmentField {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// ListAttachmentsHandler lists the attachments of a task.
+// @Summary      List the attachments of a task
+// @Description  Retrieves the metadata of the files attached to the task, oldest first.
+// @Tags         attachments
+// @Produce      json
+// @Param        id   path      string          true  "Task ID (UUID)"
+// @Success      200  {object}  AttachmentList  "Attachments"
+// @Failure      400  {object}  response.Problem  "Invalid task id"
+// @Failure      404  {object}  response.Problem  "Task not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve attachments"
+// @Router       /api/v2/tasks/{id}/attachments [get]
+// @ID ListAttachments
+func (h *AttachmentHandler) ListAttachmentsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attachments, ok := h.attachments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		list, err := attachments.ListAttachments(c.Request.Context(), taskID)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving attachments")
+			sendError(c, err, "Failed to retrieve attachments")
+			return
+		}
+		if list == nil {
+			list = []model.Attachment{}
+		}
+		c.JSON(http.StatusOK, AttachmentList{Items: list})
+	}
+}
+
+// GetAttachmentHandler returns the metadata of an attachment.
+// @Summary      Get an attachment
+// @Description  Retrieves the metadata of a file attached to the task: its name, detected content type, size and SHA-256 digest.
+// @Tags         attachments
+// @Produce      json
+// @Param        id            path      string  true  "Task ID (UUID)"
+// @Param        attachmentId  path      string  true  "Attachment ID (UUID)"
+// @Success      200  {object}  model.Attachment  "Attachment"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Attachment not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve attachment"
+// @Router       /api/v2/tasks/{id}/attachments/{attachmentId} [get]
+// @ID GetAttachment
+func (h *AttachmentHandler) GetAttachmentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attachments, ok := h.attachments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseAttachmentID(c)
+		if !ok {
+			return
+		}
+		attachment, err := attachments.GetAttachment(c.Request.Context(), taskID, id)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving attachment")
+			sendError(c, err, "Failed to retrieve attachment")
+			return
+		}
+		c.JSON(http.StatusOK, attachment)
+	}
+}
+
+// DownloadAttachmentHandler streams the contents of an attachment.
+// @Summary      Download an attachment
+// @Description  Streams the file with its detected content type. Range requests are supported, as are If-None-Match and If-Range against the ETag, which is the SHA-256 digest. Images are shown inline; other types are downloaded.
+// @Tags         attachments
+// @Produce      */*
+// @Param        id            path      string  true   "Task ID (UUID)"
+// @Param        attachmentId  path      string  true   "Attachment ID (UUID)"
+// @Param        Range         header    string  false  "Byte range to return, e.g. bytes=0-1023"
+// @Success      200  {file}    file    "Attachment contents"
+// @Header       200  {string}  ETag         "Quoted hex SHA-256 digest of the contents"
+// @Header       200  {string}  Repr-Digest  "RFC 9530 SHA-256 digest of the contents"
+// @Success      206  {file}    file    "The requested range"
+// @Success      304  "Not modified"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Attachment not found"
+// @Failure      416  {string}  string  "Range not satisfiable"
+// @Failure      500  {object}  response.Problem  "Failed to read attachment"
+// @Router       /api/v2/tasks/{id}/attachments/{attachmentId}/content [get]
+// @ID DownloadAttachment
+func (h *AttachmentHandler) DownloadAttachmentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attachments, ok := h.attachments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseAttachmentID(c)
+		if !ok {
+			return
+		}
+		attachment, contents, err := attachments.OpenAttachment(c.Request.Context(), taskID, id)
+		if err != nil {
+			logger(c).Err(err).Msg("Error reading attachment")
+			sendError(c, err, "Failed to read attachment")
+			return
+		}
+		defer contents.Close()
+		handler.ClearDeadlines(c)
+
+		header := c.Writer.Header()
+		header.Set("Content-Type", attachment.ContentType)
+		disposition := "attachment"
+		if inlineTypes[attachment.ContentType] {
+			disposition = "inline"
+		}
+		if value := mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}); value != "" {
+			disposition = value
+		}
+		header.Set("Content-Disposition", disposition)
+		header.Set("ETag", `"`+attachment.SHA256+`"`)
+		if digest, err := hex.DecodeString(attachment.SHA256); err == nil {
+			header.Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest)+":")
+		}
+		// Uploaded files are never run as a page of this origin.
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Content-Security-Policy", "sandbox")
+		http.ServeContent(c.Writer, c.Request, "", attachment.CreatedAt, contents)
+	}
+}
+
+// DeleteAttachmentHandler deletes an attachment.
+// @Summary      Delete an attachment
+// @Description  Deletes a file attached to the task, contents and metadata.
+// @Tags         attachments
+// @Param        id            path  string  true  "Task ID (UUID)"
+// @Param        attachmentId  path  string  true  "Attachment ID (UUID)"
+// @Success      204  "Attachment deleted"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Attachment not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete attachment"
+// @Router       /api/v2/tasks/{id}/attachments/{attachmentId} [delete]
+// @ID DeleteAttachment
+func (h *AttachmentHandler) DeleteAttachmentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attachments, ok := h.attachments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseAttachmentID(c)
+		if !ok {
+			return
+		}
+		if err := attachments.DeleteAttachment(c.Request.Context(), taskID, id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete attachment")
+			sendError(c, err, "Failed to delete attachment")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// The attachment routes under /projects/{projectId}/tasks are served by the
+// handlers above, which scope the service to the project in the path. The
+// handlers below only document them.
+
+// CreateProjectAttachmentHandler uploads a file and attaches it to a task of a project.
+// @Summary      Attach a file to a task of a project
+// @Description  Streams the file in the file field of a multipart/form-data body to storage. The content type is detected from the contents and must be one of the accepted types; the SHA-256 digest is computed on the way. Idempotency-Key is not honoured here.
+// @Tags         projects
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        projectId  path      string  true  "Project ID (UUID)"
+// @Param        id         path      string  true  "Task ID (UUID)"
+// @Param        file  formData  file    true  "File to attach"
+// @Success      201   {object}  model.Attachment  "Created attachment"
+// @Header       201   {string}  Location          "URL of the new attachment"
+// @Failure      400   {object}  response.Problem  "Invalid project or task id, or no file in the body"
+// @Failure      404   {object}  response.Problem  "Task not found in the project"
+// @Failure      413   {object}  response.Problem  "File too large"
+// @Failure      415   {object}  response.Problem  "Not a multipart body, or a file type that isn't accepted"
+// @Failure      429   {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500   {object}  response.Problem  "Failed to store attachment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/attachments [post]
+// @ID CreateProjectAttachment
+func (h *AttachmentHandler) CreateProjectAttachmentHandler() gin.HandlerFunc {
+	return h.CreateAttachmentHandler()
+}
+
+// ListProjectAttachmentsHandler lists the attachments of a task of a project.
+// @Summary      List the attachments of a task of a project
+// @Description  Retrieves the metadata of the files attached to the task of the project, oldest first.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string          true  "Project ID (UUID)"
+// @Param        id         path      string          true  "Task ID (UUID)"
+// @Success      200  {object}  AttachmentList  "Attachments"
+// @Failure      400  {object}  response.Problem  "Invalid project or task id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve attachments"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/attachments [get]
+// @ID ListProjectAttachments
+func (h *AttachmentHandler) ListProjectAttachmentsHandler() gin.HandlerFunc {
+	return h.ListAttachmentsHandler()
+}
+
+// GetProjectAttachmentHandler returns the metadata of an attachment of a task of a project.
+// @Summary      Get an attachment of a task of a project
+// @Description  Retrieves the metadata of a file attached to the task of the project: its name, detected content type, size and SHA-256 digest.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId     path      string  true  "Project ID (UUID)"
+// @Param        id            path      string  true  "Task ID (UUID)"
+// @Param        attachmentId  path      string  true  "Attachment ID (UUID)"
+// @Success      200  {object}  model.Attachment  "Attachment"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project, or attachment not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve attachment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/attachments/{attachmentId} [get]
+// @ID GetProjectAttachment
+func (h *AttachmentHandler) GetProjectAttachmentHandler() gin.HandlerFunc {
+	return h.GetAttachmentHandler()
+}
+
+// DownloadProjectAttachmentHandler streams the contents of an attachment of a task of a project.
+// @Summary      Download an attachment of a task of a project
+// @Description  Streams the file with its detected content type. Range requests are supported, as are If-None-Match and If-Range against the ETag, which is the SHA-256 digest. Images are shown inline; other types are downloaded.
+// @Tags         projects
+// @Produce      */*
+// @Param        projectId     path      string  true   "Project ID (UUID)"
+// @Param        id            path      string  true   "Task ID (UUID)"
+// @Param        attachmentId  path      string  true   "Attachment ID (UUID)"
+// @Param        Range         header    string  false  "Byte range to return, e.g. bytes=0-1023"
+// @Success      200  {file}    file    "Attachment contents"
+// @Header       200  {string}  ETag         "Quoted hex SHA-256 digest of the contents"
+// @Header       200  {string}  Repr-Digest  "RFC 9530 SHA-256 digest of the contents"
+// @Success      206  {file}    file    "The requested range"
+// @Success      304  "Not modified"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project, or attachment not found"
+// @Failure      416  {string}  string  "Range not satisfiable"
+// @Failure      500  {object}  response.Problem  "Failed to read attachment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/attachments/{attachmentId}/content [get]
+// @ID DownloadProjectAttachment
+func (h *AttachmentHandler) DownloadProjectAttachmentHandler() gin.HandlerFunc {
+	return h.DownloadAttachmentHandler()
+}
+
+// DeleteProjectAttachmentHandler deletes an attachment of a task of a project.
+// @Summary      Delete an attachment of a task of a project
+// @Description  Deletes a file attached to the task of the project, contents and metadata.
+// @Tags         projects
+// @Param        projectId     path  string  true  "Project ID (UUID)"
+// @Param        id            path  string  true  "Task ID (UUID)"
+// @Param        attachmentId  path  string  true  "Attachment ID (UUID)"
+// @Success      204  "Attachment deleted"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project, or attachment not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete attachment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/attachments/{attachmentId} [delete]
+// @ID DeleteProjectAttachment
+func (h *AttachmentHandler) DeleteProjectAttachmentHandler() gin.HandlerFunc {
+	return h.DeleteAttachmentHandler()
+}
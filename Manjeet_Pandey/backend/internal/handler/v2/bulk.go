@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"net/http"
+	"task_manager/internal/handler"
+	"task_manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkTaskHandler runs a batch of task operations in one transaction.
+// @Summary      Run bulk task operations
+// @Description  Runs a batch of create, update, delete and status operations on the tasks of the default project in a single transaction. With atomic set, any failure rolls back the whole batch; otherwise each operation succeeds or fails on its own.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        batch            body      service.BulkRequest  true   "Batch of operations"
+// @Param        Idempotency-Key  header    string               false  "Client generated key that identifies this request across retries"
+// @Success      200              {object}  handler.BulkResponse  "Per-operation results"
+// @Failure      400              {object}  response.Problem  "Invalid batch"
+// @Failure      409              {object}  response.Problem  "A request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to run batch"
+// @Router       /api/v2/tasks/bulk [post]
+// @ID BulkTasks
+func (t *TaskHandler) BulkTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		var req service.BulkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		results, err := tasks.Bulk(c.Request.Context(), req)
+		if err != nil {
+			logger(c).Err(err).Msg("Error running bulk operations")
+			sendError(c, err, "Failed to run batch")
+			return
+		}
+		c.JSON(http.StatusOK, handler.NewBulkResponse(req.Atomic, results))
+	}
+}
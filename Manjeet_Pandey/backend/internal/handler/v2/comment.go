@@ -0,0 +1,351 @@
+package v2
+
+import (
+	"net/http"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CommentList is the body of a comment listing.
+type CommentList struct {
+	Items []model.Comment `json:"items"`
+}
+
+// ActivityList is the body of a task's activity feed.
+type ActivityList struct {
+	Items []model.Activity `json:"items"`
+}
+
+type CommentHandler struct {
+	commentService  service.CommentService
+	activityService service.ActivityService
+}
+
+func NewCommentHandler(comments service.CommentService, activity service.ActivityService) CommentHandler {
+	return CommentHandler{commentService: comments, activityService: activity}
+}
+
+// comments returns the comment service scoped to the project of the
+// request, writing a 400 response if its ID is invalid.
+func (h *CommentHandler) comments(c *gin.Context) (service.CommentService, bool) {
+	projectID, ok := projectOf(c)
+	return h.commentService.InProject(projectID), ok
+}
+
+// activity returns the activity service scoped to the project of the
+// request, writing a 400 response if its ID is invalid.
+func (h *CommentHandler) activity(c *gin.Context) (service.ActivityService, bool) {
+	projectID, ok := projectOf(c)
+	return h.activityService.InProject(projectID), ok
+}
+
+// parseCommentID reads the commentId path parameter, writing a 400 response
+// if it is not a valid UUID.
+func parseCommentID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid comment id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateCommentHandler adds a comment to a task.
+// @Summary      Comment on a task
+// @Description  Adds a comment to the task and to its activity feed. The body is markdown.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id               path      string                      true   "Task ID (UUID)"
+// @Param        comment          body      model.CreateCommentRequest  true   "Comment to add"
+// @Param        Idempotency-Key  header    string                      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Comment     "Created comment"
+// @Header       201              {string}  Location          "URL of the new comment"
+// @Failure      400              {object}  response.Problem  "Invalid task id or request payload, with the invalid fields in errors"
+// @Failure      404              {object}  response.Problem  "Task not found"
+// @Failure      409              {object}  response.Problem  "A request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create comment"
+// @Router       /api/v2/tasks/{id}/comments [post]
+// @ID CreateComment
+func (h *CommentHandler) CreateCommentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comments, ok := h.comments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		var req model.CreateCommentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		created, err := comments.CreateComment(c.Request.Context(), taskID, req)
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating comment")
+			sendError(c, err, "Failed to create comment")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+created.ID.String())
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// ListCommentsHandler lists the comments on a task.
+// @Summary      List the comments on a task
+// @Description  Retrieves the comments on the task, oldest first.
+// @Tags         comments
+// @Produce      json
+// @Param        id   path      string       true  "Task ID (UUID)"
+// @Success      200  {object}  CommentList  "Comments"
+// @Failure      400  {object}  response.Problem  "Invalid task id"
+// @Failure      404  {object}  response.Problem  "Task not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve comments"
+// @Router       /api/v2/tasks/{id}/comments [get]
+// @ID ListComments
+func (h *CommentHandler) ListCommentsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comments, ok := h.comments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		list, err := comments.ListComments(c.Request.Context(), taskID)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving comments")
+			sendError(c, err, "Failed to retrieve comments")
+			return
+		}
+		if list == nil {
+			list = []model.Comment{}
+		}
+		c.JSON(http.StatusOK, CommentList{Items: list})
+	}
+}
+
+// UpdateCommentHandler edits a comment.
+// @Summary      Edit a comment
+// @Description  Replaces the body of a comment on the task and marks it as edited.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string                      true  "Task ID (UUID)"
+// @Param        commentId  path      string                      true  "Comment ID (UUID)"
+// @Param        comment    body      model.UpdateCommentRequest  true  "New comment body"
+// @Success      200        {object}  model.Comment     "Updated comment"
+// @Failure      400        {object}  response.Problem  "Invalid id or request payload, with the invalid fields in errors"
+// @Failure      404        {object}  response.Problem  "Comment not found"
+// @Failure      500        {object}  response.Problem  "Failed to update comment"
+// @Router       /api/v2/tasks/{id}/comments/{commentId} [put]
+// @ID UpdateComment
+func (h *CommentHandler) UpdateCommentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comments, ok := h.comments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseCommentID(c)
+		if !ok {
+			return
+		}
+		var req model.UpdateCommentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		updated, err := comments.UpdateComment(c.Request.Context(), taskID, id, req)
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to update comment")
+			sendError(c, err, "Failed to update comment")
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// DeleteCommentHandler deletes a comment.
+// @Summary      Delete a comment
+// @Description  Deletes a comment on the task. Its entry stays in the activity feed, without the comment.
+// @Tags         comments
+// @Param        id         path  string  true  "Task ID (UUID)"
+// @Param        commentId  path  string  true  "Comment ID (UUID)"
+// @Success      204  "Comment deleted"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Comment not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete comment"
+// @Router       /api/v2/tasks/{id}/comments/{commentId} [delete]
+// @ID DeleteComment
+func (h *CommentHandler) DeleteCommentHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comments, ok := h.comments(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseCommentID(c)
+		if !ok {
+			return
+		}
+		if err := comments.DeleteComment(c.Request.Context(), taskID, id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete comment")
+			sendError(c, err, "Failed to delete comment")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListActivityHandler returns the activity feed of a task.
+// @Summary      Get the activity feed of a task
+// @Description  Retrieves the history of the task, oldest first: its creation, status changes and comments. Comment entries carry the comment as it is now, or none once it has been deleted. Imports add a creation entry for the tasks they create, and a status change for the tasks whose status they change.
+// @Tags         comments
+// @Produce      json
+// @Param        id   path      string        true  "Task ID (UUID)"
+// @Success      200  {object}  ActivityList  "Activity feed"
+// @Failure      400  {object}  response.Problem  "Invalid task id"
+// @Failure      404  {object}  response.Problem  "Task not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve activity"
+// @Router       /api/v2/tasks/{id}/activity [get]
+// @ID ListActivity
+func (h *CommentHandler) ListActivityHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		activity, ok := h.activity(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		entries, err := activity.ListActivity(c.Request.Context(), taskID)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving activity")
+			sendError(c, err, "Failed to retrieve activity")
+			return
+		}
+		if entries == nil {
+			entries = []model.Activity{}
+		}
+		c.JSON(http.StatusOK, ActivityList{Items: entries})
+	}
+}
+
+// The comment and activity routes under /projects/{projectId}/tasks are
+// served by the handlers above, which scope the services to the project in
+// the path. The handlers below only document them.
+
+// CreateProjectCommentHandler adds a comment to a task of a project.
+// @Summary      Comment on a task of a project
+// @Description  Adds a comment to the task of the project and to its activity feed. The body is markdown.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        projectId        path      string                      true   "Project ID (UUID)"
+// @Param        id               path      string                      true   "Task ID (UUID)"
+// @Param        comment          body      model.CreateCommentRequest  true   "Comment to add"
+// @Param        Idempotency-Key  header    string                      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Comment     "Created comment"
+// @Header       201              {string}  Location          "URL of the new comment"
+// @Failure      400              {object}  response.Problem  "Invalid project or task id, or an invalid request payload with the invalid fields in errors"
+// @Failure      404              {object}  response.Problem  "Task not found in the project"
+// @Failure      409              {object}  response.Problem  "A request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create comment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/comments [post]
+// @ID CreateProjectComment
+func (h *CommentHandler) CreateProjectCommentHandler() gin.HandlerFunc {
+	return h.CreateCommentHandler()
+}
+
+// ListProjectCommentsHandler lists the comments on a task of a project.
+// @Summary      List the comments on a task of a project
+// @Description  Retrieves the comments on the task of the project, oldest first.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string       true  "Project ID (UUID)"
+// @Param        id         path      string       true  "Task ID (UUID)"
+// @Success      200  {object}  CommentList  "Comments"
+// @Failure      400  {object}  response.Problem  "Invalid project or task id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve comments"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/comments [get]
+// @ID ListProjectComments
+func (h *CommentHandler) ListProjectCommentsHandler() gin.HandlerFunc {
+	return h.ListCommentsHandler()
+}
+
+// UpdateProjectCommentHandler edits a comment on a task of a project.
+// @Summary      Edit a comment on a task of a project
+// @Description  Replaces the body of a comment on the task of the project and marks it as edited.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        projectId  path      string                      true  "Project ID (UUID)"
+// @Param        id         path      string                      true  "Task ID (UUID)"
+// @Param        commentId  path      string                      true  "Comment ID (UUID)"
+// @Param        comment    body      model.UpdateCommentRequest  true  "New comment body"
+// @Success      200        {object}  model.Comment     "Updated comment"
+// @Failure      400        {object}  response.Problem  "Invalid id or request payload, with the invalid fields in errors"
+// @Failure      404        {object}  response.Problem  "Task not found in the project, or comment not found"
+// @Failure      500        {object}  response.Problem  "Failed to update comment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/comments/{commentId} [put]
+// @ID UpdateProjectComment
+func (h *CommentHandler) UpdateProjectCommentHandler() gin.HandlerFunc {
+	return h.UpdateCommentHandler()
+}
+
+// DeleteProjectCommentHandler deletes a comment on a task of a project.
+// @Summary      Delete a comment on a task of a project
+// @Description  Deletes a comment on the task of the project. Its entry stays in the activity feed, without the comment.
+// @Tags         projects
+// @Param        projectId  path  string  true  "Project ID (UUID)"
+// @Param        id         path  string  true  "Task ID (UUID)"
+// @Param        commentId  path  string  true  "Comment ID (UUID)"
+// @Success      204  "Comment deleted"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project, or comment not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete comment"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/comments/{commentId} [delete]
+// @ID DeleteProjectComment
+func (h *CommentHandler) DeleteProjectCommentHandler() gin.HandlerFunc {
+	return h.DeleteCommentHandler()
+}
+
+// ListProjectActivityHandler returns the activity feed of a task of a project.
+// @Summary      Get the activity feed of a task of a project
+// @Description  Retrieves the history of the task of the project, oldest first: its creation, status changes and comments. Comment entries carry the comment as it is now, or none once it has been deleted. Imports add a creation entry for the tasks they create, and a status change for the tasks whose status they change.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string        true  "Project ID (UUID)"
+// @Param        id         path      string        true  "Task ID (UUID)"
+// @Success      200  {object}  ActivityList  "Activity feed"
+// @Failure      400  {object}  response.Problem  "Invalid project or task id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve activity"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/activity [get]
+// @ID ListProjectActivity
+func (h *CommentHandler) ListProjectActivityHandler() gin.HandlerFunc {
+	return h.ListActivityHandler()
+}
@@ -0,0 +1,11 @@
+// Package v2 implements version 2 of the HTTP API, served under /api/v2.
+// Resources are returned as they are, without the version 1 envelope, and
+// errors are always RFC 7807 problem details. The handlers share the
+// services with version 1.
+//
+// @title        Task Manager API
+// @version      2.0
+// @description  A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+// @host         localhost:8080
+// @BasePath     /
+package v2
@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"task_manager/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventsHandler serves the version 1 event streams under /api/v2. Events
+// were never wrapped in the envelope, so both versions send the same
+// messages.
+type EventsHandler struct {
+	events handler.EventsHandler
+}
+
+func NewEventsHandler(events handler.EventsHandler) EventsHandler {
+	return EventsHandler{events: events}
+}
+
+// StreamHandler streams task events as Server-Sent Events.
+// @Summary      Stream task events
+// @Description  Streams task changes as Server-Sent Events. Each event is named after its type and carries an events.Event as JSON.
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        status   query     []string  false  "Only events for tasks in these statuses"  collectionFormat(multi)
+// @Param        task_id  query     []string  false  "Only events for these task IDs"  collectionFormat(multi)
+// @Success      200      {object}  events.Event  "Event stream"
+// @Failure      400      {object}  response.Problem  "Invalid filter"
+// @Router       /api/v2/events [get]
+// @ID StreamEvents
+func (h *EventsHandler) StreamHandler() gin.HandlerFunc {
+	return h.events.StreamHandler()
+}
+
+// WebSocketHandler streams task events over a WebSocket.
+// @Summary      Stream task events over WebSocket
+// @Description  Upgrades to a WebSocket and sends each task change as a JSON events.Event text message.
+// @Tags         events
+// @Param        status   query     []string  false  "Only events for tasks in these statuses"  collectionFormat(multi)
+// @Param        task_id  query     []string  false  "Only events for these task IDs"  collectionFormat(multi)
+// @Success      101      {object}  events.Event  "Switching protocols"
+// @Failure      400      {object}  response.Problem  "Invalid filter"
+// @Router       /api/v2/ws [get]
+// @ID WatchEvents
+func (h *EventsHandler) WebSocketHandler() gin.HandlerFunc {
+	return h.events.WebSocketHandler()
+}
@@ -0,0 +1,240 @@
+package v2
+
+import (
+	"net/http"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LabelList is the body of a label listing.
+type LabelList struct {
+	Items []model.Label `json:"items"`
+}
+
+type LabelHandler struct {
+	labelService service.LabelService
+}
+
+func NewLabelHandler(labels service.LabelService) LabelHandler {
+	return LabelHandler{labelService: labels}
+}
+
+// labels returns the label service scoped to the project of the request,
+// writing a 400 response if its ID is invalid.
+func (h *LabelHandler) labels(c *gin.Context) (service.LabelService, bool) {
+	projectID, ok := projectOf(c)
+	return h.labelService.InProject(projectID), ok
+}
+
+// parseLabelID reads the labelId path parameter, writing a 400 response if
+// it is not a valid UUID.
+func parseLabelID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("labelId"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid label id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateLabelHandler creates a label.
+// @Summary      Create a label
+// @Description  Creates a label that can then be attached to tasks. Names are unique; the colour defaults to grey.
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        label            body      model.CreateLabelRequest  true   "Label to create"
+// @Param        Idempotency-Key  header    string                    false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Label       "Created label"
+// @Header       201              {string}  Location          "URL of the new label"
+// @Failure      400              {object}  response.Problem  "Invalid request payload, with the invalid fields in errors"
+// @Failure      409              {object}  response.Problem  "A label with this name already exists, or a request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create label"
+// @Router       /api/v2/labels [post]
+// @ID CreateLabel
+func (h *LabelHandler) CreateLabelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.CreateLabelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		created, err := h.labelService.CreateLabel(c.Request.Context(), req)
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating label")
+			sendError(c, err, "Failed to create label")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+created.ID.String())
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// ListLabelsHandler lists every label.
+// @Summary      List labels
+// @Description  Retrieves every label, by name.
+// @Tags         labels
+// @Produce      json
+// @Success      200  {object}  LabelList  "Labels"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve labels"
+// @Router       /api/v2/labels [get]
+// @ID ListLabels
+func (h *LabelHandler) ListLabelsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		labels, err := h.labelService.ListLabels(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving labels")
+			sendError(c, err, "Failed to retrieve labels")
+			return
+		}
+		if labels == nil {
+			labels = []model.Label{}
+		}
+		c.JSON(http.StatusOK, LabelList{Items: labels})
+	}
+}
+
+// DeleteLabelHandler deletes a label.
+// @Summary      Delete a label
+// @Description  Deletes a label and removes it from every task carrying it.
+// @Tags         labels
+// @Param        labelId  path  string  true  "Label ID (UUID)"
+// @Success      204  "Label deleted"
+// @Failure      400  {object}  response.Problem  "Invalid label id"
+// @Failure      404  {object}  response.Problem  "Label not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete label"
+// @Router       /api/v2/labels/{labelId} [delete]
+// @ID DeleteLabel
+func (h *LabelHandler) DeleteLabelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseLabelID(c)
+		if !ok {
+			return
+		}
+		if err := h.labelService.DeleteLabel(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete label")
+			sendError(c, err, "Failed to delete label")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AttachLabelHandler puts a label on a task.
+// @Summary      Attach a label to a task
+// @Description  Puts the label on the task and returns the task with its labels. Attaching a label the task already carries changes nothing.
+// @Tags         labels
+// @Produce      json
+// @Param        id       path      string      true  "Task ID (UUID)"
+// @Param        labelId  path      string      true  "Label ID (UUID)"
+// @Success      200      {object}  model.Task  "Task with its labels"
+// @Failure      400      {object}  response.Problem  "Invalid id"
+// @Failure      404      {object}  response.Problem  "Task or label not found"
+// @Failure      500      {object}  response.Problem  "Failed to attach label"
+// @Router       /api/v2/tasks/{id}/labels/{labelId} [put]
+// @ID AttachLabel
+func (h *LabelHandler) AttachLabelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		labels, ok := h.labels(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseLabelID(c)
+		if !ok {
+			return
+		}
+		task, err := labels.AttachLabel(c.Request.Context(), taskID, id)
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to attach label")
+			sendError(c, err, "Failed to attach label")
+			return
+		}
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// DetachLabelHandler takes a label off a task.
+// @Summary      Detach a label from a task
+// @Description  Takes the label off the task. The label itself is kept.
+// @Tags         labels
+// @Param        id       path  string  true  "Task ID (UUID)"
+// @Param        labelId  path  string  true  "Label ID (UUID)"
+// @Success      204  "Label detached"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found, or the task doesn't carry the label"
+// @Failure      500  {object}  response.Problem  "Failed to detach label"
+// @Router       /api/v2/tasks/{id}/labels/{labelId} [delete]
+// @ID DetachLabel
+func (h *LabelHandler) DetachLabelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		labels, ok := h.labels(c)
+		if !ok {
+			return
+		}
+		taskID, ok := parseID(c)
+		if !ok {
+			return
+		}
+		id, ok := parseLabelID(c)
+		if !ok {
+			return
+		}
+		if err := labels.DetachLabel(c.Request.Context(), taskID, id); err != nil {
+			logger(c).Err(err).Msg("Failed to detach label")
+			sendError(c, err, "Failed to detach label")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// The label routes under /projects/{projectId}/tasks are served by the
+// handlers above, which scope the service to the project in the path. The
+// handlers below only document them.
+
+// AttachProjectLabelHandler puts a label on a task of a project.
+// @Summary      Attach a label to a task of a project
+// @Description  Puts the label on the task of the project and returns the task with its labels. Attaching a label the task already carries changes nothing.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string      true  "Project ID (UUID)"
+// @Param        id         path      string      true  "Task ID (UUID)"
+// @Param        labelId  path      string      true  "Label ID (UUID)"
+// @Success      200      {object}  model.Task  "Task with its labels"
+// @Failure      400      {object}  response.Problem  "Invalid id"
+// @Failure      404      {object}  response.Problem  "Task not found in the project, or label not found"
+// @Failure      500      {object}  response.Problem  "Failed to attach label"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/labels/{labelId} [put]
+// @ID AttachProjectLabel
+func (h *LabelHandler) AttachProjectLabelHandler() gin.HandlerFunc {
+	return h.AttachLabelHandler()
+}
+
+// DetachProjectLabelHandler takes a label off a task of a project.
+// @Summary      Detach a label from a task of a project
+// @Description  Takes the label off the task of the project. The label itself is kept.
+// @Tags         projects
+// @Param        projectId  path  string  true  "Project ID (UUID)"
+// @Param        id         path  string  true  "Task ID (UUID)"
+// @Param        labelId  path  string  true  "Label ID (UUID)"
+// @Success      204  "Label detached"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Task not found in the project, or the task doesn't carry the label"
+// @Failure      500  {object}  response.Problem  "Failed to detach label"
+// @Router       /api/v2/projects/{projectId}/tasks/{id}/labels/{labelId} [delete]
+// @ID DetachProjectLabel
+func (h *LabelHandler) DetachProjectLabelHandler() gin.HandlerFunc {
+	return h.DetachLabelHandler()
+}
@@ -0,0 +1,317 @@
+package v2
+
+import (
+	"net/http"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProjectList is the body of a project listing.
+type ProjectList struct {
+	Items []model.Project `json:"items"`
+}
+
+type ProjectHandler struct {
+	projectService service.ProjectService
+}
+
+func NewProjectHandler(projects service.ProjectService) ProjectHandler {
+	return ProjectHandler{projectService: projects}
+}
+
+// parseProjectID reads the projectId path parameter, writing a 400 response
+// if it is not a valid UUID.
+func parseProjectID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid project id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// projectOf returns the project of the request: the one in the projectId
+// path parameter on the routes under /projects, and the default project
+// elsewhere. It writes a 400 response if the project ID is not a valid
+// UUID. A project that doesn't exist has no tasks, so requests for its
+// tasks get a 404.
+func projectOf(c *gin.Context) (uuid.UUID, bool) {
+	if c.Param("projectId") == "" {
+		return model.DefaultProjectID, true
+	}
+	return parseProjectID(c)
+}
+
+// CreateProjectHandler creates a project.
+// @Summary      Create a project
+// @Description  Creates a project to group tasks. Names are unique. max_concurrency caps how many of the project's tasks a worker pool processes at once; 0 leaves it to the size of the pool.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        project          body      model.CreateProjectRequest  true   "Project to create"
+// @Param        Idempotency-Key  header    string                      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Project     "Created project"
+// @Header       201              {string}  Location          "URL of the new project"
+// @Failure      400              {object}  response.Problem  "Invalid request payload, with the invalid fields in errors"
+// @Failure      409              {object}  response.Problem  "A project with this name already exists, or a request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create project"
+// @Router       /api/v2/projects [post]
+// @ID CreateProject
+func (h *ProjectHandler) CreateProjectHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.CreateProjectRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		created, err := h.projectService.CreateProject(c.Request.Context(), req)
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating project")
+			sendError(c, err, "Failed to create project")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+created.ID.String())
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// ListProjectsHandler lists every project.
+// @Summary      List projects
+// @Description  Retrieves every project, by name, the default project included.
+// @Tags         projects
+// @Produce      json
+// @Success      200  {object}  ProjectList  "Projects"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve projects"
+// @Router       /api/v2/projects [get]
+// @ID ListProjects
+func (h *ProjectHandler) ListProjectsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projects, err := h.projectService.ListProjects(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving projects")
+			sendError(c, err, "Failed to retrieve projects")
+			return
+		}
+		if projects == nil {
+			projects = []model.Project{}
+		}
+		c.JSON(http.StatusOK, ProjectList{Items: projects})
+	}
+}
+
+// GetProjectHandler retrieves a project by ID.
+// @Summary      Get a project
+// @Description  Retrieves a project by its unique identifier.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string         true  "Project ID (UUID)"
+// @Success      200        {object}  model.Project  "Project"
+// @Failure      400        {object}  response.Problem  "Invalid project id"
+// @Failure      404        {object}  response.Problem  "Project not found"
+// @Failure      500        {object}  response.Problem  "Failed to retrieve project"
+// @Router       /api/v2/projects/{projectId} [get]
+// @ID GetProject
+func (h *ProjectHandler) GetProjectHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseProjectID(c)
+		if !ok {
+			return
+		}
+		project, err := h.projectService.GetProject(c.Request.Context(), id)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving project")
+			sendError(c, err, "Failed to retrieve project")
+			return
+		}
+		c.JSON(http.StatusOK, project)
+	}
+}
+
+// UpdateProjectHandler replaces a project.
+// @Summary      Replace a project
+// @Description  Replaces the name, description and concurrency limit of the project. Omitted fields are reset to their defaults.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        projectId  path      string                      true  "Project ID (UUID)"
+// @Param        project    body      model.UpdateProjectRequest  true  "Updated project"
+// @Success      200        {object}  model.Project     "Updated project"
+// @Failure      400        {object}  response.Problem  "Invalid request payload, with the invalid fields in errors"
+// @Failure      404        {object}  response.Problem  "Project not found"
+// @Failure      409        {object}  response.Problem  "A project with this name already exists"
+// @Failure      500        {object}  response.Problem  "Failed to update project"
+// @Router       /api/v2/projects/{projectId} [put]
+// @ID UpdateProject
+func (h *ProjectHandler) UpdateProjectHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.UpdateProjectRequest
+		id, ok := parseProjectID(c)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		updated, err := h.projectService.ReplaceProject(c.Request.Context(), id, req)
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to update project")
+			sendError(c, err, "Failed to update project")
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// DeleteProjectHandler deletes an empty project.
+// @Summary      Delete a project
+// @Description  Deletes a project that has no tasks left. The default project can't be deleted.
+// @Tags         projects
+// @Param        projectId  path  string  true  "Project ID (UUID)"
+// @Success      204  "Project deleted"
+// @Failure      400  {object}  response.Problem  "Invalid project id"
+// @Failure      404  {object}  response.Problem  "Project not found"
+// @Failure      409  {object}  response.Problem  "The project still has tasks, or is the default project"
+// @Failure      500  {object}  response.Problem  "Failed to delete project"
+// @Router       /api/v2/projects/{projectId} [delete]
+// @ID DeleteProject
+func (h *ProjectHandler) DeleteProjectHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseProjectID(c)
+		if !ok {
+			return
+		}
+		if err := h.projectService.DeleteProject(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete project")
+			sendError(c, err, "Failed to delete project")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// The task routes under /projects/{projectId} are served by the task
+// handlers, which scope the task service to the project in the path. The
+// handlers below only document them.
+
+// CreateProjectTaskHandler creates a task in a project.
+// @Summary      Create a task in a project
+// @Description  Creates a task in the project and returns it, with its URL in Location. Send an Idempotency-Key to make retries safe: a retry with the same key and body gets the original response, marked with Idempotent-Replayed.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        projectId        path      string                   true   "Project ID (UUID)"
+// @Param        task             body      model.TaskRequest        true   "Task to create"
+// @Param        Idempotency-Key  header    string      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Task  "Created task"
+// @Header       201              {string}  Location    "URL of the new task"
+// @Failure      400              {object}  response.Problem  "Invalid project id, or an invalid request payload with the invalid fields in errors"
+// @Failure      404              {object}  response.Problem  "Project not found"
+// @Failure      409              {object}  response.Problem  "Task already exists, or a request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create task"
+// @Router       /api/v2/projects/{projectId}/tasks [post]
+// @ID CreateProjectTask
+func (t *TaskHandler) CreateProjectTaskHandler() gin.HandlerFunc {
+	return t.CreateTaskHandler()
+}
+
+// ListProjectTasksHandler lists the tasks of a project.
+// @Summary      List the tasks of a project
+// @Description  Retrieves the tasks of the project, with their labels. With label, only tasks carrying every one of the named labels are listed, or any of them with match=any.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string    true   "Project ID (UUID)"
+// @Param        label      query     []string  false  "Only tasks with these labels, by name"  collectionFormat(multi)
+// @Param        match      query     string    false  "Whether tasks need all the labels or any of them"  Enums(all, any)  default(all)
+// @Success      200        {object}  TaskList  "List of tasks"
+// @Failure      400        {object}  response.Problem  "Invalid project id or label filter"
+// @Failure      404        {object}  response.Problem  "Project not found"
+// @Failure      500        {object}  response.Problem  "Failed to retrieve tasks"
+// @Router       /api/v2/projects/{projectId}/tasks [get]
+// @ID ListProjectTasks
+func (t *TaskHandler) ListProjectTasksHandler() gin.HandlerFunc {
+	return t.GetTasksHandler()
+}
+
+// GetProjectTaskHandler retrieves a task of a project.
+// @Summary      Get a task of a project
+// @Description  Retrieves a task of the project by its unique identifier.
+// @Tags         projects
+// @Produce      json
+// @Param        projectId  path      string      true  "Project ID (UUID)"
+// @Param        id         path      string      true  "Task ID (UUID)"
+// @Success      200        {object}  model.Task  "Task details"
+// @Failure      400        {object}  response.Problem  "Invalid id"
+// @Failure      404        {object}  response.Problem  "Project not found, or no such task in the project"
+// @Failure      500        {object}  response.Problem  "Failed to retrieve task"
+// @Router       /api/v2/projects/{projectId}/tasks/{id} [get]
+// @ID GetProjectTask
+func (t *TaskHandler) GetProjectTaskHandler() gin.HandlerFunc {
+	return t.GetTaskHandler()
+}
+
+// UpdateProjectTaskHandler replaces a task of a project.
+// @Summary      Replace a task of a project
+// @Description  Replaces all writable fields of the task of the project identified by its ID. Omitted fields are reset to their defaults.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        projectId  path      string                   true  "Project ID (UUID)"
+// @Param        id         path      string                   true  "Task ID (UUID)"
+// @Param        task       body      model.TaskRequest        true  "Updated task"
+// @Success      200        {object}  model.Task  "Updated task"
+// @Failure      400        {object}  response.Problem  "Invalid id, or an invalid request payload with the invalid fields in errors"
+// @Failure      404        {object}  response.Problem  "Project not found, or no such task in the project"
+// @Failure      500        {object}  response.Problem  "Failed to update task"
+// @Router       /api/v2/projects/{projectId}/tasks/{id} [put]
+// @ID UpdateProjectTask
+func (t *TaskHandler) UpdateProjectTaskHandler() gin.HandlerFunc {
+	return t.UpdateTaskHandler()
+}
+
+// PatchProjectTaskHandler partially updates a task of a project.
+// @Summary      Patch a task of a project
+// @Description  Applies an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON patch (application/json-patch+json) to the task of the project identified by its ID. Changes to project_id are ignored.
+// @Tags         projects
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        projectId  path      string      true  "Project ID (UUID)"
+// @Param        id         path      string      true  "Task ID (UUID)"
+// @Param        patch      body      object      true  "Merge patch or JSON patch document"
+// @Success      200        {object}  model.Task  "Patched task"
+// @Failure      400        {object}  response.Problem  "Invalid id or patch document, or a patched task with the invalid fields in errors"
+// @Failure      404        {object}  response.Problem  "Project not found, or no such task in the project"
+// @Failure      415        {object}  response.Problem  "Unsupported patch format"
+// @Failure      500        {object}  response.Problem  "Failed to patch task"
+// @Router       /api/v2/projects/{projectId}/tasks/{id} [patch]
+// @ID PatchProjectTask
+func (t *TaskHandler) PatchProjectTaskHandler() gin.HandlerFunc {
+	return t.PatchTaskHandler()
+}
+
+// DeleteProjectTaskHandler deletes a task of a project.
+// @Summary      Delete a task of a project
+// @Description  Deletes the task of the project identified by its ID.
+// @Tags         projects
+// @Param        projectId  path  string  true  "Project ID (UUID)"
+// @Param        id         path  string  true  "Task ID (UUID)"
+// @Success      204  "Task deleted"
+// @Failure      400  {object}  response.Problem  "Invalid id"
+// @Failure      404  {object}  response.Problem  "Project not found, or no such task in the project"
+// @Failure      500  {object}  response.Problem  "Failed to delete task"
+// @Router       /api/v2/projects/{projectId}/tasks/{id} [delete]
+// @ID DeleteProjectTask
+func (t *TaskHandler) DeleteProjectTaskHandler() gin.HandlerFunc {
+	return t.DeleteTaskHandler()
+}
@@ -0,0 +1,346 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+	"task_manager/internal/handler"
+	"task_manager/internal/logging"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"task_manager/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TaskList is the body of a task listing.
+type TaskList struct {
+	Items []model.Task `json:"items"`
+}
+
+type TaskHandler struct {
+	taskService service.TaskService
+	workers     *util.Worker
+}
+
+// NewTaskHandler serves tasks. ProcessTasksHandler hands the tasks it
+// claims to workers, which the server owns and shuts down.
+func NewTaskHandler(service service.TaskService, workers *util.Worker) TaskHandler {
+	return TaskHandler{taskService: service, workers: workers}
+}
+
+func logger(c *gin.Context) *zerolog.Logger {
+	return logging.Ctx(c.Request.Context())
+}
+
+// sendError reports err as problem details. Unrecognised errors are
+// reported as 500 with msg so internals don't leak.
+func sendError(c *gin.Context, err error, msg string) {
+	response.SendProblem(c, handler.ErrorResponse(err, msg))
+}
+
+func sendBindError(c *gin.Context, err error) {
+	logger(c).Err(err).Msg("Invalid payload")
+	response.SendProblem(c, handler.BindErrorResponse(err))
+}
+
+// parseID reads the ID path parameter, writing a 400 response if it is not
+// a valid UUID.
+func parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid task id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// tasks returns the task service for the request: scoped to the project in
+// the projectId path parameter on the routes under /projects, and to the
+// default project elsewhere. It writes a 400 or 404 response when the
+// project is invalid or doesn't exist.
+func (t *TaskHandler) tasks(c *gin.Context) (service.TaskService, bool) {
+	if c.Param("projectId") == "" {
+		return t.taskService, true
+	}
+	projectID, ok := parseProjectID(c)
+	if !ok {
+		return service.TaskService{}, false
+	}
+	tasks := t.taskService.InProject(projectID)
+	if _, err := tasks.Project(c.Request.Context()); err != nil {
+		logger(c).Err(err).Msg("Error retrieving project")
+		sendError(c, err, "Failed to retrieve project")
+		return service.TaskService{}, false
+	}
+	return tasks, true
+}
+
+// CreateTaskHandler creates a new task.
+// @Summary      Create a new task
+// @Description  Creates a task in the default project and returns it, with its URL in Location. Send an Idempotency-Key to make retries safe: a retry with the same key and body gets the original response, marked with Idempotent-Replayed.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        task             body      model.TaskRequest        true   "Task to create"
+// @Param        Idempotency-Key  header    string      false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Task  "Created task"
+// @Header       201              {string}  Location    "URL of the new task"
+// @Failure      400              {object}  response.Problem  "Invalid request payload, with the invalid fields in errors"
+// @Failure      409              {object}  response.Problem  "Task already exists, or a request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create task"
+// @Router       /api/v2/tasks [post]
+// @ID CreateTask
+func (t *TaskHandler) CreateTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		var req model.TaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		created, err := tasks.CreateTask(c.Request.Context(), req.Task())
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating task")
+			sendError(c, err, "Failed to create task")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+created.ID.String())
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetTasksHandler lists every task, or those with the given labels.
+// @Summary      List all tasks
+// @Description  Retrieves the tasks of the default project, with their labels. With label, only tasks carrying every one of the named labels are listed, or any of them with match=any.
+// @Tags         tasks
+// @Produce      json
+// @Param        label  query     []string  false  "Only tasks with these labels, by name"  collectionFormat(multi)
+// @Param        match  query     string    false  "Whether tasks need all the labels or any of them"  Enums(all, any)  default(all)
+// @Success      200    {object}  TaskList  "List of tasks"
+// @Failure      400    {object}  response.Problem  "Invalid label filter"
+// @Failure      500    {object}  response.Problem  "Failed to retrieve tasks"
+// @Router       /api/v2/tasks [get]
+// @ID ListTasks
+func (t *TaskHandler) GetTasksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		filter, err := service.ParseLabelFilter(c.QueryArray("label"), c.Query("match"))
+		if err != nil {
+			sendError(c, err, "Invalid label filter")
+			return
+		}
+		list, err := tasks.ListTask(c.Request.Context(), filter)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving tasks")
+			sendError(c, err, "Failed to retrieve tasks")
+			return
+		}
+		if list == nil {
+			list = []model.Task{}
+		}
+		c.JSON(http.StatusOK, TaskList{Items: list})
+	}
+}
+
+// GetTaskHandler retrieves a task by ID.
+// @Summary      Get a task
+// @Description  Retrieves a task of the default project by its unique identifier.
+// @Tags         tasks
+// @Produce      json
+// @Param        id   path      string      true  "Task ID (UUID)"
+// @Success      200  {object}  model.Task  "Task details"
+// @Failure      400  {object}  response.Problem  "Invalid task id"
+// @Failure      404  {object}  response.Problem  "Task not found"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve task"
+// @Router       /api/v2/tasks/{id} [get]
+// @ID GetTaskByID
+func (t *TaskHandler) GetTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		task, err := tasks.GetTask(c.Request.Context(), id)
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving task")
+			sendError(c, err, "Failed to retrieve task")
+			return
+		}
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// UpdateTaskHandler replaces an existing task.
+// @Summary      Replace a task
+// @Description  Replaces all writable fields of the task of the default project identified by its ID. Omitted fields are reset to their defaults.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                   true  "Task ID (UUID)"
+// @Param        task  body      model.TaskRequest        true  "Updated task"
+// @Success      200   {object}  model.Task  "Updated task"
+// @Failure      400   {object}  response.Problem  "Invalid request payload, with the invalid fields in errors"
+// @Failure      404   {object}  response.Problem  "Task not found"
+// @Failure      500   {object}  response.Problem  "Failed to update task"
+// @Router       /api/v2/tasks/{id} [put]
+// @ID UpdateTask
+func (t *TaskHandler) UpdateTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.TaskRequest
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		updated, err := tasks.ReplaceTask(c.Request.Context(), id, req.Task())
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to update task")
+			sendError(c, err, "Failed to update task")
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// PatchTaskHandler partially updates an existing task.
+// @Summary      Patch a task
+// @Description  Applies an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON patch (application/json-patch+json) to the task of the default project identified by its ID.
+// @Tags         tasks
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        id     path      string      true  "Task ID (UUID)"
+// @Param        patch  body      object      true  "Merge patch or JSON patch document"
+// @Success      200    {object}  model.Task  "Patched task"
+// @Failure      400    {object}  response.Problem  "Invalid patch document, or a patched task with the invalid fields in errors"
+// @Failure      404    {object}  response.Problem  "Task not found"
+// @Failure      415    {object}  response.Problem  "Unsupported patch format"
+// @Failure      500    {object}  response.Problem  "Failed to patch task"
+// @Router       /api/v2/tasks/{id} [patch]
+// @ID PatchTask
+func (t *TaskHandler) PatchTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		body, err := c.GetRawData()
+		if err != nil {
+			sendBindError(c, err)
+			return
+		}
+		patch, ok := service.NewPatch(c.ContentType(), body)
+		if !ok {
+			response.SendProblem(c, response.NewErrorResponse(http.StatusUnsupportedMediaType, "Unsupported patch format"))
+			return
+		}
+		task, err := tasks.PatchTask(c.Request.Context(), id, patch)
+		if err != nil {
+			logger(c).Err(err).Msg("Failed to patch task")
+			sendError(c, err, "Failed to patch task")
+			return
+		}
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// ProcessTasksHandler claims every pending task and queues it for the
+// server's worker pool. Tasks already claimed by an earlier call are left
+// out, so concurrent calls never process a task twice.
+// @Summary      Process pending tasks
+// @Description  Claims every pending task of the default project not already being processed, queues it for the in-process workers and returns immediately. Tasks claimed by an earlier call are left out. Progress is published on /api/v2/events and /api/v2/ws.
+// @Tags         tasks
+// @Produce      json
+// @Param        Idempotency-Key  header    string    false  "Client generated key that identifies this request across retries"
+// @Success      202              {object}  TaskList  "Queued tasks"
+// @Failure      409              {object}  response.Problem  "A request with this Idempotency-Key is in progress"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to queue tasks"
+// @Failure      503              {object}  response.Problem  "The server is shutting down"
+// @Router       /api/v2/tasks/process [post]
+// @ID ProcessTasks
+func (t *TaskHandler) ProcessTasksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		claimed, err := tasks.ClaimPendingTasks(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Cannot claim Pending Task")
+			sendError(c, err, "Failed to queue tasks")
+			return
+		}
+		// The request context is cancelled once the response is written; the
+		// jobs only keep its span context so their spans join this trace.
+		queued := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(c.Request.Context()))
+		if !t.workers.Submit(queued, claimed) {
+			// The claims lapse after service.ClaimTimeout.
+			response.SendProblem(c, response.NewErrorResponse(http.StatusServiceUnavailable, "The server is shutting down"))
+			return
+		}
+		if claimed == nil {
+			claimed = []model.Task{}
+		}
+		c.JSON(http.StatusAccepted, TaskList{Items: claimed})
+	}
+}
+
+// DeleteTaskHandler deletes a task by ID.
+// @Summary      Delete a task
+// @Description  Deletes a task of the default project identified by its unique identifier.
+// @Tags         tasks
+// @Param        id   path  string  true  "Task ID (UUID)"
+// @Success      204  "Task deleted"
+// @Failure      400  {object}  response.Problem  "Invalid task id"
+// @Failure      404  {object}  response.Problem  "Task not found"
+// @Failure      500  {object}  response.Problem  "Failed to delete task"
+// @Router       /api/v2/tasks/{id} [delete]
+// @ID DeleteTask
+func (t *TaskHandler) DeleteTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, ok := t.tasks(c)
+		if !ok {
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		if err := tasks.DeleteTask(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete task")
+			sendError(c, err, "Failed to delete task")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
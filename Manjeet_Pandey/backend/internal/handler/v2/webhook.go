@@ -0,0 +1,216 @@
+package v2
+
+import (
+	"net/http"
+	"task_manager/internal/events"
+	"task_manager/internal/handler"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/internal/webhook"
+	"task_manager/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookList is the body of a webhook listing.
+type WebhookList struct {
+	Items []model.Webhook `json:"items"`
+}
+
+// DeliveryList is the body of a webhook's delivery log.
+type DeliveryList struct {
+	Items []model.WebhookDelivery `json:"items"`
+}
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	dispatcher     *webhook.Dispatcher
+}
+
+func NewWebhookHandler(webhooks service.WebhookService, dispatcher *webhook.Dispatcher) WebhookHandler {
+	return WebhookHandler{webhookService: webhooks, dispatcher: dispatcher}
+}
+
+// parseWebhookID reads the webhookId path parameter, writing a 400 response
+// if it is not a valid UUID.
+func parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		logger(c).Err(err).Msg("Error parsing uuid")
+		response.SendProblem(c, response.NewErrorResponse(http.StatusBadRequest, "Invalid webhook id"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CreateWebhookHandler registers a new webhook.
+// @Summary      Create a webhook
+// @Description  Subscribes a URL to task events. The signing secret is generated when omitted and is only returned in this response.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook          body      handler.WebhookRequest  true   "Webhook to create"
+// @Param        Idempotency-Key  header    string                  false  "Client generated key that identifies this request across retries"
+// @Success      201              {object}  model.Webhook     "Created webhook"
+// @Header       201              {string}  Location          "URL of the new webhook"
+// @Failure      400              {object}  response.Problem  "Invalid request payload"
+// @Failure      409              {object}  response.Problem  "A request with this Idempotency-Key is in progress"
+// @Failure      413              {object}  response.Problem  "Request body too large"
+// @Failure      422              {object}  response.Problem  "Idempotency-Key already used for a different request"
+// @Failure      429              {object}  response.Problem  "Rate limit exceeded"
+// @Failure      500              {object}  response.Problem  "Failed to create webhook"
+// @Router       /api/v2/webhooks [post]
+// @ID CreateWebhook
+func (h *WebhookHandler) CreateWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req handler.WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		hook, err := h.webhookService.CreateWebhook(c.Request.Context(), model.Webhook{URL: req.URL, Events: req.Events, Secret: req.Secret})
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating webhook")
+			sendError(c, err, "Failed to create webhook")
+			return
+		}
+		c.Header("Location", c.Request.URL.Path+"/"+hook.ID.String())
+		c.JSON(http.StatusCreated, hook)
+	}
+}
+
+// ListWebhooksHandler lists every webhook.
+// @Summary      List webhooks
+// @Description  Retrieves every webhook, without its signing secret.
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {object}  WebhookList       "List of webhooks"
+// @Failure      500  {object}  response.Problem  "Failed to retrieve webhooks"
+// @Router       /api/v2/webhooks [get]
+// @ID ListWebhooks
+func (h *WebhookHandler) ListWebhooksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving webhooks")
+			sendError(c, err, "Failed to retrieve webhooks")
+			return
+		}
+		items := make([]model.Webhook, len(hooks))
+		for i, hook := range hooks {
+			items[i] = handler.Redact(hook)
+		}
+		c.JSON(http.StatusOK, WebhookList{Items: items})
+	}
+}
+
+// GetWebhookHandler retrieves a webhook by ID.
+// @Summary      Get a webhook
+// @Description  Retrieves a webhook, without its signing secret.
+// @Tags         webhooks
+// @Produce      json
+// @Param        webhookId  path      string  true  "Webhook ID (UUID)"
+// @Success      200        {object}  model.Webhook     "Webhook details"
+// @Failure      400        {object}  response.Problem  "Invalid webhook id"
+// @Failure      404        {object}  response.Problem  "Webhook not found"
+// @Router       /api/v2/webhooks/{webhookId} [get]
+// @ID GetWebhook
+func (h *WebhookHandler) GetWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWebhookID(c)
+		if !ok {
+			return
+		}
+		hook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve webhook")
+			return
+		}
+		c.JSON(http.StatusOK, handler.Redact(hook))
+	}
+}
+
+// DeleteWebhookHandler removes a webhook and its delivery log.
+// @Summary      Delete a webhook
+// @Description  Removes a webhook and its delivery log.
+// @Tags         webhooks
+// @Param        webhookId  path  string  true  "Webhook ID (UUID)"
+// @Success      204        "Webhook deleted"
+// @Failure      400        {object}  response.Problem  "Invalid webhook id"
+// @Failure      404        {object}  response.Problem  "Webhook not found"
+// @Failure      500        {object}  response.Problem  "Failed to delete webhook"
+// @Router       /api/v2/webhooks/{webhookId} [delete]
+// @ID DeleteWebhook
+func (h *WebhookHandler) DeleteWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWebhookID(c)
+		if !ok {
+			return
+		}
+		if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete webhook")
+			sendError(c, err, "Failed to delete webhook")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListDeliveriesHandler returns the delivery log of a webhook.
+// @Summary      List webhook deliveries
+// @Description  Returns every delivery attempt for the webhook, newest first.
+// @Tags         webhooks
+// @Produce      json
+// @Param        webhookId  path      string  true  "Webhook ID (UUID)"
+// @Success      200        {object}  DeliveryList      "Delivery log"
+// @Failure      400        {object}  response.Problem  "Invalid webhook id"
+// @Failure      404        {object}  response.Problem  "Webhook not found"
+// @Router       /api/v2/webhooks/{webhookId}/deliveries [get]
+// @ID ListWebhookDeliveries
+func (h *WebhookHandler) ListDeliveriesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWebhookID(c)
+		if !ok {
+			return
+		}
+		deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve deliveries")
+			return
+		}
+		if deliveries == nil {
+			deliveries = []model.WebhookDelivery{}
+		}
+		c.JSON(http.StatusOK, DeliveryList{Items: deliveries})
+	}
+}
+
+// TestWebhookHandler sends a test event to a webhook.
+// @Summary      Test a webhook
+// @Description  Synchronously sends a signed webhook.test event once, without retries, and returns the recorded delivery.
+// @Tags         webhooks
+// @Produce      json
+// @Param        webhookId        path      string  true   "Webhook ID (UUID)"
+// @Param        Idempotency-Key  header    string  false  "Client generated key that identifies this request across retries"
+// @Success      200              {object}  model.WebhookDelivery  "Delivery result"
+// @Failure      400              {object}  response.Problem  "Invalid webhook id"
+// @Failure      404              {object}  response.Problem  "Webhook not found"
+// @Router       /api/v2/webhooks/{webhookId}/test [post]
+// @ID TestWebhook
+func (h *WebhookHandler) TestWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWebhookID(c)
+		if !ok {
+			return
+		}
+		hook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve webhook")
+			return
+		}
+		delivery := h.dispatcher.Deliver(c.Request.Context(), hook, events.Event{Type: handler.WebhookTestEvent, Time: time.Now().UTC()}, 1)
+		c.JSON(http.StatusOK, delivery)
+	}
+}
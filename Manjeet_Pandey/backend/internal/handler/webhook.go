@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"net/http"
+	"task_manager/internal/events"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/internal/webhook"
+	"task_manager/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookTestEvent is the event type sent by the test endpoint.
+const WebhookTestEvent events.Type = "webhook.test"
+
+// WebhookRequest is the body accepted when creating a webhook.
+type WebhookRequest struct {
+	URL    string   `json:"url" example:"https://example.com/hooks/tasks"`
+	Events []string `json:"events" example:"task.completed,task.failed"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	dispatcher     *webhook.Dispatcher
+}
+
+func NewWebhookHandler(service service.WebhookService, dispatcher *webhook.Dispatcher) WebhookHandler {
+	return WebhookHandler{
+		webhookService: service,
+		dispatcher:     dispatcher,
+	}
+}
+
+// Redact hides the signing secret, which is only shown once on creation.
+func Redact(hook model.Webhook) model.Webhook {
+	hook.Secret = ""
+	return hook
+}
+
+// CreateWebhookHandler registers a new webhook.
+// @Summary      Create a webhook
+// @Description  Subscribes a URL to task events. The signing secret is generated when omitted and is only returned in this response.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook  body      handler.WebhookRequest  true  "Webhook to create"
+// @Success      201      {object}  response.Response{data=model.Webhook}  "Created webhook"
+// @Failure      400      {object}  response.Problem   "Invalid request payload"
+// @Failure      500      {object}  response.Problem   "Failed to create webhook"
+// @Router       /api/v1/webhooks [post]
+// @ID CreateWebhook
+func (h *WebhookHandler) CreateWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendBindError(c, err)
+			return
+		}
+		hook, err := h.webhookService.CreateWebhook(c.Request.Context(), model.Webhook{URL: req.URL, Events: req.Events, Secret: req.Secret})
+		if err != nil {
+			logger(c).Err(err).Msg("Error creating webhook")
+			sendError(c, err, "Failed to create webhook")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusCreated, hook))
+	}
+}
+
+// ListWebhooksHandler lists every webhook.
+// @Summary      List webhooks
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.Webhook}  "List of webhooks"
+// @Failure      500  {object}  response.Problem   "Failed to retrieve webhooks"
+// @Router       /api/v1/webhooks [get]
+// @ID ListWebhooks
+func (h *WebhookHandler) ListWebhooksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+		if err != nil {
+			logger(c).Err(err).Msg("Error retrieving webhooks")
+			sendError(c, err, "Failed to retrieve webhooks")
+			return
+		}
+		for i := range hooks {
+			hooks[i] = Redact(hooks[i])
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, hooks))
+	}
+}
+
+// GetWebhookHandler retrieves a webhook by ID.
+// @Summary      Get a webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      200  {object}  response.Response{data=model.Webhook}  "Webhook details"
+// @Failure      400  {object}  response.Problem   "Invalid webhook id"
+// @Failure      404  {object}  response.Problem   "Webhook not found"
+// @Router       /api/v1/webhooks/{id} [get]
+// @ID GetWebhook
+func (h *WebhookHandler) GetWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "webhook")
+		if !ok {
+			return
+		}
+		hook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve webhook")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, Redact(hook)))
+	}
+}
+
+// DeleteWebhookHandler removes a webhook and its delivery log.
+// @Summary      Delete a webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      200  {object}  response.Response  "Webhook deleted"
+// @Failure      400  {object}  response.Problem   "Invalid webhook id"
+// @Failure      404  {object}  response.Problem   "Webhook not found"
+// @Router       /api/v1/webhooks/{id} [delete]
+// @ID DeleteWebhook
+func (h *WebhookHandler) DeleteWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "webhook")
+		if !ok {
+			return
+		}
+		if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+			logger(c).Err(err).Msg("Failed to delete webhook")
+			sendError(c, err, "Failed to delete webhook")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, "Deleted successfully"))
+	}
+}
+
+// ListDeliveriesHandler returns the delivery log of a webhook.
+// @Summary      List webhook deliveries
+// @Description  Returns every delivery attempt for the webhook, newest first.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      200  {object}  response.Response{data=[]model.WebhookDelivery}  "Delivery log"
+// @Failure      400  {object}  response.Problem   "Invalid webhook id"
+// @Failure      404  {object}  response.Problem   "Webhook not found"
+// @Router       /api/v1/webhooks/{id}/deliveries [get]
+// @ID ListWebhookDeliveries
+func (h *WebhookHandler) ListDeliveriesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "webhook")
+		if !ok {
+			return
+		}
+		deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve deliveries")
+			return
+		}
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, deliveries))
+	}
+}
+
+// TestWebhookHandler sends a test event to a webhook.
+// @Summary      Test a webhook
+// @Description  Synchronously sends a signed webhook.test event once, without retries, and returns the recorded delivery.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      200  {object}  response.Response{data=model.WebhookDelivery}  "Delivery result"
+// @Failure      400  {object}  response.Problem   "Invalid webhook id"
+// @Failure      404  {object}  response.Problem   "Webhook not found"
+// @Router       /api/v1/webhooks/{id}/test [post]
+// @ID TestWebhook
+func (h *WebhookHandler) TestWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "webhook")
+		if !ok {
+			return
+		}
+		hook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+		if err != nil {
+			sendError(c, err, "Failed to retrieve webhook")
+			return
+		}
+		delivery := h.dispatcher.Deliver(c.Request.Context(), hook, events.Event{Type: WebhookTestEvent, Time: time.Now().UTC()}, 1)
+		sendResponse(c, response.NewSuccessResponse(http.StatusOK, delivery))
+	}
+}
@@ -0,0 +1,55 @@
+// Package logging configures the global zerolog logger and carries
+// request-scoped loggers through context.Context.
+package logging
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const RequestIDField = "request_id"
+
+type requestIDKey struct{}
+
+// Setup sets the global level and output format. Unknown levels fall back
+// to info; any format other than console writes JSON.
+func Setup(level, format string) {
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || level == "" {
+		lvl = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(lvl)
+
+	if format == "console" {
+		log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	} else {
+		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	}
+	zerolog.DefaultContextLogger = &log.Logger
+	if err != nil && level != "" {
+		log.Warn().Str("level", level).Msg("Unknown log level, using info")
+	}
+}
+
+// WithRequestID stores the request ID in ctx together with a logger that
+// adds it to every entry.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	logger := log.Logger.With().Str(RequestIDField, id).Logger()
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return logger.WithContext(ctx)
+}
+
+// RequestID returns the request ID stored in ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Ctx returns the logger carried by ctx, or the global logger.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
+}
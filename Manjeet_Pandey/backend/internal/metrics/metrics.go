@@ -0,0 +1,153 @@
+// Package metrics exposes Prometheus metrics for the API and the worker.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"task_manager/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "task_manager"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	TasksProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "worker_tasks_processed_total",
+		Help:      "Tasks the worker completed.",
+	})
+
+	TasksFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "worker_tasks_failed_total",
+		Help:      "Tasks the worker failed to process.",
+	})
+
+	ProcessingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "worker_task_duration_seconds",
+		Help:      "Time the worker spent processing a task.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	queues = &queueCollector{
+		desc:   prometheus.NewDesc(namespace+"_worker_queue_depth", "Task IDs waiting in worker queues.", nil, nil),
+		depths: make(map[any]func() int),
+	}
+)
+
+func init() {
+	prometheus.MustRegister(queues)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware counts requests and measures their latency. Routes are
+// labelled by their pattern, not the raw path, to keep cardinality bounded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// TrackQueue adds a worker queue to the queue depth gauge until UntrackQueue
+// is called with the same key.
+func TrackQueue(key any, depth func() int) {
+	queues.mu.Lock()
+	defer queues.mu.Unlock()
+	queues.depths[key] = depth
+}
+
+func UntrackQueue(key any) {
+	queues.mu.Lock()
+	defer queues.mu.Unlock()
+	delete(queues.depths, key)
+}
+
+// queueCollector reports the combined length of every tracked worker queue
+// at scrape time.
+type queueCollector struct {
+	desc   *prometheus.Desc
+	mu     sync.Mutex
+	depths map[any]func() int
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- q.desc
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	q.mu.Lock()
+	total := 0
+	for _, depth := range q.depths {
+		total += depth()
+	}
+	q.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(q.desc, prometheus.GaugeValue, float64(total))
+}
+
+// StatusCounter reports how many tasks are in each status.
+type StatusCounter interface {
+	CountByStatus(ctx context.Context) (map[model.TaskStatus]int64, error)
+}
+
+// RegisterTaskStatus exposes the number of tasks per status, queried from
+// the database at scrape time.
+func RegisterTaskStatus(counter StatusCounter) {
+	prometheus.MustRegister(&statusCollector{
+		counter: counter,
+		desc:    prometheus.NewDesc(namespace+"_tasks", "Tasks by status.", []string{"status"}, nil),
+	})
+}
+
+type statusCollector struct {
+	counter StatusCounter
+	desc    *prometheus.Desc
+}
+
+func (s *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := s.counter.CountByStatus(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(s.desc, err)
+		return
+	}
+	for _, status := range []model.TaskStatus{model.StatusPending, model.StatusCompleted} {
+		ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
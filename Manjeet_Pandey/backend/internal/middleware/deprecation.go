@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes a deprecated API version.
+type Deprecation struct {
+	// Since is when the version was deprecated.
+	Since time.Time
+	// Sunset is when it will be removed. The zero time means no date has
+	// been set yet.
+	Sunset time.Time
+	// Successor is the base URL of the version that replaces it.
+	Successor string
+}
+
+// DeprecationMiddleware announces that the routes it guards are deprecated
+// with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links
+// to the successor version.
+func DeprecationMiddleware(d Deprecation) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	var sunset string
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	link := "<" + d.Successor + `>; rel="successor-version"`
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("Deprecation", deprecation)
+		if sunset != "" {
+			h.Set("Sunset", sunset)
+		}
+		h.Add("Link", link)
+		c.Next()
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"task_manager/internal/logging"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses answered from storage.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored with the body and sent
+// again on replay. Headers that describe the request being answered, such
+// as rate limits, are left to the retry.
+var replayedHeaders = []string{"Location", "ETag", "Last-Modified", "Content-Disposition"}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key safe to
+// retry. The first response is stored and returned again, with its
+// replayedHeaders, for retries with the same key, method, path, Accept and
+// Content-Type headers and body. Reusing a key for a different
+// request gets 422, and a retry that arrives while the first request is
+// still running gets 409. Server errors aren't stored, so they can be
+// retried. Requests without the header are passed through.
+//
+// It reads the whole body, so it must run after BodyLimitMiddleware.
+func IdempotencyMiddleware(store service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abort(c, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abort(c, http.StatusRequestEntityTooLarge, "Request body too large")
+				return
+			}
+			abort(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		logger := logging.Ctx(ctx)
+		client := c.ClientIP()
+		stored, err := store.Begin(ctx, client, key, fingerprint(c.Request, body))
+		switch {
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			abort(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		case errors.Is(err, service.ErrIdempotencyInFlight):
+			abort(c, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			logger.Err(err).Msg("Cannot look up idempotency key")
+			abort(c, http.StatusInternalServerError, "Failed to check the idempotency key")
+			return
+		case stored != nil:
+			for name, values := range stored.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			// Also runs when a handler panics, so the key isn't left
+			// claimed until it expires.
+			if completed {
+				return
+			}
+			if err := store.Release(context.WithoutCancel(ctx), client, key); err != nil {
+				logger.Err(err).Msg("Cannot release idempotency key")
+			}
+		}()
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		header := http.Header{}
+		for _, name := range replayedHeaders {
+			if values := recorder.Header().Values(name); len(values) > 0 {
+				header[name] = values
+			}
+		}
+		err = store.Complete(context.WithoutCancel(ctx), client, key, status, recorder.Header().Get("Content-Type"), header, recorder.body.Bytes())
+		if err != nil {
+			logger.Err(err).Msg("Cannot store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+func abort(c *gin.Context, status int, msg string) {
+	response.Abort(c, status, msg)
+}
+
+// fingerprint identifies a request by its method, path, body and the
+// headers that change how it is read or answered: Content-Type, and Accept,
+// which picks the version 1 envelope. The version itself is in the path.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	io.WriteString(h, "Accept: "+r.Header.Get("Accept")+"\n")
+	io.WriteString(h, "Content-Type: "+r.Header.Get("Content-Type")+"\n\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body as it is written.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"task_manager/internal/config"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is how often buckets of clients that went quiet are
+// dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps one token bucket per client for a route group.
+type rateLimiter struct {
+	limit     config.RateLimit
+	every     rate.Limit
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// RateLimitMiddleware limits each client to limit on the routes it is
+// attached to. Every response carries RateLimit-Policy, RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; rejected requests get
+// 429 with Retry-After. The headers describe the bucket: the burst is the
+// quota, and the window the time an empty bucket takes to refill.
+//
+// There is no authentication, so clients are told apart by IP address.
+// Headers a client chooses, such as an API key, are never used, since a
+// client could change them to get a fresh bucket.
+func RateLimitMiddleware(limit config.RateLimit) gin.HandlerFunc {
+	if !limit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	l := newRateLimiter(limit)
+	policy := fmt.Sprintf("%d;w=%d", limit.Burst, l.secondsUntil(float64(limit.Burst)))
+	return func(c *gin.Context) {
+		now := time.Now()
+		limiter := l.limiter(c.ClientIP(), now)
+		allowed := limiter.AllowN(now, 1)
+		tokens := limiter.TokensAt(now)
+
+		h := c.Writer.Header()
+		h.Set("RateLimit-Policy", policy)
+		h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		h.Set("RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+		h.Set("RateLimit-Reset", strconv.Itoa(l.secondsUntil(float64(limit.Burst)-tokens)))
+		if !allowed {
+			retry := l.secondsUntil(1 - tokens)
+			h.Set("Retry-After", strconv.Itoa(retry))
+			abort(c, http.StatusTooManyRequests, fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retry))
+			return
+		}
+		c.Next()
+	}
+}
+
+func newRateLimiter(limit config.RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		every:   rate.Limit(float64(limit.Requests) / limit.Period.Seconds()),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// limiter returns the bucket for key, creating it full on first use.
+func (l *rateLimiter) limiter(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.every, l.limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// sweep drops buckets that have refilled completely since they were last
+// used; recreating them gives the same result.
+func (l *rateLimiter) sweep(now time.Time) {
+	full := time.Duration(float64(l.limit.Burst) / float64(l.every) * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > full {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// secondsUntil is how long, rounded up, the bucket takes to gain tokens.
+func (l *rateLimiter) secondsUntil(tokens float64) int {
+	if tokens <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / float64(l.every)))
+}
+
+// BodyLimitMiddleware rejects request bodies larger than max bytes with
+// 413. Bodies without a Content-Length are cut off while they are read,
+// which handlers report through sendBindError or sendError.
+func BodyLimitMiddleware(max int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > max {
+			abort(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large, the limit is %d bytes", max))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+		c.Next()
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"task_manager/internal/config"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// limited serves GET / behind a rate limit.
+func limited(limit config.RateLimit) *gin.Engine {
+	r := gin.New()
+	r.SetTrustedProxies(nil)
+	r.GET("/", RateLimitMiddleware(limit), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func get(r *gin.Engine, ip string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = ip + ":1234"
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitHeadersDescribeOneQuota(t *testing.T) {
+	limit, err := config.ParseRateLimit("10/s:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := limited(limit)
+	rec := get(r, "192.0.2.1")
+	// 20 requests, refilled in 2 seconds at 10 a second.
+	if got := rec.Header().Get("RateLimit-Limit"); got != "20" {
+		t.Errorf("RateLimit-Limit is %s, want 20", got)
+	}
+	if got := rec.Header().Get("RateLimit-Policy"); got != "20;w=2" {
+		t.Errorf("RateLimit-Policy is %s, want 20;w=2", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "19" {
+		t.Errorf("RateLimit-Remaining is %s, want 19", got)
+	}
+	for i := 2; i <= 20; i++ {
+		if rec = get(r, "192.0.2.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d got %d within the burst", i, rec.Code)
+		}
+	}
+	rec = get(r, "192.0.2.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the burst got %d", rec.Code)
+	}
+	if retry, _ := strconv.Atoi(rec.Header().Get("Retry-After")); retry < 1 {
+		t.Errorf("Retry-After is %q", rec.Header().Get("Retry-After"))
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining is %s when rejected, want 0", got)
+	}
+}
+
+func TestRateLimitPerAddress(t *testing.T) {
+	r := limited(config.RateLimit{Requests: 1, Period: time.Minute, Burst: 2})
+	for i, key := range []string{"a", "b"} {
+		if rec := get(r, "192.0.2.1", "X-API-Key", key); rec.Code != http.StatusOK {
+			t.Fatalf("request %d got %d", i+1, rec.Code)
+		}
+	}
+	// A new key, or a forwarded address, doesn't buy a new bucket.
+	if rec := get(r, "192.0.2.1", "X-API-Key", "c", "X-Forwarded-For", "198.51.100.7"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("request with a fresh key got %d", rec.Code)
+	}
+	if rec := get(r, "192.0.2.2"); rec.Code != http.StatusOK {
+		t.Errorf("another address got %d", rec.Code)
+	}
+}
+
+func TestIdleBucketsAreEvicted(t *testing.T) {
+	l := newRateLimiter(config.RateLimit{Requests: 10, Period: time.Second, Burst: 20})
+	start := time.Now()
+	l.limiter("192.0.2.1", start)
+	l.limiter("192.0.2.2", start.Add(sweepInterval))
+	// The first bucket has been idle longer than it takes to refill.
+	l.limiter("192.0.2.3", start.Add(sweepInterval+time.Second+time.Millisecond))
+	if _, ok := l.buckets["192.0.2.1"]; ok {
+		t.Error("idle bucket was kept")
+	}
+	if len(l.buckets) != 2 {
+		t.Errorf("%d buckets left, want 2", len(l.buckets))
+	}
+}
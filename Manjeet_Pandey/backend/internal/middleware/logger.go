@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"task_manager/internal/logging"
+	"task_manager/internal/tracing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client supplied IDs so they can't bloat logs.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware propagates the caller's X-Request-ID or assigns a new
+// one. The ID is echoed in the response and attached, with a logger that
+// carries it and the trace ID, to the request context handed to the
+// services. It must run after the tracing middleware.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, id)
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(tracing.WithLogger(ctx))
+		c.Next()
+	}
+}
+
+// LoggerMiddleware writes one structured entry per request. It must run
+// after RequestIDMiddleware so the entry carries the request ID.
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		var event *zerolog.Event
+		logger := logging.Ctx(c.Request.Context())
+		switch {
+		case status >= 500:
+			event = logger.Error()
+		case status >= 400:
+			event = logger.Warn()
+		default:
+			event = logger.Info()
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		if len(c.Errors) > 0 {
+			event = event.Str("errors", c.Errors.String())
+		}
+		event.
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Str("path", c.Request.URL.Path).
+			Int("status", status).
+			Dur("latency", time.Since(start)).
+			Str("client_ip", c.ClientIP()).
+			Int("bytes", c.Writer.Size()).
+			Msg("Request")
+	}
+}
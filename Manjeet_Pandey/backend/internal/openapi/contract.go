@@ -0,0 +1,274 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Spec is an OpenAPI 3.1 document that responses can be checked against.
+type Spec struct {
+	name       string
+	operations map[string]Operation
+
+	compiler *jsonschema.Compiler
+	mu       sync.Mutex
+	schemas  map[string]*jsonschema.Schema
+}
+
+// Operation is one method on one path of a Spec.
+type Operation struct {
+	Method string
+	Path   string
+	// responses maps status codes, or default, to response objects.
+	responses map[string]any
+}
+
+func (s *Spec) String() string {
+	return s.name
+}
+
+func (o Operation) String() string {
+	return o.Method + " " + o.Path
+}
+
+// Load reads an OpenAPI document; name identifies it in errors.
+func Load(name string, raw []byte) (*Spec, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(name, doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	s := &Spec{
+		name:       name,
+		operations: map[string]Operation{},
+		compiler:   compiler,
+		schemas:    map[string]*jsonschema.Schema{},
+	}
+	paths, _ := doc.(map[string]any)["paths"].(map[string]any)
+	for path, item := range paths {
+		methods, _ := item.(map[string]any)
+		for method, op := range methods {
+			responses, _ := op.(map[string]any)["responses"].(map[string]any)
+			o := Operation{Method: strings.ToUpper(method), Path: path, responses: responses}
+			s.operations[routeKey(o.Method, path)] = o
+		}
+	}
+	return s, nil
+}
+
+// Operations lists the operations of the spec, sorted by path and method.
+func (s *Spec) Operations() []Operation {
+	ops := make([]Operation, 0, len(s.operations))
+	for _, op := range s.operations {
+		ops = append(ops, op)
+	}
+	slices.SortFunc(ops, func(a, b Operation) int {
+		return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+	})
+	return ops
+}
+
+// Route finds the operation for a Gin route such as GET /tasks/:id.
+func (s *Spec) Route(method, route string) (Operation, bool) {
+	op, ok := s.operations[routeKey(method, route)]
+	return op, ok
+}
+
+// Match finds the operation that serves a request for path. Like the
+// router, it prefers literal segments, so /tasks/export isn't taken for
+// /tasks/{id}.
+func (s *Spec) Match(method, path string) (Operation, bool) {
+	segments := strings.Split(path, "/")
+	var (
+		best       Operation
+		bestParams = -1
+	)
+	for _, op := range s.operations {
+		if op.Method != method {
+			continue
+		}
+		params, ok := matchPath(strings.Split(op.Path, "/"), segments)
+		if ok && (bestParams < 0 || params < bestParams) {
+			best, bestParams = op, params
+		}
+	}
+	return best, bestParams >= 0
+}
+
+// CheckResponse reports how a response to op differs from the spec: an
+// undocumented status or media type, a body where none is documented, or
+// a JSON body that doesn't match its schema.
+func (s *Spec) CheckResponse(op Operation, status int, header http.Header, body []byte) error {
+	code := strconv.Itoa(status)
+	resp, ok := op.responses[code]
+	if !ok {
+		if resp, ok = op.responses["default"]; !ok {
+			return fmt.Errorf("status %d is not documented", status)
+		}
+		code = "default"
+	}
+	content, _ := resp.(map[string]any)["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("status %d is documented without a body, but got %d bytes", status, len(body))
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("status %d: invalid Content-Type %q", status, header.Get("Content-Type"))
+	}
+	// Ranges such as image/* or */* document any matching type.
+	key := mediaType
+	media, ok := content[key]
+	for _, wildcard := range []string{strings.SplitN(mediaType, "/", 2)[0] + "/*", "*/*"} {
+		if ok {
+			break
+		}
+		key = wildcard
+		media, ok = content[key]
+	}
+	if !ok {
+		documented := make([]string, 0, len(content))
+		for t := range content {
+			documented = append(documented, t)
+		}
+		slices.Sort(documented)
+		return fmt.Errorf("status %d: %s is not documented, only %s", status, mediaType, strings.Join(documented, ", "))
+	}
+	if _, ok := media.(map[string]any)["schema"]; !ok || !isJSON(mediaType) {
+		return nil
+	}
+	schema, err := s.schema(jsonPointer("paths", op.Path, strings.ToLower(op.Method), "responses", code, "content", key, "schema"))
+	if err != nil {
+		return err
+	}
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("status %d: body is not JSON: %w", status, err)
+	}
+	if err := schema.Validate(value); err != nil {
+		var invalid *jsonschema.ValidationError
+		if errors.As(err, &invalid) {
+			return fmt.Errorf("status %d: body doesn't match the schema: %s", status, strings.TrimSpace(fmt.Sprintf("%v", invalid)))
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Spec) schema(pointer string) (*jsonschema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if schema, ok := s.schemas[pointer]; ok {
+		return schema, nil
+	}
+	ref := s.name + "#" + (&url.URL{Fragment: pointer}).EscapedFragment()
+	schema, err := s.compiler.Compile(ref)
+	if err != nil {
+		return nil, fmt.Errorf("compile %s: %w", pointer, err)
+	}
+	s.schemas[pointer] = schema
+	return schema, nil
+}
+
+// CheckRoutes compares the routes registered on a Gin engine with the
+// operations of specs. It reports routes no spec documents and operations
+// no route serves. Routes for which ignore returns true aren't checked.
+func CheckRoutes(routes gin.RoutesInfo, specs []*Spec, ignore func(method, path string) bool) []error {
+	var errs []error
+	served := map[string]bool{}
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+		served[key] = true
+		if ignore != nil && ignore(route.Method, route.Path) {
+			continue
+		}
+		documented := false
+		for _, spec := range specs {
+			if _, ok := spec.operations[key]; ok {
+				documented = true
+				break
+			}
+		}
+		if !documented {
+			errs = append(errs, fmt.Errorf("%s %s is served but not documented", route.Method, route.Path))
+		}
+	}
+	for _, spec := range specs {
+		for _, op := range spec.Operations() {
+			if !served[routeKey(op.Method, op.Path)] {
+				errs = append(errs, fmt.Errorf("%s: %s is documented but not served", spec.name, op))
+			}
+		}
+	}
+	return errs
+}
+
+// routeKey identifies a route regardless of how its parameters are named
+// or written: /tasks/:id and /tasks/{task_id} have the same key.
+func routeKey(method, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isParam(segment) {
+			segments[i] = "{}"
+		}
+	}
+	return method + " " + strings.Join(segments, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") ||
+		(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+}
+
+// matchPath reports whether segments match template, and how many of them
+// were parameters.
+func matchPath(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+	params := 0
+	for i, t := range template {
+		if isParam(t) {
+			if segments[i] == "" {
+				return 0, false
+			}
+			params++
+			continue
+		}
+		if t != segments[i] {
+			return 0, false
+		}
+	}
+	return params, true
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonPointer joins tokens into an RFC 6901 pointer.
+func jsonPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+// Package openapi turns the Swagger 2.0 documents generated by swag into
+// OpenAPI 3.1, and checks the API against them.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"task_manager/internal/response"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+)
+
+// Version is the OpenAPI version of the converted documents.
+const Version = "3.1.0"
+
+// Convert converts a Swagger 2.0 document to OpenAPI 3.1. The result is
+// indented JSON, stable across runs so it can be checked in.
+func Convert(swagger []byte) ([]byte, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(swagger, &doc2); err != nil {
+		return nil, fmt.Errorf("read the Swagger document: %w", err)
+	}
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("convert to OpenAPI 3: %w", err)
+	}
+	raw, err := json.Marshal(doc3)
+	if err != nil {
+		return nil, err
+	}
+
+	// kin-openapi writes OpenAPI 3.0. The differences that matter for these
+	// documents are how schemas mark null and examples.
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	doc["openapi"] = Version
+	// The Swagger host is only a placeholder, and without schemes kin-openapi
+	// guesses https. A relative URL points clients at wherever the
+	// document was served from.
+	basePath := doc2.BasePath
+	if basePath == "" {
+		basePath = "/"
+	}
+	doc["servers"] = []any{map[string]any{"url": basePath}}
+	if components, ok := doc["components"].(map[string]any); ok {
+		if schemas, ok := components["schemas"].(map[string]any); ok {
+			for _, schema := range schemas {
+				upgradeSchema(schema)
+			}
+		}
+	}
+	walkSchemas(doc["paths"], upgradeSchema)
+	markProblems(doc["paths"])
+
+	// The top level keys keep the conventional order; nested objects are
+	// sorted by encoding/json.
+	var out bytes.Buffer
+	out.WriteString("{\n")
+	first := true
+	for _, key := range []string{"openapi", "info", "servers", "tags", "paths", "components"} {
+		value, ok := doc[key]
+		if !ok {
+			continue
+		}
+		encoded, err := json.MarshalIndent(value, "    ", "    ")
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			out.WriteString(",\n")
+		}
+		first = false
+		fmt.Fprintf(&out, "    %q: %s", key, encoded)
+	}
+	out.WriteString("\n}\n")
+	return out.Bytes(), nil
+}
+
+// problemRef is the schema of error responses.
+const problemRef = "#/components/schemas/response.Problem"
+
+// markProblems files error responses under application/problem+json, the
+// media type they are sent with. swag can only give an operation a single
+// media type for every response.
+func markProblems(paths any) {
+	pathItems, _ := paths.(map[string]any)
+	for _, item := range pathItems {
+		operations, _ := item.(map[string]any)
+		for _, op := range operations {
+			responses, _ := op.(map[string]any)["responses"].(map[string]any)
+			for _, resp := range responses {
+				content, _ := resp.(map[string]any)["content"].(map[string]any)
+				for mediaType, media := range content {
+					schema, _ := media.(map[string]any)["schema"].(map[string]any)
+					if schema["$ref"] == problemRef {
+						delete(content, mediaType)
+						content[response.ProblemContentType] = media
+					}
+				}
+			}
+		}
+	}
+}
+
+// walkSchemas calls upgrade on every schema object under node: the values
+// of "schema" keys in parameters, request bodies and responses.
+func walkSchemas(node any, upgrade func(any)) {
+	switch n := node.(type) {
+	case map[string]any:
+		for key, value := range n {
+			if key == "schema" {
+				upgrade(value)
+				continue
+			}
+			walkSchemas(value, upgrade)
+		}
+	case []any:
+		for _, value := range n {
+			walkSchemas(value, upgrade)
+		}
+	}
+}
+
+// upgradeSchema rewrites a 3.0 schema, and the schemas nested in it, as a
+// JSON Schema 2020-12 schema: nullable becomes a null type and example
+// becomes examples.
+func upgradeSchema(node any) {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []any{typ, "null"}
+		}
+	}
+	delete(schema, "nullable")
+	if example, ok := schema["example"]; ok {
+		schema["examples"] = []any{example}
+		delete(schema, "example")
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		upgradeSchema(schema[key])
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list, ok := schema[key].([]any); ok {
+			for _, s := range list {
+				upgradeSchema(s)
+			}
+		}
+	}
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for _, s := range props {
+			upgradeSchema(s)
+		}
+	}
+}
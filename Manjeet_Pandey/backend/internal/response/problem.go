@@ -0,0 +1,151 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"task_manager/internal/logging"
+	"task_manager/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ProblemContentType is the media type of RFC 7807 error responses.
+	ProblemContentType = "application/problem+json"
+	// LegacyMediaType selects the original envelope, which reports errors in
+	// its error field, for clients that haven't moved to problem details.
+	LegacyMediaType = "application/vnd.task-manager.v1+json"
+)
+
+// Problem is an RFC 7807 problem details object. Errors and RequestID are
+// extension members.
+type Problem struct {
+	// Type identifies the kind of problem; see ProblemTypes.
+	Type   string `json:"type" example:"/problems/not-found"`
+	Title  string `json:"title" example:"Not Found"`
+	Status int    `json:"status" example:"404"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty" example:"task 5f0c1d9e-8b7a-4c1e-9f1a-2b3c4d5e6f70 not found"`
+	// Instance is the path of the request that failed.
+	Instance string `json:"instance,omitempty" example:"/tasks/5f0c1d9e-8b7a-4c1e-9f1a-2b3c4d5e6f70"`
+	// Errors lists the invalid fields when a request fails validation.
+	Errors    []validation.FieldError `json:"errors,omitempty"`
+	RequestID string                  `json:"request_id,omitempty" example:"8d7f5a0e-2c4b-4e55-a1f3-0b6c9d2e7a41"`
+}
+
+// ProblemType documents one of the problem type URIs.
+type ProblemType struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+const problemTypeBase = "/problems/"
+
+// ProblemTypes lists the problem types the API reports, keyed by the last
+// segment of their URI. Each is served at its URI.
+var ProblemTypes = map[string]ProblemType{}
+
+// statusProblems picks the problem type for a status code.
+var statusProblems = map[int]string{}
+
+func init() {
+	for _, p := range []struct {
+		status      int
+		name        string
+		description string
+	}{
+		{http.StatusBadRequest, "bad-request", "The request could not be read: malformed JSON, an invalid ID or query parameter, or an unsupported value."},
+		{http.StatusNotFound, "not-found", "The resource does not exist."},
+		{http.StatusConflict, "conflict", "The request clashes with existing data, or a request with the same Idempotency-Key is still running."},
+		{http.StatusRequestEntityTooLarge, "payload-too-large", "The request body is larger than the server accepts."},
+		{http.StatusUnsupportedMediaType, "unsupported-media-type", "The request body is in a format the endpoint does not accept."},
+		{http.StatusUnprocessableEntity, "idempotency-key-reused", "The Idempotency-Key was already used for a different request."},
+		{http.StatusTooManyRequests, "rate-limited", "The client sent too many requests. Retry after the number of seconds in Retry-After."},
+		{http.StatusInternalServerError, "internal-error", "The server failed to handle the request. Retrying may succeed."},
+		{http.StatusServiceUnavailable, "unavailable", "The server is not ready to handle requests."},
+	} {
+		statusProblems[p.status] = p.name
+		registerProblemType(p.name, http.StatusText(p.status), p.description)
+	}
+	registerProblemType(validationProblem, "Validation Failed", "One or more fields are invalid. The errors member lists each field, the rule it broke and a message.")
+}
+
+const validationProblem = "validation-error"
+
+func registerProblemType(name, title, description string) {
+	ProblemTypes[name] = ProblemType{Type: problemTypeBase + name, Title: title, Description: description}
+}
+
+// NewProblem describes an error response as problem details.
+func NewProblem(c *gin.Context, resp Response) Problem {
+	name, ok := statusProblems[resp.Status]
+	if len(resp.Errors) > 0 {
+		name, ok = validationProblem, true
+	}
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(resp.Status),
+		Status:    resp.Status,
+		Detail:    resp.Error,
+		Instance:  c.Request.URL.Path,
+		Errors:    resp.Errors,
+		RequestID: logging.RequestID(c.Request.Context()),
+	}
+	if ok {
+		problem.Type = ProblemTypes[name].Type
+		problem.Title = ProblemTypes[name].Title
+	}
+	return problem
+}
+
+// Send writes resp in the format the client asked for. Errors are problem
+// details unless the Accept header asks for LegacyMediaType.
+func Send(c *gin.Context, resp Response) {
+	c.Writer.Header().Add("Vary", "Accept")
+	switch {
+	case wantsLegacy(c.Request):
+		c.Header("Content-Type", LegacyMediaType)
+		c.JSON(resp.Status, legacyResponse{
+			Status: resp.Status,
+			Data:   resp.Data,
+			Error:  resp.Error,
+			Errors: resp.Errors,
+		})
+	case resp.Status >= http.StatusBadRequest:
+		SendProblem(c, resp)
+	default:
+		c.JSON(resp.Status, resp)
+	}
+}
+
+// SendProblem writes the error response resp as problem details.
+func SendProblem(c *gin.Context, resp Response) {
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(resp.Status, NewProblem(c, resp))
+}
+
+// Abort writes an error response with Send and stops the handler chain.
+func Abort(c *gin.Context, status int, msg string) {
+	Send(c, NewErrorResponse(status, msg))
+	c.Abort()
+}
+
+// legacyResponse is the original envelope, which always has an error field.
+type legacyResponse struct {
+	Status int                     `json:"status"`
+	Data   any                     `json:"data"`
+	Error  string                  `json:"error"`
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+func wantsLegacy(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == LegacyMediaType {
+			return true
+		}
+	}
+	return false
+}
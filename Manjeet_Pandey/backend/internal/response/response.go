@@ -1,9 +1,16 @@
 package response
 
+import "task_manager/internal/validation"
+
+// Response is the envelope for successful responses. Errors are sent as
+// Problem details, see Send, so Error and Errors only carry the message and
+// field errors a Problem is built from.
 type Response struct {
 	Status int    `json:"status"`
 	Data   any    `json:"data"`
-	Error  string `json:"error"`
+	Error  string `json:"error,omitempty" swaggerignore:"true"`
+	// Errors lists the invalid fields when a request fails validation.
+	Errors []validation.FieldError `json:"errors,omitempty" swaggerignore:"true"`
 }
 
 func NewSuccessResponse(status int, data interface{}) Response {
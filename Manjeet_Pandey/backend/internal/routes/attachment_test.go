@@ -0,0 +1,128 @@
+package routes_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"task_manager/model"
+	"testing"
+
+	v2 "task_manager/internal/handler/v2"
+)
+
+const pngImage = "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00"
+
+// upload posts contents as the file field of a multipart form.
+func (s *testServer) upload(path, fileName, contents string) *httptest.ResponseRecorder {
+	return s.do("POST", path, formFile("file", fileName, contents), "Content-Type", multipartForm["Content-Type"])
+}
+
+func TestAttachmentUpload(t *testing.T) {
+	s := newTestServer(t)
+	task := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "with files"}`))
+	attachments := "/api/v2/tasks/" + task.ID.String() + "/attachments"
+
+	rec := s.upload(attachments, "screen.png", pngImage)
+	expect(t, rec, http.StatusCreated)
+	created := decode[model.Attachment](t, rec)
+	if want := attachments + "/" + created.ID.String(); rec.Header().Get("Location") != want {
+		t.Errorf("Location is %q, want %q", rec.Header().Get("Location"), want)
+	}
+	digest := sha256.Sum256([]byte(pngImage))
+	if created.SHA256 != hex.EncodeToString(digest[:]) {
+		t.Errorf("sha256 is %s, want %x", created.SHA256, digest)
+	}
+	if created.ContentType != "image/png" || created.Size != int64(len(pngImage)) || created.FileName != "screen.png" || created.TaskID != task.ID {
+		t.Errorf("got %+v", created)
+	}
+
+	got := decode[model.Attachment](t, s.do("GET", attachments+"/"+created.ID.String(), ""))
+	if got.ID != created.ID || got.SHA256 != created.SHA256 {
+		t.Errorf("metadata is %+v, want %+v", got, created)
+	}
+	list := decode[v2.AttachmentList](t, s.do("GET", attachments, ""))
+	if len(list.Items) != 1 || list.Items[0].ID != created.ID {
+		t.Errorf("list is %+v", list.Items)
+	}
+}
+
+func TestAttachmentRejected(t *testing.T) {
+	s := newTestServer(t)
+	task := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "with files"}`))
+	attachments := "/api/v2/tasks/" + task.ID.String() + "/attachments"
+
+	// Too large for MaxAttachmentBytes.
+	expectProblem(t, s.upload(attachments, "big.txt", strings.Repeat("x", int(s.cfg.MaxAttachmentBytes)+1)), http.StatusRequestEntityTooLarge)
+	// The type is detected from the contents, not the file name.
+	expectProblem(t, s.upload(attachments, "notes.txt", "MZ\x90\x00\x03\x00\x00\x00"), http.StatusUnsupportedMediaType)
+	expectProblem(t, s.do("POST", attachments, "notes", "Content-Type", "text/plain"), http.StatusUnsupportedMediaType)
+	expectProblem(t, s.do("POST", attachments, formFile("upload", "screen.png", pngImage), "Content-Type", multipartForm["Content-Type"]), http.StatusBadRequest)
+	expectProblem(t, s.upload("/api/v2/tasks/00000000-0000-0000-0000-000000000001/attachments", "screen.png", pngImage), http.StatusNotFound)
+
+	if list := decode[v2.AttachmentList](t, s.do("GET", attachments, "")); len(list.Items) != 0 {
+		t.Errorf("%d attachments stored after rejected uploads", len(list.Items))
+	}
+}
+
+func TestAttachmentDownload(t *testing.T) {
+	s := newTestServer(t)
+	task := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "with files"}`))
+	attachments := "/api/v2/tasks/" + task.ID.String() + "/attachments"
+	image := decode[model.Attachment](t, s.upload(attachments, "screen.png", pngImage))
+	text := decode[model.Attachment](t, s.upload(attachments, "build.log", "line one\nline two\n"))
+
+	content := attachments + "/" + image.ID.String() + "/content"
+	rec := s.do("GET", content, "")
+	expect(t, rec, http.StatusOK)
+	if rec.Body.String() != pngImage {
+		t.Errorf("got %q, want the uploaded contents", rec.Body.String())
+	}
+	header := rec.Header()
+	if header.Get("Content-Type") != "image/png" {
+		t.Errorf("Content-Type is %q", header.Get("Content-Type"))
+	}
+	if want := `inline; filename=screen.png`; header.Get("Content-Disposition") != want {
+		t.Errorf("Content-Disposition is %q, want %q", header.Get("Content-Disposition"), want)
+	}
+	if want := `"` + image.SHA256 + `"`; header.Get("ETag") != want {
+		t.Errorf("ETag is %q, want %q", header.Get("ETag"), want)
+	}
+	if header.Get("X-Content-Type-Options") != "nosniff" {
+		t.Error("download can be sniffed")
+	}
+
+	// Anything but an image is a download.
+	rec = s.do("GET", attachments+"/"+text.ID.String()+"/content", "")
+	expect(t, rec, http.StatusOK)
+	if disposition := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment;") {
+		t.Errorf("Content-Disposition is %q, want an attachment", disposition)
+	}
+
+	rec = s.do("GET", content, "", "Range", "bytes=0-7")
+	expect(t, rec, http.StatusPartialContent)
+	if rec.Body.String() != pngImage[:8] {
+		t.Errorf("range got %q, want %q", rec.Body.String(), pngImage[:8])
+	}
+	if want := "bytes 0-7/" + strconv.Itoa(len(pngImage)); rec.Header().Get("Content-Range") != want {
+		t.Errorf("Content-Range is %q, want %q", rec.Header().Get("Content-Range"), want)
+	}
+	expect(t, s.do("GET", content, "", "Range", "bytes=1000-"), http.StatusRequestedRangeNotSatisfiable)
+	expect(t, s.do("GET", content, "", "If-None-Match", `"`+image.SHA256+`"`), http.StatusNotModified)
+	expect(t, s.do("GET", content, "", "If-None-Match", `"stale"`), http.StatusOK)
+}
+
+func TestAttachmentDelete(t *testing.T) {
+	s := newTestServer(t)
+	task := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "with files"}`))
+	attachments := "/api/v2/tasks/" + task.ID.String() + "/attachments"
+	created := decode[model.Attachment](t, s.upload(attachments, "screen.png", pngImage))
+	path := attachments + "/" + created.ID.String()
+
+	expect(t, s.do("DELETE", path, ""), http.StatusNoContent)
+	expectProblem(t, s.do("GET", path, ""), http.StatusNotFound)
+	expectProblem(t, s.do("GET", path+"/content", ""), http.StatusNotFound)
+	expectProblem(t, s.do("DELETE", path, ""), http.StatusNotFound)
+}
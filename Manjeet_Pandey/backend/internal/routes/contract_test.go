@@ -0,0 +1,442 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"task_manager/internal/openapi"
+	"testing"
+)
+
+// docsDir holds a swag output directory per API version.
+const docsDir = "../../cmd/docs"
+
+// step is one request of the contract scenario. Path, body and header
+// values may refer to values captured by earlier steps as {name}.
+type step struct {
+	method, path string
+	header       map[string]string
+	body         string
+	// status is the status the scenario expects, so a step that goes wrong
+	// isn't mistaken for a documented error response.
+	status int
+	// capture stores the string at a dotted path of the JSON response,
+	// given as name=path.
+	capture string
+}
+
+// unknownID is a well-formed ID that no task, project or webhook has.
+const unknownID = "00000000-0000-4000-8000-000000000000"
+
+const mergePatch = "application/merge-patch+json"
+
+// boundary separates the parts of the multipart bodies the scenario sends.
+const boundary = "contract-boundary"
+
+var multipartForm = map[string]string{"Content-Type": "multipart/form-data; boundary=" + boundary}
+
+// formFile returns a multipart body with contents in the field named field.
+func formFile(field, fileName, contents string) string {
+	return "--" + boundary + "\r\n" +
+		`Content-Disposition: form-data; name="` + field + `"; filename="` + fileName + `"` + "\r\n" +
+		"Content-Type: application/octet-stream\r\n\r\n" +
+		contents + "\r\n--" + boundary + "--\r\n"
+}
+
+// scenario exercises every documented operation at least once, with the
+// error responses that can be produced without faking failures.
+func scenario(hookURL string, maxBody, maxAttachment int64) []step {
+	oversized := `{"name": "` + strings.Repeat("x", int(maxBody)) + `"}`
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 24)
+	steps := []step{
+		{method: "GET", path: "/healthz", status: 200},
+		{method: "GET", path: "/readyz", status: 200},
+		{method: "GET", path: "/version", status: 200},
+		{method: "GET", path: "/problems/not-found", status: 200},
+		{method: "GET", path: "/problems/unknown", status: 404},
+
+		{method: "POST", path: "/api/v1/tasks", body: `{"name": "Contract", "description": "v1"}`, status: 201, capture: "task=data.id"},
+		{method: "POST", path: "/api/v1/tasks", body: `{"name": ""}`, status: 400},
+		{method: "POST", path: "/api/v1/tasks", body: oversized, status: 413},
+		{method: "POST", path: "/api/v1/tasks", header: map[string]string{"Idempotency-Key": "contract"}, body: `{"name": "Once"}`, status: 201},
+		{method: "POST", path: "/api/v1/tasks", header: map[string]string{"Idempotency-Key": "contract"}, body: `{"name": "Twice"}`, status: 422},
+		{method: "GET", path: "/api/v1/tasks", status: 200},
+		{method: "GET", path: "/api/v1/tasks/{task}", status: 200},
+		{method: "GET", path: "/api/v1/tasks/not-an-id", status: 400},
+		{method: "GET", path: "/api/v1/tasks/" + unknownID, status: 404},
+		{method: "PUT", path: "/api/v1/tasks/{task}", body: `{"name": "Contract", "status": "Completed"}`, status: 200},
+		{method: "PUT", path: "/api/v1/tasks/{task}", body: `{"name": ""}`, status: 400},
+		{method: "PUT", path: "/api/v1/tasks/" + unknownID, body: `{"name": "Missing"}`, status: 404},
+		{method: "PATCH", path: "/api/v1/tasks/{task}", header: map[string]string{"Content-Type": mergePatch}, body: `{"status": "Pending"}`, status: 200},
+		{method: "PATCH", path: "/api/v1/tasks/{task}", header: map[string]string{"Content-Type": mergePatch}, body: `{"status": "Unknown"}`, status: 400},
+		{method: "PATCH", path: "/api/v1/tasks/{task}", header: map[string]string{"Content-Type": "text/plain"}, body: `status`, status: 415},
+		{method: "PATCH", path: "/api/v1/tasks/" + unknownID, header: map[string]string{"Content-Type": mergePatch}, body: `{}`, status: 404},
+		{method: "POST", path: "/api/v1/tasks/bulk", body: `{"operations": [{"op": "create", "task": {"name": "Bulk"}}]}`, status: 200},
+		{method: "POST", path: "/api/v1/tasks/bulk", body: `{"operations": []}`, status: 400},
+		{method: "GET", path: "/api/v1/tasks/export", status: 200},
+		{method: "GET", path: "/api/v1/tasks/export?format=csv", status: 200},
+		{method: "GET", path: "/api/v1/tasks/export?format=ndjson", status: 200},
+		{method: "GET", path: "/api/v1/tasks/export?format=xml", status: 400},
+		{method: "POST", path: "/api/v1/tasks/import", body: `[{"name": "Imported"}]`, status: 200},
+		{method: "POST", path: "/api/v1/tasks/import", body: `[{"id": "{task}", "name": "Duplicate"}]`, status: 409},
+		{method: "POST", path: "/api/v1/tasks/import?format=xml", body: `[]`, status: 400},
+		{method: "GET", path: "/api/v1/events?status=Unknown", status: 400},
+		{method: "GET", path: "/api/v1/ws?status=Unknown", status: 400},
+
+		{method: "POST", path: "/api/v1/webhooks", body: `{"url": "` + hookURL + `", "events": ["*"]}`, status: 201, capture: "hook=data.id"},
+		{method: "POST", path: "/api/v1/webhooks", body: `{"url": "not a url"}`, status: 400},
+		{method: "GET", path: "/api/v1/webhooks", status: 200},
+		{method: "GET", path: "/api/v1/webhooks/{hook}", status: 200},
+		{method: "GET", path: "/api/v1/webhooks/not-an-id", status: 400},
+		{method: "GET", path: "/api/v1/webhooks/" + unknownID, status: 404},
+		{method: "POST", path: "/api/v1/webhooks/{hook}/test", status: 200},
+		{method: "POST", path: "/api/v1/webhooks/" + unknownID + "/test", status: 404},
+		{method: "GET", path: "/api/v1/webhooks/{hook}/deliveries", status: 200},
+		{method: "GET", path: "/api/v1/webhooks/" + unknownID + "/deliveries", status: 404},
+		{method: "DELETE", path: "/api/v1/webhooks/{hook}", status: 200},
+		{method: "DELETE", path: "/api/v1/webhooks/{hook}", status: 404},
+		{method: "DELETE", path: "/api/v1/webhooks/not-an-id", status: 400},
+
+		{method: "DELETE", path: "/api/v1/tasks/{task}", status: 200},
+		{method: "DELETE", path: "/api/v1/tasks/{task}", status: 404},
+		{method: "DELETE", path: "/api/v1/tasks/not-an-id", status: 400},
+
+		{method: "POST", path: "/api/v2/tasks", body: `{"name": "Contract", "description": "v2"}`, status: 201, capture: "task=id"},
+		{method: "POST", path: "/api/v2/tasks", body: `{"name": ""}`, status: 400},
+		{method: "POST", path: "/api/v2/tasks", body: oversized, status: 413},
+		{method: "GET", path: "/api/v2/tasks", status: 200},
+		{method: "GET", path: "/api/v2/tasks/{task}", status: 200},
+		{method: "GET", path: "/api/v2/tasks/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/tasks/" + unknownID, status: 404},
+		{method: "PUT", path: "/api/v2/tasks/{task}", body: `{"name": "Contract", "status": "Completed"}`, status: 200},
+		{method: "PUT", path: "/api/v2/tasks/{task}", body: `{"name": ""}`, status: 400},
+		{method: "PUT", path: "/api/v2/tasks/" + unknownID, body: `{"name": "Missing"}`, status: 404},
+		{method: "PATCH", path: "/api/v2/tasks/{task}", header: map[string]string{"Content-Type": mergePatch}, body: `{"status": "Pending"}`, status: 200},
+		{method: "PATCH", path: "/api/v2/tasks/{task}", header: map[string]string{"Content-Type": "text/plain"}, body: `status`, status: 415},
+		{method: "PATCH", path: "/api/v2/tasks/" + unknownID, header: map[string]string{"Content-Type": mergePatch}, body: `{}`, status: 404},
+		{method: "POST", path: "/api/v2/tasks/{task}/comments", body: `{"author": "contract", "body": "Looks **good**"}`, status: 201, capture: "comment=id"},
+		{method: "POST", path: "/api/v2/tasks/{task}/comments", body: `{"author": "contract", "body": " "}`, status: 400},
+		{method: "POST", path: "/api/v2/tasks/" + unknownID + "/comments", body: `{"author": "contract", "body": "Lost"}`, status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/comments", status: 200},
+		{method: "GET", path: "/api/v2/tasks/not-an-id/comments", status: 400},
+		{method: "GET", path: "/api/v2/tasks/" + unknownID + "/comments", status: 404},
+		{method: "PUT", path: "/api/v2/tasks/{task}/comments/{comment}", body: `{"body": "Looks good"}`, status: 200},
+		{method: "PUT", path: "/api/v2/tasks/{task}/comments/{comment}", body: `{}`, status: 400},
+		{method: "PUT", path: "/api/v2/tasks/{task}/comments/" + unknownID, body: `{"body": "Lost"}`, status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/activity", status: 200},
+		{method: "GET", path: "/api/v2/tasks/" + unknownID + "/activity", status: 404},
+		{method: "GET", path: "/api/v2/tasks/not-an-id/activity", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/comments/{comment}", status: 204},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/comments/{comment}", status: 404},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/comments/not-an-id", status: 400},
+		{method: "POST", path: "/api/v2/labels", body: `{"name": "backend", "color": "#1d76db"}`, status: 201, capture: "label=id"},
+		{method: "POST", path: "/api/v2/labels", body: `{"name": "backend"}`, status: 409},
+		{method: "POST", path: "/api/v2/labels", body: `{"name": "ui", "color": "blue"}`, status: 400},
+		{method: "GET", path: "/api/v2/labels", status: 200},
+		{method: "PUT", path: "/api/v2/tasks/{task}/labels/{label}", status: 200},
+		{method: "PUT", path: "/api/v2/tasks/{task}/labels/" + unknownID, status: 404},
+		{method: "PUT", path: "/api/v2/tasks/{task}/labels/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/tasks?label=backend", status: 200},
+		{method: "GET", path: "/api/v1/tasks?label=backend,ui&match=any", status: 200},
+		{method: "GET", path: "/api/v2/tasks?label=backend&match=some", status: 400},
+		{method: "GET", path: "/api/v1/tasks?label=backend&match=some", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/labels/{label}", status: 204},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/labels/{label}", status: 404},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/labels/not-an-id", status: 400},
+		{method: "PUT", path: "/api/v2/tasks/{task}/labels/{label}", status: 200},
+		{method: "DELETE", path: "/api/v2/labels/{label}", status: 204},
+		{method: "DELETE", path: "/api/v2/labels/{label}", status: 404},
+		{method: "DELETE", path: "/api/v2/labels/not-an-id", status: 400},
+		{method: "POST", path: "/api/v2/tasks/{task}/attachments", header: multipartForm, body: formFile("file", "screen.png", png), status: 201, capture: "attachment=id"},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/{attachment}", status: 200, capture: "sha=sha256"},
+		{method: "POST", path: "/api/v2/tasks/{task}/attachments", header: multipartForm, body: formFile("upload", "screen.png", png), status: 400},
+		{method: "POST", path: "/api/v2/tasks/{task}/attachments", header: multipartForm, body: formFile("file", "tool.exe", "MZ\x90\x00"), status: 415},
+		{method: "POST", path: "/api/v2/tasks/{task}/attachments", header: multipartForm, body: formFile("file", "big.txt", strings.Repeat("x", int(maxAttachment)+1)), status: 413},
+		{method: "POST", path: "/api/v2/tasks/{task}/attachments", header: map[string]string{"Content-Type": "text/plain"}, body: "notes", status: 415},
+		{method: "POST", path: "/api/v2/tasks/" + unknownID + "/attachments", header: multipartForm, body: formFile("file", "screen.png", png), status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments", status: 200},
+		{method: "GET", path: "/api/v2/tasks/not-an-id/attachments", status: 400},
+		{method: "GET", path: "/api/v2/tasks/" + unknownID + "/attachments", status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/" + unknownID, status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/{attachment}/content", status: 200},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/{attachment}/content", header: map[string]string{"Range": "bytes=0-7"}, status: 206},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/{attachment}/content", header: map[string]string{"Range": "bytes=1000-"}, status: 416},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/{attachment}/content", header: map[string]string{"If-None-Match": `"{sha}"`}, status: 304},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/" + unknownID + "/content", status: 404},
+		{method: "GET", path: "/api/v2/tasks/{task}/attachments/not-an-id/content", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/attachments/{attachment}", status: 204},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/attachments/{attachment}", status: 404},
+		{method: "DELETE", path: "/api/v2/tasks/{task}/attachments/not-an-id", status: 400},
+		{method: "POST", path: "/api/v2/projects", body: `{"name": "Contract", "max_concurrency": 2}`, status: 201, capture: "project=id"},
+		{method: "POST", path: "/api/v2/projects", body: `{"name": "Contract"}`, status: 409},
+		{method: "POST", path: "/api/v2/projects", body: `{"name": " "}`, status: 400},
+		{method: "GET", path: "/api/v2/projects", status: 200},
+		{method: "GET", path: "/api/v2/projects/{project}", status: 200},
+		{method: "GET", path: "/api/v2/projects/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/projects/" + unknownID, status: 404},
+		{method: "PUT", path: "/api/v2/projects/{project}", body: `{"name": "Contract", "description": "Scoped tasks"}`, status: 200},
+		{method: "PUT", path: "/api/v2/projects/{project}", body: `{"name": "Default"}`, status: 409},
+		{method: "PUT", path: "/api/v2/projects/{project}", body: `{"name": "Contract", "max_concurrency": -1}`, status: 400},
+		{method: "PUT", path: "/api/v2/projects/" + unknownID, body: `{"name": "Missing"}`, status: 404},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks", body: `{"name": "Scoped"}`, status: 201, capture: "scoped=id"},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks", body: `{"name": ""}`, status: 400},
+		{method: "POST", path: "/api/v2/projects/" + unknownID + "/tasks", body: `{"name": "Lost"}`, status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks", status: 200},
+		{method: "GET", path: "/api/v2/projects/not-an-id/tasks", status: 400},
+		{method: "GET", path: "/api/v2/projects/" + unknownID + "/tasks", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}", status: 200},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{task}", status: 404},
+		{method: "GET", path: "/api/v2/tasks/{scoped}", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/not-an-id", status: 400},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}", body: `{"name": "Scoped", "status": "Completed"}`, status: 200},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}", body: `{"name": ""}`, status: 400},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{task}", body: `{"name": "Moved"}`, status: 404},
+		{method: "PATCH", path: "/api/v2/projects/{project}/tasks/{scoped}", header: map[string]string{"Content-Type": mergePatch}, body: `{"status": "Pending"}`, status: 200},
+		{method: "PATCH", path: "/api/v2/projects/{project}/tasks/{scoped}", header: map[string]string{"Content-Type": "text/plain"}, body: `status`, status: 415},
+		{method: "PATCH", path: "/api/v2/projects/{project}/tasks/{task}", header: map[string]string{"Content-Type": mergePatch}, body: `{}`, status: 404},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{scoped}/comments", body: `{"author": "contract", "body": "Scoped"}`, status: 201, capture: "note=id"},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{scoped}/comments", body: `{"author": "contract", "body": " "}`, status: 400},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{task}/comments", body: `{"author": "contract", "body": "Moved"}`, status: 404},
+		{method: "POST", path: "/api/v2/tasks/{scoped}/comments", body: `{"author": "contract", "body": "Moved"}`, status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/comments", status: 200},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{task}/comments", status: 404},
+		{method: "GET", path: "/api/v2/projects/not-an-id/tasks/{scoped}/comments", status: 400},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}/comments/{note}", body: `{"body": "Scoped task"}`, status: 200},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}/comments/{note}", body: `{}`, status: 400},
+		{method: "PUT", path: "/api/v2/tasks/{scoped}/comments/{note}", body: `{"body": "Moved"}`, status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/activity", status: 200},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{task}/activity", status: 404},
+		{method: "GET", path: "/api/v2/tasks/{scoped}/activity", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/not-an-id/activity", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{scoped}/comments/{note}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/comments/{note}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/comments/{note}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/comments/not-an-id", status: 400},
+		{method: "POST", path: "/api/v2/labels", body: `{"name": "scoped", "color": "#1d76db"}`, status: 201, capture: "tag=id"},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}/labels/{tag}", status: 200},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{task}/labels/{tag}", status: 404},
+		{method: "PUT", path: "/api/v2/tasks/{scoped}/labels/{tag}", status: 404},
+		{method: "PUT", path: "/api/v2/projects/{project}/tasks/{scoped}/labels/not-an-id", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{scoped}/labels/{tag}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/labels/{tag}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/labels/{tag}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/labels/not-an-id", status: 400},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments", header: multipartForm, body: formFile("file", "screen.png", png), status: 201, capture: "scan=id"},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{task}/attachments", header: multipartForm, body: formFile("file", "screen.png", png), status: 404},
+		{method: "POST", path: "/api/v2/tasks/{scoped}/attachments", header: multipartForm, body: formFile("file", "screen.png", png), status: 404},
+		{method: "POST", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments", header: multipartForm, body: formFile("upload", "screen.png", png), status: 400},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments", status: 200},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{task}/attachments", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/not-an-id/attachments", status: 400},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/{scan}", status: 200},
+		{method: "GET", path: "/api/v2/tasks/{scoped}/attachments/{scan}", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/{scan}/content", status: 200},
+		{method: "GET", path: "/api/v2/tasks/{scoped}/attachments/{scan}/content", status: 404},
+		{method: "GET", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/not-an-id/content", status: 400},
+		{method: "DELETE", path: "/api/v2/tasks/{scoped}/attachments/{scan}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/{scan}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/{scan}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}/attachments/not-an-id", status: 400},
+		{method: "DELETE", path: "/api/v2/labels/{tag}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}", status: 409},
+		{method: "DELETE", path: "/api/v2/projects/00000000-0000-0000-0000-000000000001", status: 409},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{task}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/not-an-id", status: 400},
+		{method: "DELETE", path: "/api/v2/projects/{project}/tasks/{scoped}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}", status: 204},
+		{method: "DELETE", path: "/api/v2/projects/{project}", status: 404},
+		{method: "DELETE", path: "/api/v2/projects/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/events?status=Unknown", status: 400},
+		{method: "GET", path: "/api/v2/ws?status=Unknown", status: 400},
+		{method: "POST", path: "/api/v2/tasks/bulk", body: `{"operations": [{"op": "create", "task": {"name": "Bulk"}}]}`, status: 200},
+		{method: "POST", path: "/api/v2/tasks/bulk", body: `{"operations": []}`, status: 400},
+		{method: "GET", path: "/api/v2/tasks/export", status: 200},
+		{method: "GET", path: "/api/v2/tasks/export?format=csv", status: 200},
+		{method: "GET", path: "/api/v2/tasks/export?format=xml", status: 400},
+		{method: "POST", path: "/api/v2/tasks/import", body: `[{"name": "Imported"}]`, status: 200},
+		{method: "POST", path: "/api/v2/tasks/import", body: `[{"id": "{task}", "name": "Duplicate"}]`, status: 409},
+		{method: "POST", path: "/api/v2/tasks/import?format=xml", body: `[]`, status: 400},
+
+		{method: "POST", path: "/api/v2/webhooks", body: `{"url": "` + hookURL + `", "events": ["*"]}`, status: 201, capture: "hook=id"},
+		{method: "POST", path: "/api/v2/webhooks", body: `{"url": "not a url"}`, status: 400},
+		{method: "GET", path: "/api/v2/webhooks", status: 200},
+		{method: "GET", path: "/api/v2/webhooks/{hook}", status: 200},
+		{method: "GET", path: "/api/v2/webhooks/not-an-id", status: 400},
+		{method: "GET", path: "/api/v2/webhooks/" + unknownID, status: 404},
+		{method: "POST", path: "/api/v2/webhooks/{hook}/test", status: 200},
+		{method: "POST", path: "/api/v2/webhooks/" + unknownID + "/test", status: 404},
+		{method: "GET", path: "/api/v2/webhooks/{hook}/deliveries", status: 200},
+		{method: "GET", path: "/api/v2/webhooks/" + unknownID + "/deliveries", status: 404},
+		{method: "DELETE", path: "/api/v2/webhooks/{hook}", status: 204},
+		{method: "DELETE", path: "/api/v2/webhooks/{hook}", status: 404},
+		{method: "DELETE", path: "/api/v2/webhooks/not-an-id", status: 400},
+
+		{method: "DELETE", path: "/api/v2/tasks/{task}", status: 204},
+		{method: "DELETE", path: "/api/v2/tasks/{task}", status: 404},
+		{method: "DELETE", path: "/api/v2/tasks/not-an-id", status: 400},
+
+		// Last, since the workers it starts keep changing tasks.
+		{method: "POST", path: "/api/v1/tasks/process", status: 202},
+		{method: "POST", path: "/api/v2/tasks/process", status: 202},
+	}
+	return steps
+}
+
+// loadSpecs reads the OpenAPI document of every version, failing the test
+// when one is stale against the Swagger document it is generated from.
+func loadSpecs(t *testing.T) []*openapi.Spec {
+	t.Helper()
+	var specs []*openapi.Spec
+	for _, version := range []string{"v1", "v2"} {
+		swagger, err := os.ReadFile(filepath.Join(docsDir, version, version+"_swagger.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := openapi.Convert(swagger)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		path := filepath.Join(docsDir, version, "openapi.json")
+		have, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Errorf("%s is stale; run go run ./cmd/openapi generate", path)
+		}
+		spec, err := openapi.Load(version, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// TestContract compares the routes with the documented operations, then
+// sends a scripted request to every operation and validates each
+// response's status, media type and body against its document.
+func TestContract(t *testing.T) {
+	specs := loadSpecs(t)
+	s := newTestServer(t)
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(hooks.Close)
+
+	// The unversioned routes alias version 1, and GraphQL has its own
+	// schema.
+	ignore := func(method, path string) bool {
+		if path == "/graphql" {
+			return true
+		}
+		if strings.HasPrefix(path, "/api/") {
+			return false
+		}
+		_, aliased := specs[0].Route(method, "/api/v1"+path)
+		return aliased
+	}
+	for _, err := range openapi.CheckRoutes(s.engine.Routes(), specs, ignore) {
+		t.Error(err)
+	}
+
+	vars := map[string]string{}
+	exercised := map[string]bool{}
+	for _, st := range scenario(hooks.URL, s.cfg.MaxBodyBytes, s.cfg.MaxAttachmentBytes) {
+		path, body := expand(st.path, vars), expand(st.body, vars)
+		name := st.method + " " + path
+		if len(name) > 80 {
+			name = name[:80] + "..."
+		}
+		req := httptest.NewRequest(st.method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for key, value := range st.header {
+			req.Header.Set(key, expand(value, vars))
+		}
+		rec := httptest.NewRecorder()
+		s.engine.ServeHTTP(rec, req)
+
+		spec, op, ok := match(specs, st.method, req.URL.Path)
+		if !ok {
+			t.Errorf("%s: no documented operation", name)
+			continue
+		}
+		exercised[spec.String()+" "+op.String()] = true
+		if rec.Code != st.status {
+			t.Errorf("%s: got %d, the scenario expects %d: %s", name, rec.Code, st.status, snippet(rec.Body.Bytes()))
+			continue
+		}
+		if err := spec.CheckResponse(op, rec.Code, rec.Header(), rec.Body.Bytes()); err != nil {
+			t.Errorf("%s (%s): %v", name, op, err)
+			continue
+		}
+		if st.capture != "" {
+			key, field, _ := strings.Cut(st.capture, "=")
+			value, err := lookup(rec.Body.Bytes(), field)
+			if err != nil {
+				t.Fatalf("%s: capture %s: %v", name, field, err)
+			}
+			vars[key] = value
+		}
+	}
+	for _, spec := range specs {
+		for _, op := range spec.Operations() {
+			if !exercised[spec.String()+" "+op.String()] {
+				t.Errorf("%s: %s is not exercised by the contract scenario", spec, op)
+			}
+		}
+	}
+}
+
+// match finds the operation for a request in the first spec documenting
+// it.
+func match(specs []*openapi.Spec, method, path string) (*openapi.Spec, openapi.Operation, bool) {
+	for _, spec := range specs {
+		if op, ok := spec.Match(method, path); ok {
+			return spec, op, true
+		}
+	}
+	return nil, openapi.Operation{}, false
+}
+
+func expand(s string, vars map[string]string) string {
+	for key, value := range vars {
+		s = strings.ReplaceAll(s, "{"+key+"}", value)
+	}
+	return s
+}
+
+// lookup returns the string at a dotted path of a JSON document.
+func lookup(body []byte, path string) (string, error) {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", err
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%s is not an object", key)
+		}
+		value = object[key]
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("not a string")
+	}
+	return s, nil
+}
+
+func snippet(body []byte) string {
+	const max = 200
+	if len(body) > max {
+		return string(body[:max]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"strings"
+	"task_manager/internal/config"
+	"task_manager/internal/events"
+	"task_manager/internal/handler"
+	"task_manager/internal/metrics"
+	"task_manager/internal/middleware"
+	"task_manager/internal/storage"
+	"task_manager/internal/tracing"
+	"task_manager/internal/webhook"
+	"task_manager/util"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"gorm.io/gorm"
+)
+
+// NewEngine returns a router with the middleware every request goes
+// through and the routes registered by SetupRoutes.
+func NewEngine(cfg config.Config, db *gorm.DB, store storage.Storage, bus *events.Bus, dispatcher *webhook.Dispatcher, workers *util.Worker, shutdown <-chan struct{}) *gin.Engine {
+	r := gin.New()
+	// Unless proxies are configured, X-Forwarded-For is ignored so clients
+	// can't pick the address their rate limits are kept under. The list was
+	// checked by config.Load.
+	r.SetTrustedProxies(cfg.TrustedProxies)
+	r.Use(
+		gin.CustomRecovery(handler.RecoveryHandler),
+		otelgin.Middleware(tracing.ServiceName, otelgin.WithGinFilter(traced)),
+		middleware.RequestIDMiddleware(),
+		middleware.LoggerMiddleware(),
+		middleware.CORSMiddleware(),
+		metrics.Middleware(),
+	)
+	SetupRoutes(r, cfg, db, store, bus, dispatcher, workers, shutdown)
+	return r
+}
+
+// traced leaves scrapes, probes and the API docs out of the traces.
+func traced(c *gin.Context) bool {
+	switch path := c.Request.URL.Path; path {
+	case "/metrics", "/healthz", "/readyz":
+		return false
+	default:
+		return !strings.HasPrefix(path, "/swagger/") && !strings.HasPrefix(path, "/openapi/")
+	}
+}
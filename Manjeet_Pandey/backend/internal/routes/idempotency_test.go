@@ -0,0 +1,139 @@
+package routes_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager/internal/config"
+	"task_manager/internal/handler"
+	"task_manager/internal/middleware"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"testing"
+	"time"
+
+	v2 "task_manager/internal/handler/v2"
+)
+
+const idempotencyKey = middleware.IdempotencyKeyHeader
+
+func replayed(rec *httptest.ResponseRecorder) bool {
+	return rec.Header().Get(middleware.IdempotentReplayedHeader) == "true"
+}
+
+func TestIdempotentRetry(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"name": "once"}`
+
+	first := s.do("POST", "/api/v2/tasks", body, idempotencyKey, "create-1")
+	expect(t, first, http.StatusCreated)
+	if replayed(first) {
+		t.Error("first response is marked as replayed")
+	}
+	retry := s.do("POST", "/api/v2/tasks", body, idempotencyKey, "create-1")
+	expect(t, retry, http.StatusCreated)
+	if !replayed(retry) {
+		t.Error("retry isn't marked as replayed")
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("retry got %s, want the first response %s", retry.Body, first.Body)
+	}
+	if location := retry.Header().Get("Location"); location == "" || location != first.Header().Get("Location") {
+		t.Errorf("retry Location is %q, want %q", location, first.Header().Get("Location"))
+	}
+	if list := decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks", "")); len(list.Items) != 1 {
+		t.Errorf("%d tasks after a retry, want 1", len(list.Items))
+	}
+
+	// Another key is another request.
+	expect(t, s.do("POST", "/api/v2/tasks", body, idempotencyKey, "create-2"), http.StatusCreated)
+	// Client errors are replayed too; server errors aren't stored.
+	invalid := s.do("POST", "/api/v2/tasks", `{"name": ""}`, idempotencyKey, "invalid")
+	expectProblem(t, invalid, http.StatusBadRequest)
+	if retry := s.do("POST", "/api/v2/tasks", `{"name": ""}`, idempotencyKey, "invalid"); !replayed(retry) || retry.Code != http.StatusBadRequest {
+		t.Errorf("retry of a rejected request got %d, replayed %v", retry.Code, replayed(retry))
+	}
+}
+
+func TestIdempotencyKeyReuse(t *testing.T) {
+	s := newTestServer(t)
+	expect(t, s.do("POST", "/api/v2/tasks", `{"name": "first"}`, idempotencyKey, "k"), http.StatusCreated)
+
+	// The key identifies the method, path and body it was first used with.
+	expectProblem(t, s.do("POST", "/api/v2/tasks", `{"name": "second"}`, idempotencyKey, "k"), http.StatusUnprocessableEntity)
+	expectProblem(t, s.do("POST", "/api/v1/tasks", `{"name": "first"}`, idempotencyKey, "k"), http.StatusUnprocessableEntity)
+	// And the Accept header, which picks the version 1 envelope.
+	expect(t, s.do("POST", "/api/v1/tasks", `{"name": "v1"}`, idempotencyKey, "v1"), http.StatusCreated)
+	expect(t, s.do("POST", "/api/v1/tasks", `{"name": "v1"}`, idempotencyKey, "v1", "Accept", response.LegacyMediaType), http.StatusUnprocessableEntity)
+	expectProblem(t, s.do("POST", "/api/v2/tasks", `{"name": "x"}`, idempotencyKey, strings.Repeat("k", 256)), http.StatusBadRequest)
+
+	// Keys belong to the client that sent them.
+	req := httptest.NewRequest("POST", "/api/v2/tasks", strings.NewReader(`{"name": "second"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKey, "k")
+	req.RemoteAddr = "198.51.100.7:1234"
+	rec := httptest.NewRecorder()
+	s.engine.ServeHTTP(rec, req)
+	expect(t, rec, http.StatusCreated)
+	if replayed(rec) {
+		t.Error("another client's request was answered from the first client's key")
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"name": "slow"}`
+	// Claim the key as a request still running would.
+	h := sha256.New()
+	h.Write([]byte("POST /api/v2/tasks\nAccept: \nContent-Type: application/json\n\n" + body))
+	store := service.NewIdempotencyService(s.db, s.cfg.IdempotencyTTL)
+	if _, err := store.Begin(context.Background(), "192.0.2.1", "slow", hex.EncodeToString(h.Sum(nil))); err != nil {
+		t.Fatal(err)
+	}
+	expectProblem(t, s.do("POST", "/api/v2/tasks", body, idempotencyKey, "slow"), http.StatusConflict)
+
+	// Once released, a retry runs the request.
+	if err := store.Release(context.Background(), "192.0.2.1", "slow"); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, s.do("POST", "/api/v2/tasks", body, idempotencyKey, "slow"), http.StatusCreated)
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.IdempotencyTTL = 50 * time.Millisecond })
+	expect(t, s.do("POST", "/api/v2/tasks", `{"name": "a"}`, idempotencyKey, "k"), http.StatusCreated)
+	time.Sleep(100 * time.Millisecond)
+	rec := s.do("POST", "/api/v2/tasks", `{"name": "a"}`, idempotencyKey, "k")
+	expect(t, rec, http.StatusCreated)
+	if replayed(rec) {
+		t.Error("expired key was replayed")
+	}
+}
+
+func TestIdempotentBulkAndProcess(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"operations": [{"op": "create", "task": {"name": "a"}}, {"op": "create", "task": {"name": "b"}}]}`
+	first := s.do("POST", "/api/v1/tasks/bulk", body, idempotencyKey, "bulk")
+	expect(t, first, http.StatusOK)
+	retry := s.do("POST", "/api/v1/tasks/bulk", body, idempotencyKey, "bulk")
+	if !replayed(retry) || retry.Body.String() != first.Body.String() {
+		t.Errorf("bulk retry got %s, replayed %v", retry.Body, replayed(retry))
+	}
+	if bulk := decode[envelope[handler.BulkResponse]](t, retry).Data; bulk.Succeeded != 2 {
+		t.Errorf("replayed bulk response is %+v", bulk)
+	}
+	if list := decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks", "")); len(list.Items) != 2 {
+		t.Errorf("%d tasks after a bulk retry, want 2", len(list.Items))
+	}
+
+	first = s.do("POST", "/api/v1/tasks/process", "", idempotencyKey, "process")
+	expect(t, first, http.StatusAccepted)
+	retry = s.do("POST", "/api/v1/tasks/process", "", idempotencyKey, "process")
+	if !replayed(retry) || len(decode[envelope[[]model.Task]](t, retry).Data) != 2 {
+		t.Errorf("process retry got %s, replayed %v", retry.Body, replayed(retry))
+	}
+}
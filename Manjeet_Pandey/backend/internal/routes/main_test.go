@@ -0,0 +1,151 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"task_manager/internal/config"
+	"task_manager/internal/database"
+	"task_manager/internal/events"
+	"task_manager/internal/logging"
+	"task_manager/internal/response"
+	"task_manager/internal/routes"
+	"task_manager/internal/service"
+	"task_manager/internal/storage"
+	"task_manager/internal/webhook"
+	"task_manager/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// Request logs would drown the test output.
+	logging.Setup("disabled", "console")
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testServer is the API over a scratch database, served in-process.
+type testServer struct {
+	t      *testing.T
+	cfg    config.Config
+	db     *gorm.DB
+	bus    *events.Bus
+	engine *gin.Engine
+	// workers process the tasks claimed through the API.
+	workers *util.Worker
+}
+
+// newTestServer builds the API against a database in a temporary
+// directory. Rate limits are off unless configure sets them.
+func newTestServer(t *testing.T, configure ...func(*config.Config)) *testServer {
+	t.Helper()
+	dir := t.TempDir()
+	// Tests run requests from several goroutines; wait for the lock rather
+	// than failing with "database is locked".
+	db, err := database.Open(filepath.Join(dir, "tasks.db") + database.DSNOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.RateLimitRead, cfg.RateLimitWrite, cfg.RateLimitBulk = config.RateLimit{}, config.RateLimit{}, config.RateLimit{}
+	cfg.MaxAttachmentBytes = 64 << 10
+	for _, f := range configure {
+		f(&cfg)
+	}
+	store, err := storage.NewLocal(filepath.Join(dir, "attachments"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := events.NewBus()
+	dispatcher := webhook.NewDispatcher(service.NewWebhookService(db), bus, webhook.Options{MaxAttempts: 1})
+	dispatcher.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		dispatcher.Shutdown(ctx)
+	})
+	workers := util.NewWorker(2, service.NewTaskService(db, bus))
+	workers.StartWorker()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		workers.Shutdown(ctx)
+	})
+	shutdown := make(chan struct{})
+	t.Cleanup(func() { close(shutdown) })
+	return &testServer{
+		t:       t,
+		cfg:     cfg,
+		db:      db,
+		bus:     bus,
+		engine:  routes.NewEngine(cfg, db, store, bus, dispatcher, workers, shutdown),
+		workers: workers,
+	}
+}
+
+// do sends a request, as JSON when there is a body.
+func (s *testServer) do(method, path, body string, header ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.engine.ServeHTTP(rec, req)
+	return rec
+}
+
+// expect fails the test unless rec has the status.
+func expect(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("got %d, want %d: %s", rec.Code, status, rec.Body.String())
+	}
+}
+
+// decode reads a JSON body into a T.
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body.String(), err)
+	}
+	return v
+}
+
+// envelope is a version 1 response with its data decoded into a T.
+type envelope[T any] struct {
+	Status int `json:"status"`
+	Data   T   `json:"data"`
+}
+
+// expectProblem checks that rec is a problem details response with the
+// status.
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) response.Problem {
+	t.Helper()
+	expect(t, rec, status)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, response.ProblemContentType) {
+		t.Fatalf("Content-Type is %q, want %s", ct, response.ProblemContentType)
+	}
+	problem := decode[response.Problem](t, rec)
+	if problem.Status != status {
+		t.Fatalf("problem status is %d, want %d", problem.Status, status)
+	}
+	return problem
+}
@@ -1,19 +1,198 @@
 package routes
 
 import (
+	"task_manager/internal/config"
+	"task_manager/internal/events"
+	"task_manager/internal/graphql"
 	"task_manager/internal/handler"
+	v2 "task_manager/internal/handler/v2"
+	"task_manager/internal/middleware"
 	"task_manager/internal/service"
+	"task_manager/internal/storage"
+	"task_manager/internal/webhook"
+	"task_manager/util"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
-	taskService := service.NewTaskService(db)
-	taskHandler := handler.NewTaskHandler(taskService)
-	r.POST("/tasks", taskHandler.CreateTaskHandler())
-	r.GET("/tasks", taskHandler.GetTasksHandler())
-	r.GET("/tasks/:id", taskHandler.GetTaskHandler())
-	r.PUT("/tasks/:id", taskHandler.UpdateTaskHandler())
-	r.DELETE("/tasks/:id", taskHandler.DeleteTaskHandler())
+// v1Deprecated is when version 2 of the API was introduced and version 1,
+// with the unversioned routes, was deprecated.
+var v1Deprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// limits holds the middleware shared by every version, so a client's rate
+// limits are counted across versions rather than once per version.
+type limits struct {
+	read, write, bulk gin.HandlerFunc
+	body, importBody  gin.HandlerFunc
+	attachmentBody    gin.HandlerFunc
+	idempotent        gin.HandlerFunc
+}
+
+// SetupRoutes registers the API. Event streams are closed when shutdown is
+// closed. Attachment contents are kept in store, and tasks are processed by
+// workers, which the caller starts and shuts down.
+//
+// Version 1 is served under /api/v1 and, for clients written before
+// versioning, at the root; both are deprecated in favour of version 2 under
+// /api/v2. The versions share the services, and the probes aren't
+// versioned.
+//
+// Apart from the probes, routes fall into three groups with their own rate
+// limits: reads, writes, and the bulk endpoints that touch many tasks at
+// once. Request bodies are capped at cfg.MaxBodyBytes, or
+// cfg.MaxImportBytes for imports, or cfg.MaxAttachmentBytes and room for
+// the multipart framing for uploads. Writes, and bulk endpoints other than
+// import, honour the Idempotency-Key header; uploads don't, since they are
+// streamed rather than buffered. GraphQL queries count as writes
+// since they may carry mutations.
+func SetupRoutes(r *gin.Engine, cfg config.Config, db *gorm.DB, store storage.Storage, bus *events.Bus, dispatcher *webhook.Dispatcher, workers *util.Worker, shutdown <-chan struct{}) {
+	healthHandler := handler.NewHealthHandler(db)
+	r.GET("/healthz", healthHandler.LivenessHandler())
+	r.GET("/readyz", healthHandler.ReadinessHandler())
+	r.GET("/version", healthHandler.VersionHandler())
+	r.GET("/problems/:type", handler.ProblemTypeHandler())
+
+	l := limits{
+		read:       middleware.RateLimitMiddleware(cfg.RateLimitRead),
+		write:      middleware.RateLimitMiddleware(cfg.RateLimitWrite),
+		bulk:       middleware.RateLimitMiddleware(cfg.RateLimitBulk),
+		body:       middleware.BodyLimitMiddleware(cfg.MaxBodyBytes),
+		importBody: middleware.BodyLimitMiddleware(cfg.MaxImportBytes),
+		// Headers and boundaries of the multipart body come on top of the
+		// file.
+		attachmentBody: middleware.BodyLimitMiddleware(cfg.MaxAttachmentBytes + 1<<20),
+		idempotent:     middleware.IdempotencyMiddleware(service.NewIdempotencyService(db, cfg.IdempotencyTTL)),
+	}
+	taskService := service.NewTaskService(db, bus)
+	eventsHandler := handler.NewEventsHandler(bus, shutdown)
+
+	deprecated := middleware.DeprecationMiddleware(middleware.Deprecation{
+		Since:     v1Deprecated,
+		Sunset:    cfg.APIV1Sunset,
+		Successor: "/api/v2",
+	})
+	webhookService := service.NewWebhookService(db)
+	taskHandler := handler.NewTaskHandler(taskService, workers)
+	setupV1(r.Group("/api/v1", deprecated), l, taskHandler, eventsHandler, webhookService, dispatcher)
+	setupV1(r.Group("", deprecated), l, taskHandler, eventsHandler, webhookService, dispatcher)
+	commentHandler := v2.NewCommentHandler(service.NewCommentService(db), service.NewActivityService(db))
+	attachmentHandler := v2.NewAttachmentHandler(service.NewAttachmentService(db, store, service.AttachmentLimits{
+		MaxBytes: cfg.MaxAttachmentBytes,
+		Types:    cfg.AttachmentTypes,
+	}))
+	labelHandler := v2.NewLabelHandler(service.NewLabelService(db, bus))
+	projectHandler := v2.NewProjectHandler(service.NewProjectService(db))
+	webhookHandler := v2.NewWebhookHandler(webhookService, dispatcher)
+	setupV2(r.Group("/api/v2"), l, v2.NewTaskHandler(taskService, workers), eventsHandler, commentHandler, attachmentHandler, labelHandler, projectHandler, webhookHandler)
+
+	// GraphQL isn't versioned; the schema evolves by adding fields. GraphiQL
+	// is only served in development mode.
+	graphqlHandler := graphql.NewHandler(graphql.NewSchema(taskService, service.NewActivityService(db)), shutdown)
+	r.POST("/graphql", l.write, l.body, graphqlHandler.QueryHandler())
+	r.GET("/graphql", l.read, graphqlHandler.PlaygroundHandler(gin.IsDebugging()))
+}
+
+func setupV1(api *gin.RouterGroup, l limits, taskHandler handler.TaskHandler, eventsHandler handler.EventsHandler, webhookService service.WebhookService, dispatcher *webhook.Dispatcher) {
+	read := api.Group("", l.read)
+	write := api.Group("", l.write, l.body, l.idempotent)
+	bulk := api.Group("", l.bulk)
+
+	write.POST("/tasks", taskHandler.CreateTaskHandler())
+	read.GET("/tasks", taskHandler.GetTasksHandler())
+	bulk.POST("/tasks/bulk", l.body, l.idempotent, taskHandler.BulkTaskHandler())
+	bulk.GET("/tasks/export", taskHandler.ExportTasksHandler())
+	bulk.POST("/tasks/import", l.importBody, taskHandler.ImportTasksHandler())
+	bulk.POST("/tasks/process", l.body, l.idempotent, taskHandler.ProcessTasksHandler())
+	read.GET("/tasks/:id", taskHandler.GetTaskHandler())
+	write.PUT("/tasks/:id", taskHandler.UpdateTaskHandler())
+	write.PATCH("/tasks/:id", taskHandler.PatchTaskHandler())
+	write.DELETE("/tasks/:id", taskHandler.DeleteTaskHandler())
+
+	read.GET("/events", eventsHandler.StreamHandler())
+	read.GET("/ws", eventsHandler.WebSocketHandler())
+
+	webhookHandler := handler.NewWebhookHandler(webhookService, dispatcher)
+	write.POST("/webhooks", webhookHandler.CreateWebhookHandler())
+	read.GET("/webhooks", webhookHandler.ListWebhooksHandler())
+	read.GET("/webhooks/:id", webhookHandler.GetWebhookHandler())
+	write.DELETE("/webhooks/:id", webhookHandler.DeleteWebhookHandler())
+	read.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveriesHandler())
+	write.POST("/webhooks/:id/test", webhookHandler.TestWebhookHandler())
+}
+
+// setupV2 registers version 2, which serves everything version 1 does;
+// comments, activity feeds, attachments, labels and projects are only
+// served here. /tasks holds the tasks of the default project, with their
+// comments, activity, attachments and labels, and /projects/:projectId/tasks
+// those of any project.
+func setupV2(api *gin.RouterGroup, l limits, taskHandler v2.TaskHandler, eventsHandler handler.EventsHandler, commentHandler v2.CommentHandler, attachmentHandler v2.AttachmentHandler, labelHandler v2.LabelHandler, projectHandler v2.ProjectHandler, webhookHandler v2.WebhookHandler) {
+	read := api.Group("", l.read)
+	write := api.Group("", l.write, l.body, l.idempotent)
+	bulk := api.Group("", l.bulk)
+
+	write.POST("/tasks", taskHandler.CreateTaskHandler())
+	read.GET("/tasks", taskHandler.GetTasksHandler())
+	bulk.POST("/tasks/bulk", l.body, l.idempotent, taskHandler.BulkTaskHandler())
+	bulk.GET("/tasks/export", taskHandler.ExportTasksHandler())
+	bulk.POST("/tasks/import", l.importBody, taskHandler.ImportTasksHandler())
+	bulk.POST("/tasks/process", l.body, l.idempotent, taskHandler.ProcessTasksHandler())
+	read.GET("/tasks/:id", taskHandler.GetTaskHandler())
+	write.PUT("/tasks/:id", taskHandler.UpdateTaskHandler())
+	write.PATCH("/tasks/:id", taskHandler.PatchTaskHandler())
+	write.DELETE("/tasks/:id", taskHandler.DeleteTaskHandler())
+
+	write.POST("/tasks/:id/comments", commentHandler.CreateCommentHandler())
+	read.GET("/tasks/:id/comments", commentHandler.ListCommentsHandler())
+	write.PUT("/tasks/:id/comments/:commentId", commentHandler.UpdateCommentHandler())
+	write.DELETE("/tasks/:id/comments/:commentId", commentHandler.DeleteCommentHandler())
+	read.GET("/tasks/:id/activity", commentHandler.ListActivityHandler())
+
+	api.POST("/tasks/:id/attachments", l.write, l.attachmentBody, attachmentHandler.CreateAttachmentHandler())
+	read.GET("/tasks/:id/attachments", attachmentHandler.ListAttachmentsHandler())
+	read.GET("/tasks/:id/attachments/:attachmentId", attachmentHandler.GetAttachmentHandler())
+	read.GET("/tasks/:id/attachments/:attachmentId/content", attachmentHandler.DownloadAttachmentHandler())
+	write.DELETE("/tasks/:id/attachments/:attachmentId", attachmentHandler.DeleteAttachmentHandler())
+
+	write.POST("/labels", labelHandler.CreateLabelHandler())
+	read.GET("/labels", labelHandler.ListLabelsHandler())
+	write.DELETE("/labels/:labelId", labelHandler.DeleteLabelHandler())
+	write.PUT("/tasks/:id/labels/:labelId", labelHandler.AttachLabelHandler())
+	write.DELETE("/tasks/:id/labels/:labelId", labelHandler.DetachLabelHandler())
+
+	write.POST("/projects", projectHandler.CreateProjectHandler())
+	read.GET("/projects", projectHandler.ListProjectsHandler())
+	read.GET("/projects/:projectId", projectHandler.GetProjectHandler())
+	write.PUT("/projects/:projectId", projectHandler.UpdateProjectHandler())
+	write.DELETE("/projects/:projectId", projectHandler.DeleteProjectHandler())
+	write.POST("/projects/:projectId/tasks", taskHandler.CreateProjectTaskHandler())
+	read.GET("/projects/:projectId/tasks", taskHandler.ListProjectTasksHandler())
+	read.GET("/projects/:projectId/tasks/:id", taskHandler.GetProjectTaskHandler())
+	write.PUT("/projects/:projectId/tasks/:id", taskHandler.UpdateProjectTaskHandler())
+	write.PATCH("/projects/:projectId/tasks/:id", taskHandler.PatchProjectTaskHandler())
+	write.DELETE("/projects/:projectId/tasks/:id", taskHandler.DeleteProjectTaskHandler())
+	write.POST("/projects/:projectId/tasks/:id/comments", commentHandler.CreateProjectCommentHandler())
+	read.GET("/projects/:projectId/tasks/:id/comments", commentHandler.ListProjectCommentsHandler())
+	write.PUT("/projects/:projectId/tasks/:id/comments/:commentId", commentHandler.UpdateProjectCommentHandler())
+	write.DELETE("/projects/:projectId/tasks/:id/comments/:commentId", commentHandler.DeleteProjectCommentHandler())
+	read.GET("/projects/:projectId/tasks/:id/activity", commentHandler.ListProjectActivityHandler())
+	api.POST("/projects/:projectId/tasks/:id/attachments", l.write, l.attachmentBody, attachmentHandler.CreateProjectAttachmentHandler())
+	read.GET("/projects/:projectId/tasks/:id/attachments", attachmentHandler.ListProjectAttachmentsHandler())
+	read.GET("/projects/:projectId/tasks/:id/attachments/:attachmentId", attachmentHandler.GetProjectAttachmentHandler())
+	read.GET("/projects/:projectId/tasks/:id/attachments/:attachmentId/content", attachmentHandler.DownloadProjectAttachmentHandler())
+	write.DELETE("/projects/:projectId/tasks/:id/attachments/:attachmentId", attachmentHandler.DeleteProjectAttachmentHandler())
+	write.PUT("/projects/:projectId/tasks/:id/labels/:labelId", labelHandler.AttachProjectLabelHandler())
+	write.DELETE("/projects/:projectId/tasks/:id/labels/:labelId", labelHandler.DetachProjectLabelHandler())
+
+	v2Events := v2.NewEventsHandler(eventsHandler)
+	read.GET("/events", v2Events.StreamHandler())
+	read.GET("/ws", v2Events.WebSocketHandler())
+
+	write.POST("/webhooks", webhookHandler.CreateWebhookHandler())
+	read.GET("/webhooks", webhookHandler.ListWebhooksHandler())
+	read.GET("/webhooks/:webhookId", webhookHandler.GetWebhookHandler())
+	write.DELETE("/webhooks/:webhookId", webhookHandler.DeleteWebhookHandler())
+	read.GET("/webhooks/:webhookId/deliveries", webhookHandler.ListDeliveriesHandler())
+	write.POST("/webhooks/:webhookId/test", webhookHandler.TestWebhookHandler())
 }
@@ -0,0 +1,570 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"task_manager/internal/handler"
+	"task_manager/internal/response"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"testing"
+	"time"
+
+	v2 "task_manager/internal/handler/v2"
+
+	"gorm.io/gorm"
+)
+
+func TestProbes(t *testing.T) {
+	s := newTestServer(t)
+	for _, path := range []string{"/healthz", "/readyz", "/version", "/problems/not-found"} {
+		expect(t, s.do("GET", path, ""), http.StatusOK)
+	}
+	expect(t, s.do("GET", "/problems/unknown", ""), http.StatusNotFound)
+}
+
+func TestTasksV1(t *testing.T) {
+	for _, prefix := range []string{"/api/v1", ""} {
+		t.Run("prefix="+prefix, func(t *testing.T) {
+			s := newTestServer(t)
+
+			rec := s.do("POST", prefix+"/tasks", `{"name": "Write docs", "description": "v1"}`)
+			expect(t, rec, http.StatusCreated)
+			if rec.Header().Get("Deprecation") == "" {
+				t.Error("version 1 response has no Deprecation header")
+			}
+			created := decode[envelope[model.Task]](t, rec).Data
+			if created.ID.String() == "00000000-0000-0000-0000-000000000000" || created.CreatedAt.IsZero() {
+				t.Fatalf("created task lacks its stored fields: %+v", created)
+			}
+			if created.Status != model.StatusPending {
+				t.Errorf("status is %q, want %q", created.Status, model.StatusPending)
+			}
+			path := prefix + "/tasks/" + created.ID.String()
+
+			list := decode[envelope[[]model.Task]](t, s.do("GET", prefix+"/tasks", "")).Data
+			if len(list) != 1 || list[0].ID != created.ID {
+				t.Fatalf("list is %+v, want the created task", list)
+			}
+			got := decode[envelope[model.Task]](t, s.do("GET", path, "")).Data
+			if got.Name != "Write docs" {
+				t.Errorf("name is %q", got.Name)
+			}
+
+			rec = s.do("PUT", path, `{"name": "Write more docs", "status": "Completed"}`)
+			expect(t, rec, http.StatusOK)
+			replaced := decode[envelope[model.Task]](t, rec).Data
+			if replaced.Name != "Write more docs" || replaced.Description != "" || replaced.Status != model.StatusCompleted {
+				t.Errorf("PUT didn't replace every field: %+v", replaced)
+			}
+
+			rec = s.do("PATCH", path, `{"description": "merged"}`, "Content-Type", "application/merge-patch+json")
+			expect(t, rec, http.StatusOK)
+			if patched := decode[envelope[model.Task]](t, rec).Data; patched.Description != "merged" || patched.Name != "Write more docs" {
+				t.Errorf("merge patch gave %+v", patched)
+			}
+			rec = s.do("PATCH", path, `[{"op": "replace", "path": "/status", "value": "Pending"}]`, "Content-Type", "application/json-patch+json")
+			expect(t, rec, http.StatusOK)
+			if patched := decode[envelope[model.Task]](t, rec).Data; patched.Status != model.StatusPending {
+				t.Errorf("JSON patch gave %+v", patched)
+			}
+
+			rec = s.do("DELETE", path, "")
+			expect(t, rec, http.StatusOK)
+			if deleted := decode[envelope[string]](t, rec).Data; deleted != "Deleted successfully" {
+				t.Errorf("DELETE answered %q", deleted)
+			}
+			expectProblem(t, s.do("GET", path, ""), http.StatusNotFound)
+		})
+	}
+}
+
+func TestTasksV2(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do("POST", "/api/v2/tasks", `{"name": "Write docs"}`)
+	expect(t, rec, http.StatusCreated)
+	if rec.Header().Get("Deprecation") != "" {
+		t.Error("version 2 response is marked deprecated")
+	}
+	created := decode[model.Task](t, rec)
+	path := "/api/v2/tasks/" + created.ID.String()
+	if location := rec.Header().Get("Location"); location != path {
+		t.Errorf("Location is %q, want %q", location, path)
+	}
+	if created.ProjectID != model.DefaultProjectID {
+		t.Errorf("project is %v, want the default project", created.ProjectID)
+	}
+
+	list := decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks", ""))
+	if len(list.Items) != 1 || list.Items[0].ID != created.ID {
+		t.Fatalf("list is %+v, want the created task", list.Items)
+	}
+	if got := decode[model.Task](t, s.do("GET", path, "")); got.Name != "Write docs" {
+		t.Errorf("name is %q", got.Name)
+	}
+	rec = s.do("PUT", path, `{"name": "Replaced", "status": "Completed"}`)
+	expect(t, rec, http.StatusOK)
+	if replaced := decode[model.Task](t, rec); replaced.Status != model.StatusCompleted {
+		t.Errorf("PUT gave %+v", replaced)
+	}
+	rec = s.do("PATCH", path, `{"status": "Pending"}`, "Content-Type", "application/merge-patch+json")
+	expect(t, rec, http.StatusOK)
+	if patched := decode[model.Task](t, rec); patched.Status != model.StatusPending || patched.Name != "Replaced" {
+		t.Errorf("merge patch gave %+v", patched)
+	}
+	expect(t, s.do("DELETE", path, ""), http.StatusNoContent)
+	expectProblem(t, s.do("DELETE", path, ""), http.StatusNotFound)
+}
+
+func TestProjectScopedTaskResources(t *testing.T) {
+	s := newTestServer(t)
+	project := decode[model.Project](t, s.do("POST", "/api/v2/projects", `{"name": "Scoped"}`)).ID.String()
+	label := decode[model.Label](t, s.do("POST", "/api/v2/labels", `{"name": "scoped"}`)).ID.String()
+	scopedID := decode[model.Task](t, s.do("POST", "/api/v2/projects/"+project+"/tasks", `{"name": "Scoped"}`)).ID.String()
+	homeID := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "Default"}`)).ID.String()
+	scoped := "/api/v2/projects/" + project + "/tasks/" + scopedID
+
+	rec := s.do("POST", scoped+"/comments", `{"author": "ada", "body": "Scoped"}`)
+	expect(t, rec, http.StatusCreated)
+	comment := decode[model.Comment](t, rec).ID.String()
+	if want := scoped + "/comments/" + comment; rec.Header().Get("Location") != want {
+		t.Errorf("Location is %q, want %q", rec.Header().Get("Location"), want)
+	}
+	rec = s.upload(scoped+"/attachments", "screen.png", pngImage)
+	expect(t, rec, http.StatusCreated)
+	attachment := decode[model.Attachment](t, rec).ID.String()
+	expect(t, s.do("PUT", scoped+"/labels/"+label, ""), http.StatusOK)
+	if list := decode[v2.CommentList](t, s.do("GET", scoped+"/comments", "")); len(list.Items) != 1 {
+		t.Errorf("scoped comments are %+v", list.Items)
+	}
+
+	// The scoped task's resources are only reachable under its own project,
+	// and the default project's tasks aren't reachable under another one.
+	moved := "/api/v2/tasks/" + scopedID
+	stranger := "/api/v2/projects/" + project + "/tasks/" + homeID
+	tests := []struct {
+		name, method, path, body string
+	}{
+		{"comment", "POST", moved + "/comments", `{"author": "ada", "body": "Moved"}`},
+		{"list comments", "GET", moved + "/comments", ""},
+		{"edit comment", "PUT", moved + "/comments/" + comment, `{"body": "Moved"}`},
+		{"delete comment", "DELETE", moved + "/comments/" + comment, ""},
+		{"activity", "GET", moved + "/activity", ""},
+		{"attach", "POST", moved + "/attachments", formFile("file", "screen.png", pngImage)},
+		{"list attachments", "GET", moved + "/attachments", ""},
+		{"attachment", "GET", moved + "/attachments/" + attachment, ""},
+		{"download", "GET", moved + "/attachments/" + attachment + "/content", ""},
+		{"delete attachment", "DELETE", moved + "/attachments/" + attachment, ""},
+		{"label", "PUT", moved + "/labels/" + label, ""},
+		{"unlabel", "DELETE", moved + "/labels/" + label, ""},
+		{"comment elsewhere", "POST", stranger + "/comments", `{"author": "ada", "body": "Moved"}`},
+		{"activity elsewhere", "GET", stranger + "/activity", ""},
+		{"attach elsewhere", "POST", stranger + "/attachments", formFile("file", "screen.png", pngImage)},
+		{"label elsewhere", "PUT", stranger + "/labels/" + label, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header []string
+			if strings.HasSuffix(tt.path, "/attachments") && tt.method == "POST" {
+				header = []string{"Content-Type", multipartForm["Content-Type"]}
+			}
+			expectProblem(t, s.do(tt.method, tt.path, tt.body, header...), http.StatusNotFound)
+		})
+	}
+
+	if list := decode[v2.CommentList](t, s.do("GET", scoped+"/comments", "")); len(list.Items) != 1 {
+		t.Errorf("scoped comments after cross-project requests are %+v", list.Items)
+	}
+	expect(t, s.do("GET", scoped+"/attachments/"+attachment+"/content", ""), http.StatusOK)
+	expect(t, s.do("DELETE", scoped+"/labels/"+label, ""), http.StatusNoContent)
+}
+
+func TestTaskErrors(t *testing.T) {
+	s := newTestServer(t)
+	id := decode[model.Task](t, s.do("POST", "/api/v2/tasks", `{"name": "Existing"}`)).ID.String()
+	const unknown = "00000000-0000-4000-8000-000000000000"
+	oversized := `{"name": "` + strings.Repeat("x", int(s.cfg.MaxBodyBytes)) + `"}`
+
+	tests := []struct {
+		name               string
+		method, path, body string
+		contentType        string
+		status             int
+		field              string
+	}{
+		{name: "invalid id", method: "GET", path: "/tasks/not-an-id", status: http.StatusBadRequest},
+		{name: "unknown id", method: "GET", path: "/tasks/" + unknown, status: http.StatusNotFound},
+		{name: "replace unknown", method: "PUT", path: "/tasks/" + unknown, body: `{"name": "Missing"}`, status: http.StatusNotFound},
+		{name: "delete invalid id", method: "DELETE", path: "/tasks/not-an-id", status: http.StatusBadRequest},
+		{name: "blank name", method: "POST", path: "/tasks", body: `{"name": " "}`, status: http.StatusBadRequest, field: "name"},
+		{name: "long name", method: "POST", path: "/tasks", body: fmt.Sprintf(`{"name": %q}`, strings.Repeat("x", model.MaxTaskNameLength+1)), status: http.StatusBadRequest, field: "name"},
+		{name: "long description", method: "PUT", path: "/tasks/" + id, body: fmt.Sprintf(`{"name": "Existing", "description": %q}`, strings.Repeat("x", model.MaxTaskDescriptionLength+1)), status: http.StatusBadRequest, field: "description"},
+		{name: "unknown status", method: "PUT", path: "/tasks/" + id, body: `{"name": "Existing", "status": "Done"}`, status: http.StatusBadRequest, field: "status"},
+		{name: "wrong type", method: "POST", path: "/tasks", body: `{"name": 1}`, status: http.StatusBadRequest, field: "name"},
+		{name: "malformed JSON", method: "POST", path: "/tasks", body: `{"name": `, status: http.StatusBadRequest},
+		{name: "too large", method: "POST", path: "/tasks", body: oversized, status: http.StatusRequestEntityTooLarge},
+		{name: "unsupported patch", method: "PATCH", path: "/tasks/" + id, body: `status`, contentType: "text/plain", status: http.StatusUnsupportedMediaType},
+		{name: "invalid patch", method: "PATCH", path: "/tasks/" + id, body: `{"status": "Done"}`, contentType: "application/merge-patch+json", status: http.StatusBadRequest, field: "status"},
+		{name: "patch unknown", method: "PATCH", path: "/tasks/" + unknown, body: `{}`, contentType: "application/merge-patch+json", status: http.StatusNotFound},
+		{name: "invalid label match", method: "GET", path: "/tasks?label=a&match=some", status: http.StatusBadRequest},
+	}
+	for _, version := range []string{"/api/v1", "/api/v2"} {
+		for _, tt := range tests {
+			t.Run(version+" "+tt.name, func(t *testing.T) {
+				var header []string
+				if tt.contentType != "" {
+					header = []string{"Content-Type", tt.contentType}
+				}
+				problem := expectProblem(t, s.do(tt.method, version+tt.path, tt.body, header...), tt.status)
+				if tt.field == "" {
+					return
+				}
+				for _, err := range problem.Errors {
+					if err.Field == tt.field {
+						return
+					}
+				}
+				t.Errorf("errors %+v don't name %s", problem.Errors, tt.field)
+			})
+		}
+	}
+
+	t.Run("legacy envelope", func(t *testing.T) {
+		rec := s.do("GET", "/api/v1/tasks/"+unknown, "", "Accept", response.LegacyMediaType)
+		expect(t, rec, http.StatusNotFound)
+		if body := decode[response.Response](t, rec); body.Error == "" {
+			t.Errorf("legacy error response has no error: %s", rec.Body.String())
+		}
+	})
+}
+
+func TestBulkAndTransfer(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do("POST", "/api/v1/tasks/bulk", `{"atomic": true, "operations": [
+		{"op": "create", "task": {"name": "a"}},
+		{"op": "create", "task": {"name": "b"}},
+		{"op": "delete", "id": "00000000-0000-4000-8000-000000000000"}]}`)
+	expect(t, rec, http.StatusOK)
+	bulk := decode[envelope[handler.BulkResponse]](t, rec).Data
+	if bulk.Committed || bulk.Failed != 3 || bulk.Results[0].Status != http.StatusFailedDependency || bulk.Results[2].Status != http.StatusNotFound {
+		t.Errorf("atomic batch with a failure gave %+v", bulk)
+	}
+	if list := decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks", "")); len(list.Items) != 0 {
+		t.Fatalf("rolled back batch left %d tasks", len(list.Items))
+	}
+
+	rec = s.do("POST", "/api/v1/tasks/bulk", `{"operations": [{"op": "create", "task": {"name": "a"}}, {"op": "create", "task": {"name": "b"}}]}`)
+	expect(t, rec, http.StatusOK)
+	if bulk := decode[envelope[handler.BulkResponse]](t, rec).Data; !bulk.Committed || bulk.Succeeded != 2 {
+		t.Errorf("batch gave %+v", bulk)
+	}
+
+	rec = s.do("GET", "/api/v1/tasks/export?format=ndjson", "")
+	expect(t, rec, http.StatusOK)
+	exported := rec.Body.String()
+	if lines := strings.Count(exported, "\n"); lines != 2 {
+		t.Fatalf("export has %d lines, want 2:\n%s", lines, exported)
+	}
+
+	expect(t, s.do("DELETE", "/api/v2/tasks/"+decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks", "")).Items[0].ID.String(), ""), http.StatusNoContent)
+	rec = s.do("POST", "/api/v1/tasks/import?format=ndjson&on_conflict=skip", exported)
+	expect(t, rec, http.StatusOK)
+	if summary := decode[envelope[service.ImportSummary]](t, rec).Data; summary.Read != 2 || summary.Written != 1 || summary.Skipped != 1 {
+		t.Errorf("import gave %+v, want 2 read, 1 written and 1 skipped", summary)
+	}
+	expectProblem(t, s.do("POST", "/api/v1/tasks/import?format=ndjson", exported), http.StatusConflict)
+	expectProblem(t, s.do("GET", "/api/v1/tasks/export?format=xml", ""), http.StatusBadRequest)
+	expectProblem(t, s.do("POST", "/api/v1/tasks/bulk", `{"operations": []}`), http.StatusBadRequest)
+}
+
+// TestBulkAndTransferV2 checks that version 2 serves the bulk, import and
+// export endpoints without the envelope.
+func TestBulkAndTransferV2(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do("POST", "/api/v2/tasks/bulk", `{"operations": [{"op": "create", "task": {"name": "a"}}, {"op": "create", "task": {"name": "b"}}]}`)
+	expect(t, rec, http.StatusOK)
+	if bulk := decode[handler.BulkResponse](t, rec); !bulk.Committed || bulk.Succeeded != 2 || bulk.Results[0].Status != http.StatusCreated {
+		t.Errorf("batch gave %+v", bulk)
+	}
+
+	rec = s.do("GET", "/api/v2/tasks/export", "")
+	expect(t, rec, http.StatusOK)
+	exported := decode[[]model.Task](t, rec)
+	if len(exported) != 2 {
+		t.Fatalf("export has %d tasks, want 2", len(exported))
+	}
+
+	rec = s.do("POST", "/api/v2/tasks/import?on_conflict=update", rec.Body.String())
+	expect(t, rec, http.StatusOK)
+	if summary := decode[service.ImportSummary](t, rec); summary.Read != 2 || summary.Written != 2 {
+		t.Errorf("import gave %+v, want 2 read and written", summary)
+	}
+	expectProblem(t, s.do("POST", "/api/v2/tasks/import", `[{"id": "`+exported[0].ID.String()+`", "name": "a"}]`), http.StatusConflict)
+	expectProblem(t, s.do("GET", "/api/v2/tasks/export?format=xml", ""), http.StatusBadRequest)
+	expectProblem(t, s.do("POST", "/api/v2/tasks/bulk", `{"operations": []}`), http.StatusBadRequest)
+
+	rec = s.do("POST", "/api/v2/tasks/process", "")
+	expect(t, rec, http.StatusAccepted)
+	if queued := decode[v2.TaskList](t, rec); len(queued.Items) != 2 {
+		t.Errorf("process queued %d tasks, want 2", len(queued.Items))
+	}
+	rec = s.do("POST", "/api/v2/tasks/process", "")
+	expect(t, rec, http.StatusAccepted)
+	if queued := decode[v2.TaskList](t, rec); queued.Items == nil || len(queued.Items) != 0 {
+		t.Errorf("second process call queued %+v, want an empty list", queued.Items)
+	}
+}
+
+func TestImportHistoryAndBatches(t *testing.T) {
+	s := newTestServer(t)
+	const id = "0b6a3f5e-8a61-4d0c-9a0e-3c1f6f0f3a11"
+	activity := func() []model.Activity {
+		return decode[v2.ActivityList](t, s.do("GET", "/api/v2/tasks/"+id+"/activity", "")).Items
+	}
+
+	expect(t, s.do("POST", "/api/v2/tasks/import", `[{"id": "`+id+`", "name": "restored"}]`), http.StatusOK)
+	if entries := activity(); len(entries) != 1 || entries[0].Type != model.ActivityTaskCreated || entries[0].ToStatus != model.StatusPending {
+		t.Errorf("history after the import is %+v, want the creation", entries)
+	}
+	expect(t, s.do("POST", "/api/v2/tasks/import?on_conflict=update", `[{"id": "`+id+`", "name": "restored", "status": "Completed"}]`), http.StatusOK)
+	if entries := activity(); len(entries) != 2 || entries[1].Type != model.ActivityStatusChanged || entries[1].FromStatus != model.StatusPending || entries[1].ToStatus != model.StatusCompleted {
+		t.Errorf("history after the update is %+v, want a status change", entries)
+	}
+
+	// The first batch is written before the record failing in the second.
+	var body strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&body, "{\"name\": \"task %d\"}\n", i)
+	}
+	body.WriteString(`{"name": ""}` + "\n")
+	problem := expectProblem(t, s.do("POST", "/api/v2/tasks/import?format=ndjson", body.String()), http.StatusBadRequest)
+	if !strings.Contains(problem.Detail, "record 501") || !strings.Contains(problem.Detail, "500 tasks were imported before it") {
+		t.Errorf("detail is %q, want the failing record and the tasks written", problem.Detail)
+	}
+	rec := s.do("GET", "/api/v2/tasks/export?format=ndjson", "")
+	if lines := strings.Count(rec.Body.String(), "\n"); lines != 501 {
+		t.Errorf("%d tasks after the failed import, want 501", lines)
+	}
+}
+
+// TestExportLetsWritersIn checks that tasks can be written while an export
+// holds its cursor open.
+func TestExportLetsWritersIn(t *testing.T) {
+	s := newTestServer(t)
+	for _, name := range []string{"a", "b"} {
+		expect(t, s.do("POST", "/api/v2/tasks", `{"name": "`+name+`"}`), http.StatusCreated)
+	}
+
+	tasks := service.NewTaskService(s.db, nil)
+	exported := 0
+	err := tasks.ExportTasks(context.Background(), func(model.Task) error {
+		if exported == 0 {
+			start := time.Now()
+			expect(t, s.do("POST", "/api/v2/tasks", `{"name": "during the export"}`), http.StatusCreated)
+			if waited := time.Since(start); waited > time.Second {
+				t.Errorf("the write waited %v for the export", waited)
+			}
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The export reads the tasks as they were when it started.
+	if exported != 2 {
+		t.Errorf("exported %d tasks, want 2", exported)
+	}
+}
+
+func TestProcessTasks(t *testing.T) {
+	s := newTestServer(t)
+	const count = 20
+	for i := 0; i < count; i++ {
+		expect(t, s.do("POST", "/api/v2/tasks", fmt.Sprintf(`{"name": "task %d"}`, i)), http.StatusCreated)
+	}
+
+	// Concurrent calls split the pending tasks between them.
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, 2)
+	for i := range recs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recs[i] = s.do("POST", "/api/v1/tasks/process", "")
+		}()
+	}
+	wg.Wait()
+	claimed := map[string]bool{}
+	for _, rec := range recs {
+		expect(t, rec, http.StatusAccepted)
+		for _, task := range decode[envelope[[]model.Task]](t, rec).Data {
+			if claimed[task.ID.String()] {
+				t.Errorf("task %s was queued twice", task.ID)
+			}
+			claimed[task.ID.String()] = true
+		}
+	}
+	if len(claimed) != count {
+		t.Errorf("%d tasks were queued, want %d", len(claimed), count)
+	}
+
+	// Shutting the workers down waits for the queue to drain.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.workers.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range decode[v2.TaskList](t, s.do("GET", "/api/v2/tasks?limit=100", "")).Items {
+		if task.Status != model.StatusCompleted {
+			t.Errorf("task %s is %s after shutdown", task.ID, task.Status)
+		}
+	}
+	expect(t, s.do("POST", "/api/v1/tasks/process", ""), http.StatusServiceUnavailable)
+}
+
+// graphql runs a GraphQL query and decodes its data into a T.
+func graphql[T any](t *testing.T, s *testServer, query string) T {
+	t.Helper()
+	rec := s.do("POST", "/graphql", fmt.Sprintf(`{"query": %q}`, query))
+	expect(t, rec, http.StatusOK)
+	body := decode[struct {
+		Data   T                          `json:"data"`
+		Errors []struct{ Message string } `json:"errors"`
+	}](t, rec)
+	if len(body.Errors) > 0 {
+		t.Fatalf("query failed: %+v", body.Errors)
+	}
+	return body.Data
+}
+
+func TestPagination(t *testing.T) {
+	s := newTestServer(t)
+	for i := range 5 {
+		expect(t, s.do("POST", "/api/v2/tasks", fmt.Sprintf(`{"name": "task %d"}`, i)), http.StatusCreated)
+	}
+
+	type page struct {
+		Tasks struct {
+			Nodes      []struct{ Name string }
+			TotalCount int
+			PageInfo   struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+	}
+	var names []string
+	after := ""
+	for pages := 1; ; pages++ {
+		query := `{ tasks(first: 2` + after + `) { nodes { name } totalCount pageInfo { hasNextPage endCursor } } }`
+		p := graphql[page](t, s, query)
+		if p.Tasks.TotalCount != 5 {
+			t.Errorf("totalCount is %d, want 5", p.Tasks.TotalCount)
+		}
+		for _, node := range p.Tasks.Nodes {
+			names = append(names, node.Name)
+		}
+		if !p.Tasks.PageInfo.HasNextPage {
+			if pages != 3 {
+				t.Errorf("got %d pages, want 3", pages)
+			}
+			break
+		}
+		after = fmt.Sprintf(`, after: %q`, *p.Tasks.PageInfo.EndCursor)
+	}
+	if want := "task 0,task 1,task 2,task 3,task 4"; strings.Join(names, ",") != want {
+		t.Errorf("pages hold %v, want %s, oldest first", names, want)
+	}
+
+	filtered := graphql[page](t, s, `{ tasks(filter: {search: "TASK 3"}) { nodes { name } totalCount pageInfo { hasNextPage } } }`)
+	if filtered.Tasks.TotalCount != 1 || filtered.Tasks.PageInfo.HasNextPage {
+		t.Errorf("search gave %+v", filtered.Tasks)
+	}
+}
+
+func TestSearchMatchesWildcardsLiterally(t *testing.T) {
+	s := newTestServer(t)
+	for _, name := range []string{"50% off", "500 off", "snake_case", "snakeXcase", `C:\tasks`} {
+		expect(t, s.do("POST", "/api/v2/tasks", fmt.Sprintf(`{"name": %q}`, name)), http.StatusCreated)
+	}
+
+	type page struct {
+		Tasks struct {
+			Nodes []struct{ Name string }
+		}
+	}
+	for search, want := range map[string]string{`%`: "50% off", `_`: "snake_case", `\`: `C:\tasks`} {
+		got := graphql[page](t, s, fmt.Sprintf(`{ tasks(filter: {search: %q}) { nodes { name } } }`, search))
+		if len(got.Tasks.Nodes) != 1 || got.Tasks.Nodes[0].Name != want {
+			t.Errorf("search %q gave %+v, want only %q", search, got.Tasks.Nodes, want)
+		}
+	}
+}
+
+func TestGraphQLActivity(t *testing.T) {
+	s := newTestServer(t)
+	var ids []string
+	for _, name := range []string{"plain", "discussed", "done"} {
+		ids = append(ids, decode[model.Task](t, s.do("POST", "/api/v2/tasks", fmt.Sprintf(`{"name": %q}`, name))).ID.String())
+	}
+	expect(t, s.do("POST", "/api/v2/tasks/"+ids[1]+"/comments", `{"author": "manjeet", "body": "Looks good"}`), http.StatusCreated)
+	expect(t, s.do("PUT", "/api/v2/tasks/"+ids[2], `{"name": "done", "status": "Completed"}`), http.StatusOK)
+
+	// Count the queries reading activity, however the tasks are selected.
+	var queries atomic.Int32
+	err := s.db.Callback().Query().After("gorm:query").Register("count_activity", func(db *gorm.DB) {
+		if db.Statement.Table == "activities" {
+			queries.Add(1)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type activity struct {
+		Type       string
+		FromStatus *string
+		ToStatus   *string
+		Comment    *struct{ Author, Body string }
+	}
+	type task struct {
+		Name     string
+		Activity []activity
+	}
+	page := graphql[struct {
+		Tasks struct{ Nodes []task }
+	}](t, s, `{ tasks { nodes { name activity { type fromStatus toStatus comment { author body } } } } }`)
+	if n := queries.Load(); n != 1 {
+		t.Errorf("activity of a page took %d queries, want 1", n)
+	}
+	if len(page.Tasks.Nodes) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(page.Tasks.Nodes))
+	}
+	byName := map[string][]activity{}
+	for _, node := range page.Tasks.Nodes {
+		byName[node.Name] = node.Activity
+	}
+	if a := byName["plain"]; len(a) != 1 || a[0].Type != "TASK_CREATED" || a[0].FromStatus != nil || *a[0].ToStatus != "Pending" {
+		t.Errorf("activity of a new task is %+v", a)
+	}
+	if a := byName["discussed"]; len(a) != 2 || a[1].Type != "COMMENT" || a[1].Comment == nil || a[1].Comment.Body != "Looks good" {
+		t.Errorf("activity of a commented task is %+v", a)
+	}
+	if a := byName["done"]; len(a) != 2 || a[1].Type != "STATUS_CHANGED" || *a[1].FromStatus != "Pending" || *a[1].ToStatus != "Completed" {
+		t.Errorf("activity of a completed task is %+v", a)
+	}
+
+	one := graphql[struct{ Task task }](t, s, fmt.Sprintf(`{ task(id: %q) { name activity { type } } }`, ids[1]))
+	if len(one.Task.Activity) != 2 {
+		t.Errorf("activity of one task is %+v", one.Task.Activity)
+	}
+}
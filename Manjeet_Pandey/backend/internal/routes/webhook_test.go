@@ -0,0 +1,51 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/model"
+	"testing"
+
+	v2 "task_manager/internal/handler/v2"
+)
+
+func TestWebhooksV2(t *testing.T) {
+	s := newTestServer(t)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(receiver.Close)
+
+	rec := s.do("POST", "/api/v2/webhooks", fmt.Sprintf(`{"url": %q, "events": ["task.created"]}`, receiver.URL))
+	expect(t, rec, http.StatusCreated)
+	hook := decode[model.Webhook](t, rec)
+	if hook.Secret == "" {
+		t.Error("the generated secret isn't returned on creation")
+	}
+	if want := "/api/v2/webhooks/" + hook.ID.String(); rec.Header().Get("Location") != want {
+		t.Errorf("Location is %q, want %q", rec.Header().Get("Location"), want)
+	}
+	path := "/api/v2/webhooks/" + hook.ID.String()
+
+	hooks := decode[v2.WebhookList](t, s.do("GET", "/api/v2/webhooks", ""))
+	if len(hooks.Items) != 1 || hooks.Items[0].Secret != "" {
+		t.Errorf("listed %+v, want the webhook without its secret", hooks.Items)
+	}
+	if got := decode[model.Webhook](t, s.do("GET", path, "")); got.ID != hook.ID || got.Secret != "" {
+		t.Errorf("got %+v, want the webhook without its secret", got)
+	}
+
+	rec = s.do("POST", path+"/test", "")
+	expect(t, rec, http.StatusOK)
+	if delivery := decode[model.WebhookDelivery](t, rec); !delivery.Success || delivery.StatusCode != http.StatusOK {
+		t.Errorf("test delivery gave %+v", delivery)
+	}
+	if deliveries := decode[v2.DeliveryList](t, s.do("GET", path+"/deliveries", "")); len(deliveries.Items) != 1 {
+		t.Errorf("delivery log has %d entries, want 1", len(deliveries.Items))
+	}
+
+	expect(t, s.do("DELETE", path, ""), http.StatusNoContent)
+	expectProblem(t, s.do("GET", path, ""), http.StatusNotFound)
+	expectProblem(t, s.do("GET", path+"/deliveries", ""), http.StatusNotFound)
+	expectProblem(t, s.do("GET", "/api/v2/webhooks/not-an-id", ""), http.StatusBadRequest)
+	expectProblem(t, s.do("POST", "/api/v2/webhooks", `{"url": "not a url", "events": ["*"]}`), http.StatusBadRequest)
+}
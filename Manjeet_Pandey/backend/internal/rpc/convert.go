@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"task_manager/internal/events"
+	"task_manager/internal/logging"
+	"task_manager/internal/service"
+	"task_manager/internal/validation"
+	"task_manager/model"
+	taskv1 "task_manager/proto/task/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	statusToProto = map[model.TaskStatus]taskv1.TaskStatus{
+		model.StatusPending:   taskv1.TaskStatus_TASK_STATUS_PENDING,
+		model.StatusCompleted: taskv1.TaskStatus_TASK_STATUS_COMPLETED,
+	}
+	statusFromProto = map[taskv1.TaskStatus]model.TaskStatus{
+		taskv1.TaskStatus_TASK_STATUS_PENDING:   model.StatusPending,
+		taskv1.TaskStatus_TASK_STATUS_COMPLETED: model.StatusCompleted,
+	}
+	eventTypes = map[events.Type]taskv1.TaskEventType{
+		events.TaskCreated:    taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED,
+		events.TaskUpdated:    taskv1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+		events.TaskDeleted:    taskv1.TaskEventType_TASK_EVENT_TYPE_DELETED,
+		events.TaskProcessing: taskv1.TaskEventType_TASK_EVENT_TYPE_PROCESSING,
+		events.TaskCompleted:  taskv1.TaskEventType_TASK_EVENT_TYPE_COMPLETED,
+		events.TaskFailed:     taskv1.TaskEventType_TASK_EVENT_TYPE_FAILED,
+		events.TasksImported:  taskv1.TaskEventType_TASK_EVENT_TYPE_IMPORTED,
+	}
+)
+
+// fromProtoStatus maps TASK_STATUS_UNSPECIFIED to the empty status, which
+// the service treats as pending.
+func fromProtoStatus(s taskv1.TaskStatus) model.TaskStatus {
+	if s == taskv1.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		return ""
+	}
+	if status, ok := statusFromProto[s]; ok {
+		return status
+	}
+	// Left invalid so the service rejects it.
+	return model.TaskStatus(s.String())
+}
+
+func toProtoTask(t model.Task) *taskv1.Task {
+	return &taskv1.Task{
+		Id:          t.ID.String(),
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      statusToProto[t.Status],
+		CreateTime:  timestamppb.New(t.CreatedAt),
+		UpdateTime:  timestamppb.New(t.UpdatedAt),
+	}
+}
+
+func toProtoEvent(e events.Event) *taskv1.TaskEvent {
+	event := &taskv1.TaskEvent{
+		Id:    e.ID,
+		Type:  eventTypes[e.Type],
+		Error: e.Error,
+		Time:  timestamppb.New(e.Time),
+	}
+	if e.TaskID != uuid.Nil {
+		event.TaskId = e.TaskID.String()
+	}
+	if e.Task != nil {
+		event.Task = toProtoTask(*e.Task)
+	}
+	return event
+}
+
+func parseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid task id")
+	}
+	return parsed, nil
+}
+
+// statusError maps service errors onto status codes. Validation errors
+// carry the invalid fields as BadRequest details. Unrecognised errors are
+// reported without their details so internals don't leak.
+func statusError(ctx context.Context, err error) error {
+	var (
+		notFound *service.NotFoundError
+		invalid  *service.ValidationError
+		conflict *service.ConflictError
+		fields   validation.Errors
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, notFound.Error())
+	case errors.As(err, &invalid):
+		st := status.New(codes.InvalidArgument, invalid.Error())
+		if errors.As(err, &fields) {
+			details := &errdetails.BadRequest{}
+			for _, f := range fields {
+				details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+					Field:       f.Field,
+					Description: f.Message,
+					Reason:      f.Rule,
+				})
+			}
+			if withDetails, err := st.WithDetails(details); err == nil {
+				st = withDetails
+			}
+		}
+		return st.Err()
+	case errors.As(err, &conflict):
+		return status.Error(codes.AlreadyExists, conflict.Error())
+	default:
+		logging.Ctx(ctx).Err(err).Msg("gRPC call failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
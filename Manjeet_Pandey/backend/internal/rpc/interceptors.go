@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"task_manager/internal/logging"
+	"task_manager/internal/tracing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key of the request ID, the gRPC counterpart
+// of the X-Request-ID header.
+const requestIDKey = "x-request-id"
+
+// maxRequestIDLength bounds client supplied IDs so they can't bloat logs.
+const maxRequestIDLength = 128
+
+// withRequestID propagates the caller's request ID or assigns a new one,
+// echoes it in the response header and attaches it, with a logger, to ctx.
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" || len(id) > maxRequestIDLength {
+		id = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return tracing.WithLogger(logging.WithRequestID(ctx, id))
+}
+
+// logCall writes one structured entry per call, like LoggerMiddleware does
+// for HTTP requests.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	logger := logging.Ctx(ctx)
+	var event *zerolog.Event
+	switch code {
+	case codes.OK, codes.Canceled:
+		event = logger.Info()
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		event = logger.Error()
+	default:
+		event = logger.Warn()
+	}
+	service, rpc, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	event.
+		Str("service", service).
+		Str("method", rpc).
+		Str("code", code.String()).
+		Dur("latency", time.Since(start)).
+		Msg("gRPC call")
+}
+
+// UnaryInterceptor tags each call with a request ID and logs it.
+func UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx = withRequestID(ctx)
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamInterceptor tags each stream with a request ID and logs it when the
+// stream ends.
+func StreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx := withRequestID(ss.Context())
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	logCall(ctx, info.FullMethod, start, err)
+	return err
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,226 @@
+// Package rpc serves the task.v1 gRPC API, backed by the same TaskService as
+// the REST API.
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"task_manager/internal/events"
+	"task_manager/internal/service"
+	"task_manager/model"
+	taskv1 "task_manager/proto/task/v1"
+	"task_manager/util"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type TaskServer struct {
+	taskv1.UnimplementedTaskServiceServer
+	tasks    service.TaskService
+	workers  *util.Worker
+	shutdown <-chan struct{}
+}
+
+// NewTaskServer serves tasks. ProcessTasks hands the tasks it claims to
+// workers, which the server owns. Open WatchTasks streams end when shutdown
+// is closed, so they don't hold up a graceful server shutdown.
+func NewTaskServer(tasks service.TaskService, workers *util.Worker, shutdown <-chan struct{}) *TaskServer {
+	return &TaskServer{tasks: tasks, workers: workers, shutdown: shutdown}
+}
+
+func (s *TaskServer) CreateTask(ctx context.Context, req *taskv1.CreateTaskRequest) (*taskv1.Task, error) {
+	task, err := s.tasks.CreateTask(ctx, model.Task{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Status:      fromProtoStatus(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProtoTask(task), nil
+}
+
+func (s *TaskServer) GetTask(ctx context.Context, req *taskv1.GetTaskRequest) (*taskv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.GetTask(ctx, id)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProtoTask(*task), nil
+}
+
+func (s *TaskServer) ListTasks(ctx context.Context, req *taskv1.ListTasksRequest) (*taskv1.ListTasksResponse, error) {
+	if req.GetPageSize() < 0 || req.GetPageSize() > service.MaxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", service.MaxPageSize)
+	}
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	q := service.TaskQuery{
+		Search: req.GetSearch(),
+		Offset: offset,
+		Limit:  int(req.GetPageSize()),
+	}
+	for _, st := range req.GetStatuses() {
+		q.Statuses = append(q.Statuses, fromProtoStatus(st))
+	}
+	page, err := s.tasks.FindTasks(ctx, q)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	resp := &taskv1.ListTasksResponse{TotalSize: page.Total}
+	for _, task := range page.Tasks {
+		resp.Tasks = append(resp.Tasks, toProtoTask(task))
+	}
+	if page.HasMore {
+		resp.NextPageToken = encodePageToken(offset + len(page.Tasks))
+	}
+	return resp, nil
+}
+
+// UpdateTask replaces the task when the mask is empty. Otherwise the masked
+// fields are applied as a merge patch, so it is validated like
+// PATCH /tasks/{id}.
+func (s *TaskServer) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskRequest) (*taskv1.Task, error) {
+	task := req.GetTask()
+	if task == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+	id, err := parseID(task.GetId())
+	if err != nil {
+		return nil, err
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		updated, err := s.tasks.ReplaceTask(ctx, id, model.Task{
+			Name:        task.GetName(),
+			Description: task.GetDescription(),
+			Status:      fromProtoStatus(task.GetStatus()),
+		})
+		if err != nil {
+			return nil, statusError(ctx, err)
+		}
+		return toProtoTask(updated), nil
+	}
+
+	fields := map[string]any{}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			fields["name"] = task.GetName()
+		case "description":
+			fields["description"] = task.GetDescription()
+		case "status":
+			if task.GetStatus() == taskv1.TaskStatus_TASK_STATUS_UNSPECIFIED {
+				return nil, status.Error(codes.InvalidArgument, "status must be set when it is in update_mask")
+			}
+			fields["status"] = fromProtoStatus(task.GetStatus())
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "update_mask path %q is not one of name, description or status", path)
+		}
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	patch, _ := service.NewPatch(service.MergePatchContentType, body)
+	updated, err := s.tasks.PatchTask(ctx, id, patch)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProtoTask(updated), nil
+}
+
+func (s *TaskServer) DeleteTask(ctx context.Context, req *taskv1.DeleteTaskRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tasks.DeleteTask(ctx, id); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServer) WatchTasks(req *taskv1.WatchTasksRequest, stream taskv1.TaskService_WatchTasksServer) error {
+	var filter events.Filter
+	for _, st := range req.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, fromProtoStatus(st))
+	}
+	for _, raw := range req.GetTaskIds() {
+		id, err := parseID(raw)
+		if err != nil {
+			return err
+		}
+		filter.TaskIDs = append(filter.TaskIDs, id)
+	}
+	bus := s.tasks.Events()
+	if bus == nil {
+		return status.Error(codes.Unavailable, "task events are not available")
+	}
+	sub := bus.Subscribe(filter)
+	defer sub.Close()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toProtoEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *TaskServer) ProcessTasks(ctx context.Context, _ *taskv1.ProcessTasksRequest) (*taskv1.ProcessTasksResponse, error) {
+	tasks, err := s.tasks.ClaimPendingTasks(ctx)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	// The call's context is cancelled once it returns; the jobs only keep
+	// its span context so their spans join this trace.
+	queued := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	if !s.workers.Submit(queued, tasks) {
+		return nil, status.Error(codes.Unavailable, "the server is shutting down")
+	}
+	resp := &taskv1.ProcessTasksResponse{}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, toProtoTask(task))
+	}
+	return resp, nil
+}
+
+const pageTokenPrefix = "task:"
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && len(raw) > len(pageTokenPrefix) && string(raw[:len(pageTokenPrefix)]) == pageTokenPrefix {
+		if offset, err := strconv.Atoi(string(raw[len(pageTokenPrefix):])); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, status.Error(codes.InvalidArgument, "page_token is not valid")
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ActivityService reads the history of the tasks of one project.
+type ActivityService struct {
+	db      *gorm.DB
+	project uuid.UUID
+}
+
+// NewActivityService creates a service for the default project.
+func NewActivityService(db *gorm.DB) ActivityService {
+	return ActivityService{db: db, project: model.DefaultProjectID}
+}
+
+// InProject returns a copy of the service scoped to another project.
+func (s ActivityService) InProject(projectID uuid.UUID) ActivityService {
+	s.project = projectID
+	return s
+}
+
+// ListActivity returns the history of a task, oldest first: its creation,
+// status changes and comments. Comment entries carry the comment as it is
+// now.
+func (s *ActivityService) ListActivity(ctx context.Context, taskID uuid.UUID) (_ []model.Activity, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ActivityService.ListActivity")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	if err := taskExists(db, s.project, taskID); err != nil {
+		return nil, err
+	}
+	entries, err := loadActivity(db, []uuid.UUID{taskID})
+	return entries[taskID], err
+}
+
+// ListActivityOf returns the histories of several tasks, keyed by task ID,
+// with two queries however many tasks there are. Tasks that don't exist
+// have no history.
+func (s *ActivityService) ListActivityOf(ctx context.Context, taskIDs []uuid.UUID) (_ map[uuid.UUID][]model.Activity, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ActivityService.ListActivityOf")
+	defer func() { tracing.End(span, err) }()
+
+	return loadActivity(s.db.WithContext(ctx), taskIDs)
+}
+
+// loadActivity reads the histories of the tasks, oldest entry first, and
+// the comments their comment entries refer to.
+func loadActivity(db *gorm.DB, taskIDs []uuid.UUID) (map[uuid.UUID][]model.Activity, error) {
+	var entries []model.Activity
+	if err := db.Where("task_id IN ?", taskIDs).Order("id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	var commentIDs []uuid.UUID
+	for _, entry := range entries {
+		if entry.CommentID != nil {
+			commentIDs = append(commentIDs, *entry.CommentID)
+		}
+	}
+	if len(commentIDs) > 0 {
+		var comments []model.Comment
+		if err := db.Where("id IN ?", commentIDs).Find(&comments).Error; err != nil {
+			return nil, err
+		}
+		byID := make(map[uuid.UUID]*model.Comment, len(comments))
+		for i := range comments {
+			byID[comments[i].ID] = &comments[i]
+		}
+		for i := range entries {
+			if entries[i].CommentID != nil {
+				entries[i].Comment = byID[*entries[i].CommentID]
+			}
+		}
+	}
+	byTask := make(map[uuid.UUID][]model.Activity, len(taskIDs))
+	for _, entry := range entries {
+		byTask[entry.TaskID] = append(byTask[entry.TaskID], entry)
+	}
+	return byTask, nil
+}
+
+// taskExists reports a NotFoundError when the project has no task with the
+// ID.
+func taskExists(tx *gorm.DB, projectID, id uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&model.Task{}).Where("id = ? AND project_id = ?", id, projectID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return &NotFoundError{Resource: taskResource, ID: id}
+	}
+	return nil
+}
+
+// recordStatusChange adds a status change to the history of a task, unless
+// the status stayed the same.
+func recordStatusChange(tx *gorm.DB, taskID uuid.UUID, from, to model.TaskStatus) error {
+	if from == to {
+		return nil
+	}
+	return tx.Create(&model.Activity{TaskID: taskID, Type: model.ActivityStatusChanged, FromStatus: from, ToStatus: to}).Error
+}
+
+// deleteHistory removes the comments and history of a deleted task.
+func deleteHistory(tx *gorm.DB, taskID uuid.UUID) error {
+	if err := tx.Where("task_id = ?", taskID).Delete(&model.Comment{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("task_id = ?", taskID).Delete(&model.Activity{}).Error
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"task_manager/internal/logging"
+	"task_manager/internal/storage"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const attachmentResource = "attachment"
+
+// AttachmentLimits bound what can be uploaded.
+type AttachmentLimits struct {
+	// MaxBytes caps the size of a single file.
+	MaxBytes int64
+	// Types lists the accepted content types. An entry such as image/*
+	// accepts every subtype.
+	Types []string
+}
+
+func (l AttachmentLimits) allows(contentType string) bool {
+	major, _, _ := strings.Cut(contentType, "/")
+	for _, t := range l.Types {
+		if t == contentType || t == major+"/*" || t == "*/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// AttachmentService manages the attachments of the tasks of one project.
+type AttachmentService struct {
+	db      *gorm.DB
+	store   storage.Storage
+	limits  AttachmentLimits
+	project uuid.UUID
+}
+
+// NewAttachmentService creates a service for the default project.
+func NewAttachmentService(db *gorm.DB, store storage.Storage, limits AttachmentLimits) AttachmentService {
+	return AttachmentService{db: db, store: store, limits: limits, project: model.DefaultProjectID}
+}
+
+// InProject returns a copy of the service scoped to another project.
+func (s AttachmentService) InProject(projectID uuid.UUID) AttachmentService {
+	s.project = projectID
+	return s
+}
+
+// CreateAttachment streams r to storage and records it against the task.
+// The content type is sniffed from the contents; declaredType, the type the
+// client sent, only refines plain text. The SHA-256 digest and size are
+// computed on the way through.
+func (s *AttachmentService) CreateAttachment(ctx context.Context, taskID uuid.UUID, fileName, declaredType string, r io.Reader) (_ model.Attachment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.CreateAttachment")
+	defer func() { tracing.End(span, err) }()
+
+	if err := taskExists(s.db.WithContext(ctx), s.project, taskID); err != nil {
+		return model.Attachment{}, err
+	}
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return model.Attachment{}, err
+	}
+	attachment := model.Attachment{
+		ID:          uuid.New(),
+		TaskID:      taskID,
+		FileName:    cleanFileName(fileName),
+		ContentType: detectType(head, declaredType),
+	}
+	if !s.limits.allows(attachment.ContentType) {
+		return model.Attachment{}, &UnsupportedTypeError{Type: attachment.ContentType}
+	}
+
+	hash := sha256.New()
+	body := &limitedReader{r: io.TeeReader(br, hash), remaining: s.limits.MaxBytes}
+	if err := s.store.Put(ctx, attachment.StorageKey(), body, attachment.ContentType); err != nil {
+		if body.remaining < 0 {
+			return model.Attachment{}, &TooLargeError{Limit: s.limits.MaxBytes}
+		}
+		return model.Attachment{}, err
+	}
+	attachment.Size = s.limits.MaxBytes - body.remaining
+	attachment.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	if err := s.db.WithContext(ctx).Create(&attachment).Error; err != nil {
+		s.deleteObject(ctx, attachment)
+		return model.Attachment{}, translateError(err, attachmentResource, attachment.ID)
+	}
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of a task, oldest first.
+func (s *AttachmentService) ListAttachments(ctx context.Context, taskID uuid.UUID) (_ []model.Attachment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.ListAttachments")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	if err := taskExists(db, s.project, taskID); err != nil {
+		return nil, err
+	}
+	var attachments []model.Attachment
+	if err := db.Where("task_id = ?", taskID).Order("created_at, id").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *AttachmentService) GetAttachment(ctx context.Context, taskID, id uuid.UUID) (_ model.Attachment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.GetAttachment")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	if err := taskExists(db, s.project, taskID); err != nil {
+		return model.Attachment{}, err
+	}
+	var attachment model.Attachment
+	if err := db.Where("task_id = ?", taskID).First(&attachment, id).Error; err != nil {
+		return model.Attachment{}, translateError(err, attachmentResource, id)
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns an attachment with its contents. The caller closes
+// the reader.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, taskID, id uuid.UUID) (_ model.Attachment, _ io.ReadSeekCloser, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.OpenAttachment")
+	defer func() { tracing.End(span, err) }()
+
+	attachment, err := s.GetAttachment(ctx, taskID, id)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	contents, err := s.store.Open(ctx, attachment.StorageKey())
+	if err != nil {
+		return model.Attachment{}, nil, translateError(err, attachmentResource, id)
+	}
+	return attachment, contents, nil
+}
+
+// DeleteAttachment removes the contents of an attachment, then its record,
+// so a failure never leaves contents that nothing points to.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, taskID, id uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.DeleteAttachment")
+	defer func() { tracing.End(span, err) }()
+
+	attachment, err := s.GetAttachment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey()); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Delete(&attachment).Error
+}
+
+// PurgeOrphans deletes the attachments of tasks that no longer exist.
+// Deleting a task leaves its attachments behind, since the storage can't
+// take part in the transaction; this clears them up afterwards.
+func (s *AttachmentService) PurgeOrphans(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AttachmentService.PurgeOrphans")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	var orphans []model.Attachment
+	if err := db.Where("task_id NOT IN (?)", db.Model(&model.Task{}).Select("id")).Find(&orphans).Error; err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, attachment := range orphans {
+		if err := s.store.Delete(ctx, attachment.StorageKey()); err != nil {
+			return purged, err
+		}
+		if err := db.Delete(&attachment).Error; err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *AttachmentService) deleteObject(ctx context.Context, attachment model.Attachment) {
+	if err := s.store.Delete(context.WithoutCancel(ctx), attachment.StorageKey()); err != nil {
+		logging.Ctx(ctx).Err(err).Str("key", attachment.StorageKey()).Msg("Cannot delete the contents of an unsaved attachment")
+	}
+}
+
+// detectType sniffs the content type from the first bytes of a file. Text
+// formats can't be told apart that way, so for plain text a more specific
+// text or JSON type declared by the client is kept.
+func detectType(head []byte, declared string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	declared, _, _ = mime.ParseMediaType(declared)
+	if sniffed == "text/plain" && (strings.HasPrefix(declared, "text/") || declared == "application/json") {
+		return declared
+	}
+	return sniffed
+}
+
+// cleanFileName keeps the last element of a client supplied path, without
+// control characters and cut to MaxAttachmentNameLength bytes.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	for len(name) > model.MaxAttachmentNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// limitedReader fails once more than remaining bytes have been read, which
+// leaves remaining negative.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errors.New("file exceeds the size limit")
+	}
+	return n, err
+}
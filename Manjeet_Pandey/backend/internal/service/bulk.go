@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manager/internal/events"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxBulkOperations caps the number of operations in a single batch.
+const MaxBulkOperations = 1000
+
+type BulkOp string
+
+const (
+	BulkCreate BulkOp = "create"
+	BulkUpdate BulkOp = "update"
+	BulkDelete BulkOp = "delete"
+	BulkStatus BulkOp = "status"
+)
+
+var (
+	// ErrRolledBack marks operations that succeeded but were undone because
+	// another operation in an atomic batch failed.
+	ErrRolledBack = errors.New("rolled back: another operation in the batch failed")
+	// ErrSkipped marks operations that never ran because an atomic batch had
+	// already failed.
+	ErrSkipped = errors.New("skipped: another operation in the batch failed")
+)
+
+// BulkOperation is a single step of a batch. Create and update read Task,
+// status reads Status, and update, delete and status address the task by ID.
+type BulkOperation struct {
+	Op     BulkOp           `json:"op" enums:"create,update,delete,status"`
+	ID     uuid.UUID        `json:"id,omitempty"`
+	Task   *model.Task      `json:"task,omitempty"`
+	Status model.TaskStatus `json:"status,omitempty"`
+}
+
+// BulkRequest is a batch of operations run in one transaction. With Atomic
+// set, any failure rolls back the whole batch; otherwise each operation
+// commits or fails on its own.
+type BulkRequest struct {
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+// BulkResult is the outcome of one operation, in request order.
+type BulkResult struct {
+	Index int         `json:"index"`
+	Op    BulkOp      `json:"op"`
+	ID    uuid.UUID   `json:"id"`
+	Task  *model.Task `json:"task,omitempty"`
+	Err   error       `json:"-"`
+}
+
+// Bulk runs every operation of the batch inside a single transaction and
+// reports a result for each one. The returned error is only set when the
+// batch as a whole could not be run.
+func (s *TaskService) Bulk(ctx context.Context, req BulkRequest) (_ []BulkResult, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.Bulk")
+	defer func() { tracing.End(span, err) }()
+
+	if len(req.Operations) == 0 {
+		return nil, &ValidationError{Err: errors.New("batch has no operations")}
+	}
+	if len(req.Operations) > MaxBulkOperations {
+		return nil, &ValidationError{Err: fmt.Errorf("batch has %d operations, the limit is %d", len(req.Operations), MaxBulkOperations)}
+	}
+
+	results := make([]BulkResult, len(req.Operations))
+	failed := -1
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := TaskService{db: tx, project: s.project}
+		for i, op := range req.Operations {
+			savepoint := fmt.Sprintf("bulk_op_%d", i)
+			if !req.Atomic {
+				if err := tx.SavePoint(savepoint).Error; err != nil {
+					return err
+				}
+			}
+			results[i] = txService.runBulkOperation(ctx, i, op)
+			if results[i].Err == nil {
+				continue
+			}
+			if req.Atomic {
+				failed = i
+				return results[i].Err
+			}
+			if err := tx.RollbackTo(savepoint).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if failed >= 0 {
+		for i := range results {
+			switch {
+			case i < failed:
+				results[i].Task = nil
+				results[i].Err = ErrRolledBack
+			case i > failed:
+				results[i] = BulkResult{Index: i, Op: req.Operations[i].Op, ID: req.Operations[i].ID, Err: ErrSkipped}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.publishBulk(results)
+	return results, nil
+}
+
+// publishBulk announces the committed results of a batch. Operations inside
+// the transaction run without a bus so that rolled back work is never
+// published.
+func (s *TaskService) publishBulk(results []BulkResult) {
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		switch result.Op {
+		case BulkCreate:
+			s.publish(events.TaskCreated, *result.Task)
+		case BulkUpdate, BulkStatus:
+			s.publish(events.TaskUpdated, *result.Task)
+		case BulkDelete:
+			s.events.Publish(events.Event{Type: events.TaskDeleted, TaskID: result.ID})
+		}
+	}
+}
+
+func (s *TaskService) runBulkOperation(ctx context.Context, index int, op BulkOperation) BulkResult {
+	result := BulkResult{Index: index, Op: op.Op, ID: op.ID}
+	var (
+		task model.Task
+		err  error
+	)
+	switch op.Op {
+	case BulkCreate:
+		if op.Task == nil {
+			result.Err = &ValidationError{Err: errors.New("create requires a task")}
+			return result
+		}
+		task, err = s.CreateTask(ctx, *op.Task)
+	case BulkUpdate:
+		if op.Task == nil {
+			result.Err = &ValidationError{Err: errors.New("update requires a task")}
+			return result
+		}
+		task, err = s.ReplaceTask(ctx, op.ID, *op.Task)
+	case BulkStatus:
+		task, err = s.UpdateStatus(ctx, op.ID, op.Status)
+	case BulkDelete:
+		err = s.DeleteTask(ctx, op.ID)
+	default:
+		err = &ValidationError{Err: fmt.Errorf("unknown operation %q", op.Op)}
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if op.Op != BulkDelete {
+		result.ID = task.ID
+		result.Task = &task
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const commentResource = "comment"
+
+// CommentService manages the comments on the tasks of one project.
+type CommentService struct {
+	db      *gorm.DB
+	project uuid.UUID
+}
+
+// NewCommentService creates a service for the default project.
+func NewCommentService(db *gorm.DB) CommentService {
+	return CommentService{db: db, project: model.DefaultProjectID}
+}
+
+// InProject returns a copy of the service scoped to another project.
+func (s CommentService) InProject(projectID uuid.UUID) CommentService {
+	s.project = projectID
+	return s
+}
+
+// CreateComment adds a comment to a task and to its activity feed.
+func (s *CommentService) CreateComment(ctx context.Context, taskID uuid.UUID, req model.CreateCommentRequest) (_ model.Comment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CommentService.CreateComment")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validate(req); err != nil {
+		return model.Comment{}, err
+	}
+	comment := req.Comment()
+	comment.TaskID = taskID
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, s.project, taskID); err != nil {
+			return err
+		}
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.Activity{TaskID: taskID, Type: model.ActivityComment, CommentID: &comment.ID}).Error
+	})
+	if err != nil {
+		return model.Comment{}, translateError(err, commentResource, comment.ID)
+	}
+	return comment, nil
+}
+
+// ListComments returns the comments on a task, oldest first.
+func (s *CommentService) ListComments(ctx context.Context, taskID uuid.UUID) (_ []model.Comment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CommentService.ListComments")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	if err := taskExists(db, s.project, taskID); err != nil {
+		return nil, err
+	}
+	var comments []model.Comment
+	if err := db.Where("task_id = ?", taskID).Order("created_at, id").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// UpdateComment replaces the body of a comment on a task and marks it as
+// edited.
+func (s *CommentService) UpdateComment(ctx context.Context, taskID, id uuid.UUID, req model.UpdateCommentRequest) (_ model.Comment, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CommentService.UpdateComment")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validate(req); err != nil {
+		return model.Comment{}, err
+	}
+	var comment model.Comment
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, s.project, taskID); err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", taskID).First(&comment, id).Error; err != nil {
+			return err
+		}
+		if comment.Body == req.Body {
+			return nil
+		}
+		now := time.Now()
+		comment.Body = req.Body
+		comment.EditedAt = &now
+		return tx.Save(&comment).Error
+	})
+	if err != nil {
+		return model.Comment{}, translateError(err, commentResource, id)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment from a task. Its entry stays in the
+// activity feed, without the comment.
+func (s *CommentService) DeleteComment(ctx context.Context, taskID, id uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CommentService.DeleteComment")
+	defer func() { tracing.End(span, err) }()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, s.project, taskID); err != nil {
+			return err
+		}
+		result := tx.Where("task_id = ?", taskID).Delete(&model.Comment{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &NotFoundError{Resource: commentResource, ID: id}
+		}
+		return nil
+	})
+}
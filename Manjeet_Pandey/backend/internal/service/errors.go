@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task_manager/internal/storage"
+	"task_manager/internal/validation"
+	"task_manager/model"
+
+	"gorm.io/gorm"
+)
+
+// NotFoundError reports that the requested resource does not exist.
+type NotFoundError struct {
+	Resource string
+	ID       any
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %v not found", e.Resource, e.ID)
+}
+
+// ValidationError reports input that the service refuses to store.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError reports a write that clashes with existing data.
+type ConflictError struct {
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// validate checks a request against its validate tags.
+func validate(req any) error {
+	if err := validation.Struct(req); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+// translateError maps GORM and model errors onto the service error types.
+// Errors it does not recognise are returned unchanged.
+func translateError(err error, resource string, id any) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, storage.ErrNotFound):
+		return &NotFoundError{Resource: resource, ID: id}
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &ConflictError{Err: fmt.Errorf("%s %v already exists", resource, id)}
+	case errors.Is(err, model.ErrInvalidStatus):
+		return &ValidationError{Err: err}
+	default:
+		return err
+	}
+}
+
+// TooLargeError reports an upload larger than the service accepts.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("file too large, the limit is %d bytes", e.Limit)
+}
+
+// UnsupportedTypeError reports an upload whose content type isn't allowed.
+type UnsupportedTypeError struct {
+	Type string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("files of type %s are not accepted", e.Type)
+}
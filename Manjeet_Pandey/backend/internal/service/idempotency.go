@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"task_manager/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// purgeInterval is how often expired idempotency records are deleted.
+const purgeInterval = time.Minute
+
+var (
+	// ErrIdempotencyKeyReused means the key was first used for a different
+	// request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotencyInFlight means the first request with the key has not
+	// finished yet.
+	ErrIdempotencyInFlight = errors.New("a request with this idempotency key is still being processed")
+)
+
+type IdempotencyService struct {
+	db  *gorm.DB
+	ttl time.Duration
+	// lastPurge is shared by copies of the service, in Unix nanoseconds.
+	lastPurge *atomic.Int64
+}
+
+// NewIdempotencyService keeps responses for ttl after the first request.
+func NewIdempotencyService(db *gorm.DB, ttl time.Duration) IdempotencyService {
+	return IdempotencyService{db: db, ttl: ttl, lastPurge: &atomic.Int64{}}
+}
+
+// Begin claims key for a request with the given fingerprint. It returns the
+// stored record when the request was already completed and should be
+// replayed, or nil when the caller should handle the request and then call
+// Complete or Release.
+func (s *IdempotencyService) Begin(ctx context.Context, client, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	now := time.Now()
+	s.purgeExpired(ctx, now)
+	db := s.db.WithContext(ctx)
+	for {
+		claim := model.IdempotencyRecord{Client: client, Key: key, Fingerprint: fingerprint, ExpiresAt: now.Add(s.ttl)}
+		err := db.Create(&claim).Error
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, err
+		}
+
+		var stored model.IdempotencyRecord
+		err = db.Where("client = ? AND key = ?", client, key).First(&stored).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released between the insert and the read; try again.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case stored.ExpiresAt.Before(now):
+			if err := db.Delete(&stored).Error; err != nil {
+				return nil, err
+			}
+			continue
+		case stored.Fingerprint != fingerprint:
+			return nil, ErrIdempotencyKeyReused
+		case stored.Status == 0:
+			return nil, ErrIdempotencyInFlight
+		default:
+			return &stored, nil
+		}
+	}
+}
+
+// Complete stores the response to replay for retries of the request.
+func (s *IdempotencyService) Complete(ctx context.Context, client, key string, status int, contentType string, header http.Header, body []byte) error {
+	return s.db.WithContext(ctx).Model(&model.IdempotencyRecord{}).
+		Where("client = ? AND key = ?", client, key).
+		Updates(model.IdempotencyRecord{Status: status, ContentType: contentType, Header: header, Body: body}).Error
+}
+
+// Release forgets the key so that a retry runs the request again.
+func (s *IdempotencyService) Release(ctx context.Context, client, key string) error {
+	return s.db.WithContext(ctx).Where("client = ? AND key = ?", client, key).Delete(&model.IdempotencyRecord{}).Error
+}
+
+func (s *IdempotencyService) purgeExpired(ctx context.Context, now time.Time) {
+	last := s.lastPurge.Load()
+	if now.UnixNano()-last < int64(purgeInterval) || !s.lastPurge.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&model.IdempotencyRecord{})
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"task_manager/internal/events"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const labelResource = "label"
+
+// LabelMatch says how many of the labels in a LabelFilter a task must
+// carry.
+type LabelMatch string
+
+const (
+	// MatchAll keeps tasks carrying every label.
+	MatchAll LabelMatch = "all"
+	// MatchAny keeps tasks carrying at least one of the labels.
+	MatchAny LabelMatch = "any"
+)
+
+// LabelFilter selects tasks by the names of their labels. The zero value
+// keeps every task.
+type LabelFilter struct {
+	Labels []string
+	// Match defaults to MatchAll.
+	Match LabelMatch
+}
+
+// ParseLabelFilter builds a filter from label names, each of which may be a
+// comma separated list, and a match of all, any or "".
+func ParseLabelFilter(names []string, match string) (LabelFilter, error) {
+	filter := LabelFilter{Match: LabelMatch(match)}
+	switch filter.Match {
+	case "":
+		filter.Match = MatchAll
+	case MatchAll, MatchAny:
+	default:
+		return LabelFilter{}, &ValidationError{Err: fmt.Errorf("match must be %s or %s", MatchAll, MatchAny)}
+	}
+	for _, list := range names {
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter.Labels = append(filter.Labels, name)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// apply narrows a query on tasks to those matching the filter.
+func (f LabelFilter) apply(db *gorm.DB) *gorm.DB {
+	if len(f.Labels) == 0 {
+		return db
+	}
+	tagged := db.Session(&gorm.Session{NewDB: true}).Table("task_labels").
+		Select("task_labels.task_id").
+		Joins("JOIN labels ON labels.id = task_labels.label_id").
+		Where("labels.name IN ?", f.Labels)
+	if f.Match != MatchAny {
+		distinct := map[string]bool{}
+		for _, name := range f.Labels {
+			distinct[name] = true
+		}
+		tagged = tagged.Group("task_labels.task_id").Having("COUNT(DISTINCT labels.id) = ?", len(distinct))
+	}
+	return db.Where("tasks.id IN (?)", tagged)
+}
+
+// orderedLabels preloads the labels of tasks by name.
+func orderedLabels(db *gorm.DB) *gorm.DB {
+	return db.Order("labels.name")
+}
+
+// detachLabels removes every label from a deleted task.
+func detachLabels(tx *gorm.DB, taskID uuid.UUID) error {
+	return tx.Exec("DELETE FROM task_labels WHERE task_id = ?", taskID).Error
+}
+
+// LabelService manages the labels, which every project shares, and puts
+// them on the tasks of one project.
+type LabelService struct {
+	db      *gorm.DB
+	events  *events.Bus
+	project uuid.UUID
+}
+
+// NewLabelService creates a service for the default project that publishes
+// a task.updated event to bus whenever the labels of a task change. bus may
+// be nil.
+func NewLabelService(db *gorm.DB, bus *events.Bus) LabelService {
+	return LabelService{db: db, events: bus, project: model.DefaultProjectID}
+}
+
+// InProject returns a copy of the service scoped to another project.
+func (s LabelService) InProject(projectID uuid.UUID) LabelService {
+	s.project = projectID
+	return s
+}
+
+func (s *LabelService) CreateLabel(ctx context.Context, req model.CreateLabelRequest) (_ model.Label, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelService.CreateLabel")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validate(req); err != nil {
+		return model.Label{}, err
+	}
+	label := req.Label()
+	if err := s.db.WithContext(ctx).Create(&label).Error; err != nil {
+		return model.Label{}, translateError(err, labelResource, label.Name)
+	}
+	return label, nil
+}
+
+// ListLabels returns every label by name.
+func (s *LabelService) ListLabels(ctx context.Context) (_ []model.Label, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelService.ListLabels")
+	defer func() { tracing.End(span, err) }()
+
+	var labels []model.Label
+	if err := s.db.WithContext(ctx).Order("name").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// DeleteLabel deletes a label and removes it from the tasks carrying it.
+func (s *LabelService) DeleteLabel(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelService.DeleteLabel")
+	defer func() { tracing.End(span, err) }()
+
+	var tasks []model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Table("task_labels").Where("label_id = ?", id).Pluck("task_id", &ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_labels WHERE label_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Label{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &NotFoundError{Resource: labelResource, ID: id}
+		}
+		for _, taskID := range ids {
+			task, err := announceLabels(tx, taskID)
+			if err != nil {
+				return err
+			}
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		s.events.Publish(events.Event{Type: events.TaskUpdated, Task: &task})
+	}
+	return nil
+}
+
+// AttachLabel puts a label on a task and returns the task with its labels.
+// Attaching a label the task already carries changes nothing.
+func (s *LabelService) AttachLabel(ctx context.Context, taskID, labelID uuid.UUID) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelService.AttachLabel")
+	defer func() { tracing.End(span, err) }()
+
+	var task model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, s.project, taskID); err != nil {
+			return err
+		}
+		if err := tx.First(&model.Label{}, labelID).Error; err != nil {
+			return translateError(err, labelResource, labelID)
+		}
+		if err := tx.Exec("INSERT INTO task_labels (task_id, label_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, labelID).Error; err != nil {
+			return err
+		}
+		task, err = announceLabels(tx, taskID)
+		return err
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+	s.events.Publish(events.Event{Type: events.TaskUpdated, Task: &task})
+	return task, nil
+}
+
+// DetachLabel takes a label off a task. It fails with a NotFoundError when
+// the task doesn't carry the label.
+func (s *LabelService) DetachLabel(ctx context.Context, taskID, labelID uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelService.DetachLabel")
+	defer func() { tracing.End(span, err) }()
+
+	var task model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, s.project, taskID); err != nil {
+			return err
+		}
+		result := tx.Exec("DELETE FROM task_labels WHERE task_id = ? AND label_id = ?", taskID, labelID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &NotFoundError{Resource: labelResource, ID: labelID}
+		}
+		task, err = announceLabels(tx, taskID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.events.Publish(events.Event{Type: events.TaskUpdated, Task: &task})
+	return nil
+}
+
+// announceLabels reads a task with its new labels and writes the
+// task.updated event announcing them to the webhook outbox, in the
+// transaction that changed them.
+func announceLabels(tx *gorm.DB, taskID uuid.UUID) (model.Task, error) {
+	var task model.Task
+	if err := tx.Preload("Labels", orderedLabels).First(&task, taskID).Error; err != nil {
+		return model.Task{}, err
+	}
+	return task, enqueueEvent(tx, events.Event{Type: events.TaskUpdated, Task: &task})
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// Patch transforms the JSON document of a resource.
+type Patch interface {
+	Apply(doc []byte) ([]byte, error)
+}
+
+// MergePatch is an RFC 7396 JSON Merge Patch document.
+type MergePatch []byte
+
+func (p MergePatch) Apply(doc []byte) ([]byte, error) {
+	out, err := jsonpatch.MergePatch(doc, p)
+	if err != nil {
+		return nil, patchError(err)
+	}
+	return out, nil
+}
+
+// JSONPatch is an RFC 6902 JSON Patch document.
+type JSONPatch []byte
+
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	ops, err := jsonpatch.DecodePatch(p)
+	if err != nil {
+		return nil, patchError(err)
+	}
+	out, err := ops.Apply(doc)
+	if err != nil {
+		return nil, patchError(err)
+	}
+	return out, nil
+}
+
+// NewPatch picks the patch format from the request content type. Plain
+// application/json is treated as a merge patch.
+func NewPatch(contentType string, body []byte) (Patch, bool) {
+	switch contentType {
+	case JSONPatchContentType:
+		return JSONPatch(body), true
+	case MergePatchContentType, "application/json", "":
+		return MergePatch(body), true
+	default:
+		return nil, false
+	}
+}
+
+// patchError reports a patch document that is malformed or cannot be applied.
+func patchError(err error) error {
+	return &ValidationError{Err: fmt.Errorf("invalid patch: %w", err)}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const projectResource = "project"
+
+// projectExists reports a NotFoundError when there is no project with the
+// ID.
+func projectExists(tx *gorm.DB, id uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&model.Project{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return &NotFoundError{Resource: projectResource, ID: id}
+	}
+	return nil
+}
+
+type ProjectService struct {
+	db *gorm.DB
+}
+
+func NewProjectService(db *gorm.DB) ProjectService {
+	return ProjectService{db: db}
+}
+
+func (s *ProjectService) CreateProject(ctx context.Context, req model.CreateProjectRequest) (_ model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.CreateProject")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validate(req); err != nil {
+		return model.Project{}, err
+	}
+	project := req.Project()
+	if err := s.db.WithContext(ctx).Create(&project).Error; err != nil {
+		return model.Project{}, translateError(err, projectResource, project.Name)
+	}
+	return project, nil
+}
+
+// ListProjects returns every project by name.
+func (s *ProjectService) ListProjects(ctx context.Context) (_ []model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.ListProjects")
+	defer func() { tracing.End(span, err) }()
+
+	var projects []model.Project
+	if err := s.db.WithContext(ctx).Order("name").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (s *ProjectService) GetProject(ctx context.Context, id uuid.UUID) (_ model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.GetProject")
+	defer func() { tracing.End(span, err) }()
+
+	var project model.Project
+	if err := s.db.WithContext(ctx).First(&project, id).Error; err != nil {
+		return model.Project{}, translateError(err, projectResource, id)
+	}
+	return project, nil
+}
+
+// FindProject returns the project whose ID or name is ref.
+func (s *ProjectService) FindProject(ctx context.Context, ref string) (_ model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.FindProject")
+	defer func() { tracing.End(span, err) }()
+
+	if id, err := uuid.Parse(ref); err == nil {
+		return s.GetProject(ctx, id)
+	}
+	var project model.Project
+	if err := s.db.WithContext(ctx).Where("name = ?", ref).First(&project).Error; err != nil {
+		return model.Project{}, translateError(err, projectResource, ref)
+	}
+	return project, nil
+}
+
+// ReplaceProject overwrites the name, description and concurrency limit of
+// a project.
+func (s *ProjectService) ReplaceProject(ctx context.Context, id uuid.UUID, req model.UpdateProjectRequest) (_ model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.ReplaceProject")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validate(req); err != nil {
+		return model.Project{}, err
+	}
+	var stored model.Project
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&stored, id).Error; err != nil {
+			return err
+		}
+		stored.Name = req.Name
+		stored.Description = req.Description
+		stored.MaxConcurrency = req.MaxConcurrency
+		return tx.Save(&stored).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return model.Project{}, translateError(err, projectResource, req.Name)
+	}
+	if err != nil {
+		return model.Project{}, translateError(err, projectResource, id)
+	}
+	return stored, nil
+}
+
+// DeleteProject deletes an empty project. Projects that still have tasks,
+// and the default project, can't be deleted.
+func (s *ProjectService) DeleteProject(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.DeleteProject")
+	defer func() { tracing.End(span, err) }()
+
+	if id == model.DefaultProjectID {
+		return &ConflictError{Err: errors.New("the default project can't be deleted")}
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := projectExists(tx, id); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.Task{}).Where("project_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &ConflictError{Err: fmt.Errorf("project %v still has %d tasks", id, count)}
+		}
+		return tx.Delete(&model.Project{}, id).Error
+	})
+}
+
+// CountByStatus returns the number of tasks in each status across every
+// project.
+func (s *ProjectService) CountByStatus(ctx context.Context) (_ map[model.TaskStatus]int64, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectService.CountByStatus")
+	defer func() { tracing.End(span, err) }()
+
+	var rows []struct {
+		Status model.TaskStatus
+		Count  int64
+	}
+	if err := s.db.WithContext(ctx).Model(&model.Task{}).Select("status, count(*) as count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[model.TaskStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"task_manager/internal/tracing"
+	"task_manager/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxPageSize caps how many tasks FindTasks returns at once.
+const MaxPageSize = 100
+
+// likeEscaper makes % and _ in a search match themselves in a LIKE pattern
+// with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// TaskQuery selects a page of tasks, oldest first.
+type TaskQuery struct {
+	// Statuses keeps only tasks in one of these statuses.
+	Statuses []model.TaskStatus
+	// Search keeps only tasks whose name or description contains it,
+	// ignoring case.
+	Search string
+	// Offset is how many matching tasks to skip.
+	Offset int
+	// Limit is the page size, at most MaxPageSize.
+	Limit int
+}
+
+// TaskPage is one page of the tasks matching a TaskQuery.
+type TaskPage struct {
+	Tasks []model.Task
+	// Total counts every matching task, not just this page.
+	Total   int64
+	HasMore bool
+}
+
+// FindTasks returns the page of tasks selected by q.
+func (s *TaskService) FindTasks(ctx context.Context, q TaskQuery) (_ TaskPage, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.FindTasks")
+	defer func() { tracing.End(span, err) }()
+
+	for _, status := range q.Statuses {
+		if err := status.Validate(); err != nil {
+			return TaskPage{}, &ValidationError{Err: err}
+		}
+	}
+	if q.Limit <= 0 || q.Limit > MaxPageSize {
+		q.Limit = MaxPageSize
+	}
+	query := s.tasks(s.db.WithContext(ctx)).Model(&model.Task{})
+	if len(q.Statuses) > 0 {
+		query = query.Where("status IN ?", q.Statuses)
+	}
+	if q.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(q.Search)) + "%"
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+
+	// A new session lets the count and the page reuse the conditions.
+	query = query.Session(&gorm.Session{})
+
+	var page TaskPage
+	if err := query.Count(&page.Total).Error; err != nil {
+		return TaskPage{}, err
+	}
+	// One extra row tells whether another page follows.
+	err = query.Order("created_at, id").Offset(max(q.Offset, 0)).Limit(q.Limit + 1).Find(&page.Tasks).Error
+	if err != nil {
+		return TaskPage{}, err
+	}
+	if len(page.Tasks) > q.Limit {
+		page.Tasks = page.Tasks[:q.Limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
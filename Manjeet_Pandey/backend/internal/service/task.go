@@ -1,62 +1,338 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"task_manager/internal/events"
+	"task_manager/internal/tracing"
 	"task_manager/model"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const taskResource = "task"
+
+// ClaimTimeout is how long a claim on a pending task holds. Tasks claimed
+// by a worker that went away are claimed again once their claim is older.
+const ClaimTimeout = 10 * time.Minute
+
+// TaskService manages the tasks of one project: every query is scoped to
+// it, so tasks of other projects can't be seen or changed.
 type TaskService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	events  *events.Bus
+	project uuid.UUID
 }
 
-func NewTaskService(db *gorm.DB) TaskService {
-	return TaskService{db: db}
+// NewTaskService creates a service for the default project that publishes
+// every change to bus. bus may be nil when nobody listens for changes.
+func NewTaskService(db *gorm.DB, bus *events.Bus) TaskService {
+	return TaskService{db: db, events: bus, project: model.DefaultProjectID}
 }
 
-func (s *TaskService) CreateTask(task model.Task) error {
-	if err := s.db.Create(&task).Error; err != nil {
-		return err
+// InProject returns a copy of the service scoped to another project.
+func (s TaskService) InProject(projectID uuid.UUID) TaskService {
+	s.project = projectID
+	return s
+}
+
+// Project returns the project the service is scoped to.
+func (s *TaskService) Project(ctx context.Context) (_ model.Project, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.Project")
+	defer func() { tracing.End(span, err) }()
+
+	var project model.Project
+	if err := s.db.WithContext(ctx).First(&project, s.project).Error; err != nil {
+		return model.Project{}, translateError(err, projectResource, s.project)
 	}
-	return nil
+	return project, nil
+}
+
+// tasks starts a query on the tasks of the project.
+func (s *TaskService) tasks(db *gorm.DB) *gorm.DB {
+	return db.Where("tasks.project_id = ?", s.project)
+}
+
+// Events returns the bus the service publishes changes to.
+func (s *TaskService) Events() *events.Bus {
+	return s.events
+}
+
+// publish announces a committed change of the task on the bus. The event
+// has already been written to the webhook outbox by the transaction.
+func (s *TaskService) publish(eventType events.Type, task model.Task) {
+	s.events.Publish(events.Event{Type: eventType, Task: &task})
+}
+
+// Announce publishes an event that goes with no change to the task, such as
+// a worker picking it up, writing it to the webhook outbox first. The event
+// is published even when it can't be written.
+func (s *TaskService) Announce(ctx context.Context, event events.Event) error {
+	err := enqueueEvent(s.db.WithContext(ctx), event)
+	s.events.Publish(event)
+	return err
 }
 
-func (s *TaskService) ListTask() ([]model.Task, error) {
+// CreateTask stores a new task, starts its activity feed and returns it
+// with its generated ID and timestamps.
+func (s *TaskService) CreateTask(ctx context.Context, task model.Task) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.CreateTask")
+	defer func() { tracing.End(span, err) }()
+
+	req := model.TaskRequest{Name: task.Name, Description: task.Description, Status: task.Status}
+	if err := validate(req); err != nil {
+		return model.Task{}, err
+	}
+	// Only the writable fields are kept; the ID and timestamps are ours.
+	task = req.Task()
+	if task.Status == "" {
+		task.Status = model.StatusPending
+	}
+	task.ProjectID = s.project
+	task.TraceParent = tracing.TraceParent(ctx)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := projectExists(tx, s.project); err != nil {
+			return err
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.Activity{TaskID: task.ID, Type: model.ActivityTaskCreated, ToStatus: task.Status}).Error; err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.TaskCreated, Task: &task})
+	})
+	if err != nil {
+		return model.Task{}, translateError(err, taskResource, task.ID)
+	}
+	s.publish(events.TaskCreated, task)
+	return task, nil
+}
+
+// ListTask returns the tasks matching filter, with their labels.
+func (s *TaskService) ListTask(ctx context.Context, filter LabelFilter) (_ []model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.ListTask")
+	defer func() { tracing.End(span, err) }()
+
 	var tasks []model.Task
-	if err := s.db.Find(&tasks).Error; err != nil {
+	query := filter.apply(s.tasks(s.db.WithContext(ctx)).Preload("Labels", orderedLabels))
+	if err := query.Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
-func (s *TaskService) GetTask(id uuid.UUID) (*model.Task, error) {
+func (s *TaskService) GetTask(ctx context.Context, id uuid.UUID) (_ *model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.GetTask")
+	defer func() { tracing.End(span, err) }()
+
 	var task model.Task
-	if err := s.db.First(&task, id).Error; err != nil {
-		return nil, err
+	if err := s.tasks(s.db.WithContext(ctx)).Preload("Labels", orderedLabels).First(&task, id).Error; err != nil {
+		return nil, translateError(err, taskResource, id)
 	}
 	return &task, nil
 }
 
-func (s *TaskService) UpdateTask(id uuid.UUID, task model.Task) error {
-	if err := s.db.Where("id = ?", id).Updates(&task).Error; err != nil {
-		return err
+// ReplaceTask overwrites every client-writable field of the task, including
+// zero values. A missing status falls back to the column default.
+func (s *TaskService) ReplaceTask(ctx context.Context, id uuid.UUID, task model.Task) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.ReplaceTask")
+	defer func() { tracing.End(span, err) }()
+
+	var stored model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.tasks(tx).Preload("Labels", orderedLabels).First(&stored, id).Error; err != nil {
+			return err
+		}
+		if err := replace(tx, &stored, task); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.TaskUpdated, Task: &stored})
+	})
+	if err != nil {
+		return model.Task{}, translateError(err, taskResource, id)
 	}
-	return nil
+	s.publish(events.TaskUpdated, stored)
+	return stored, nil
+}
+
+// PatchTask applies an RFC 7396 merge patch or RFC 6902 JSON patch to the
+// JSON representation of the task and stores the result.
+func (s *TaskService) PatchTask(ctx context.Context, id uuid.UUID, patch Patch) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.PatchTask")
+	defer func() { tracing.End(span, err) }()
+
+	var stored model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.tasks(tx).Preload("Labels", orderedLabels).First(&stored, id).Error; err != nil {
+			return err
+		}
+		doc, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		patched, err := patch.Apply(doc)
+		if err != nil {
+			return err
+		}
+		var task model.Task
+		if err := json.Unmarshal(patched, &task); err != nil {
+			return patchError(err)
+		}
+		if err := replace(tx, &stored, task); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.TaskUpdated, Task: &stored})
+	})
+	if err != nil {
+		return model.Task{}, translateError(err, taskResource, id)
+	}
+	s.publish(events.TaskUpdated, stored)
+	return stored, nil
+}
+
+// UpdateStatus changes only the status of a task.
+func (s *TaskService) UpdateStatus(ctx context.Context, id uuid.UUID, status model.TaskStatus) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	return s.updateStatus(ctx, id, status)
+}
+
+// CompleteTask marks a processed task completed. It is announced both as
+// updated and as completed.
+func (s *TaskService) CompleteTask(ctx context.Context, id uuid.UUID) (_ model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.CompleteTask")
+	defer func() { tracing.End(span, err) }()
+
+	task, err := s.updateStatus(ctx, id, model.StatusCompleted, events.TaskCompleted)
+	if err != nil {
+		return model.Task{}, err
+	}
+	s.publish(events.TaskCompleted, task)
+	return task, nil
+}
+
+// updateStatus changes the status of a task and announces the change as
+// task.updated. The events of also are written to the outbox with it, for
+// the caller to publish.
+func (s *TaskService) updateStatus(ctx context.Context, id uuid.UUID, status model.TaskStatus, also ...events.Type) (_ model.Task, err error) {
+	if err := status.Validate(); err != nil {
+		return model.Task{}, &ValidationError{Err: err}
+	}
+	var stored model.Task
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.tasks(tx).Preload("Labels", orderedLabels).First(&stored, id).Error; err != nil {
+			return err
+		}
+		from := stored.Status
+		stored.Status = status
+		stored.ClaimedAt = nil
+		if err := tx.Omit(clause.Associations).Save(&stored).Error; err != nil {
+			return err
+		}
+		if err := recordStatusChange(tx, id, from, status); err != nil {
+			return err
+		}
+		for _, eventType := range append([]events.Type{events.TaskUpdated}, also...) {
+			if err := enqueueEvent(tx, events.Event{Type: eventType, Task: &stored}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Task{}, translateError(err, taskResource, id)
+	}
+	s.publish(events.TaskUpdated, stored)
+	return stored, nil
 }
 
-func (s *TaskService) DeleteTask(id uuid.UUID) error {
-	if err := s.db.Delete(&model.Task{}, id).Error; err != nil {
+func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.DeleteTask")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := s.tasks(tx).Delete(&model.Task{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &NotFoundError{Resource: taskResource, ID: id}
+		}
+		if err := deleteHistory(tx, id); err != nil {
+			return err
+		}
+		if err := detachLabels(tx, id); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.TaskDeleted, TaskID: id})
+	})
+	if err != nil {
 		return err
 	}
+	s.events.Publish(events.Event{Type: events.TaskDeleted, TaskID: id})
 	return nil
 }
 
-func (s *TaskService) GetPendingTasks() ([]model.Task, error) {
-	var tasks []model.Task
-	err := s.db.Where("status = ?", model.StatusPending).Find(&tasks).Error
-	if err != nil {
+// ClaimPendingTasks claims the pending tasks of the project that no worker
+// holds and returns them. Each task is claimed with its own conditional
+// update, so a task claimed by a concurrent call is left out rather than
+// processed twice. Processing releases the claim when it changes the
+// status.
+func (s *TaskService) ClaimPendingTasks(ctx context.Context) (_ []model.Task, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.ClaimPendingTasks")
+	defer func() { tracing.End(span, err) }()
+
+	db := s.db.WithContext(ctx)
+	stale := time.Now().Add(-ClaimTimeout)
+	claimable := func(q *gorm.DB) *gorm.DB {
+		return q.Where("status = ? AND (claimed_at IS NULL OR claimed_at < ?)", model.StatusPending, stale)
+	}
+	var candidates []model.Task
+	if err := claimable(s.tasks(db)).Order("created_at, id").Find(&candidates).Error; err != nil {
 		return nil, err
 	}
+	now := time.Now()
+	tasks := candidates[:0]
+	for _, task := range candidates {
+		// UpdateColumn leaves updated_at alone: claiming doesn't change the
+		// task.
+		result := claimable(db.Model(&model.Task{})).Where("id = ?", task.ID).UpdateColumn("claimed_at", now)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			task.ClaimedAt = &now
+			tasks = append(tasks, task)
+		}
+	}
 	return tasks, nil
 }
+
+// replace validates the writable fields of task, copies them onto stored
+// and saves it, recording a status change in the task's history. ID and
+// timestamps are owned by the server and never taken from the input.
+func replace(tx *gorm.DB, stored *model.Task, task model.Task) error {
+	req := model.TaskRequest{Name: task.Name, Description: task.Description, Status: task.Status}
+	if err := validate(req); err != nil {
+		return err
+	}
+	from := stored.Status
+	stored.Name = task.Name
+	stored.Description = task.Description
+	stored.Status = task.Status
+	if stored.Status == "" {
+		stored.Status = model.StatusPending
+	}
+	if stored.Status != from {
+		stored.ClaimedAt = nil
+	}
+	if err := tx.Omit(clause.Associations).Save(stored).Error; err != nil {
+		return err
+	}
+	return recordStatusChange(tx, stored.ID, from, stored.Status)
+}
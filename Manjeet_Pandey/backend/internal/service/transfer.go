@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"task_manager/internal/events"
+	"task_manager/internal/tracing"
+	"task_manager/internal/validation"
+	"task_manager/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const importBatchSize = 500
+
+// ConflictMode decides what an import does with a task whose ID already
+// exists.
+type ConflictMode string
+
+const (
+	ConflictFail   ConflictMode = "fail"
+	ConflictSkip   ConflictMode = "skip"
+	ConflictUpdate ConflictMode = "update"
+)
+
+// ParseConflictMode validates a conflict mode, defaulting to fail.
+func ParseConflictMode(name string) (ConflictMode, error) {
+	switch m := ConflictMode(name); m {
+	case "":
+		return ConflictFail, nil
+	case ConflictFail, ConflictSkip, ConflictUpdate:
+		return m, nil
+	default:
+		return "", &ValidationError{Err: fmt.Errorf("unknown conflict mode %q: must be fail, skip or update", name)}
+	}
+}
+
+// ImportSummary counts the records seen by an import.
+type ImportSummary struct {
+	Read    int `json:"read"`
+	Written int `json:"written"`
+	Skipped int `json:"skipped"`
+}
+
+// ExportTasks calls fn for every task of the project in creation order.
+// Rows are read from a cursor so the full table is never held in memory;
+// the database's write-ahead log keeps the cursor from blocking writers.
+func (s *TaskService) ExportTasks(ctx context.Context, fn func(model.Task) error) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.ExportTasks")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := s.tasks(s.db.WithContext(ctx)).Model(&model.Task{}).Order("created_at, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var task model.Task
+		if err := s.db.ScanRows(rows, &task); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ImportTasks stores every task returned by next in the project until it
+// reports io.EOF. IDs and timestamps are kept as given; tasks of other
+// projects are never updated, and count as skipped.
+//
+// Records are validated as they are read and written in batches of
+// importBatchSize, each in its own transaction with the history entries and
+// webhook events of its tasks, so other writers aren't held up for the
+// whole import. A failure keeps the batches written before the failing
+// record, and its error says how many tasks they hold.
+func (s *TaskService) ImportTasks(ctx context.Context, next func() (model.Task, error), mode ConflictMode) (_ ImportSummary, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TaskService.ImportTasks")
+	defer func() { tracing.End(span, err) }()
+
+	if err := projectExists(s.db.WithContext(ctx), s.project); err != nil {
+		return ImportSummary{}, translateError(err, taskResource, nil)
+	}
+	var summary ImportSummary
+	batch := make([]model.Task, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		written, err := s.importBatch(ctx, batch, mode)
+		if err != nil {
+			return err
+		}
+		summary.Written += written
+		summary.Skipped += len(batch) - written
+		batch = batch[:0]
+		return nil
+	}
+	err = func() error {
+		for {
+			task, err := next()
+			if errors.Is(err, io.EOF) {
+				return flush()
+			}
+			if err != nil {
+				return &ValidationError{Err: fmt.Errorf("record %d: %w", summary.Read+1, err)}
+			}
+			summary.Read++
+			task.ProjectID = s.project
+			if task.Status == "" {
+				task.Status = model.StatusPending
+			}
+			req := model.TaskRequest{Name: task.Name, Description: task.Description, Status: task.Status}
+			if err := validation.Struct(req); err != nil {
+				return &ValidationError{Err: fmt.Errorf("record %d: %w", summary.Read, err)}
+			}
+			batch = append(batch, task)
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}()
+	if summary.Written > 0 {
+		if announceErr := s.Announce(ctx, events.Event{Type: events.TasksImported}); err == nil {
+			err = announceErr
+		}
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = &ConflictError{Err: errors.New("import contains a task that already exists: use skip or update to resolve conflicts")}
+	}
+	if err != nil {
+		return ImportSummary{}, partialImport(translateError(err, taskResource, nil), summary.Written)
+	}
+	return summary, nil
+}
+
+// importBatch writes a batch of an import in one transaction, together with
+// a creation entry or status change in the history of each task it writes
+// and a task.created or task.updated event, and returns how many tasks it
+// wrote.
+func (s *TaskService) importBatch(ctx context.Context, batch []model.Task, mode ConflictMode) (written int, err error) {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := make([]uuid.UUID, 0, len(batch))
+		for i := range batch {
+			if batch[i].ID == uuid.Nil {
+				batch[i].ID = uuid.New()
+			}
+			ids = append(ids, batch[i].ID)
+		}
+		var existing []model.Task
+		if err := tx.Select("id", "project_id", "status").Where("id IN ?", ids).Find(&existing).Error; err != nil {
+			return err
+		}
+		stored := make(map[uuid.UUID]model.Task, len(existing))
+		for _, task := range existing {
+			stored[task.ID] = task
+		}
+
+		var (
+			rows    []model.Task
+			types   []events.Type
+			history []model.Activity
+		)
+		for _, task := range batch {
+			previous, exists := stored[task.ID]
+			switch {
+			case !exists:
+				types = append(types, events.TaskCreated)
+				history = append(history, model.Activity{TaskID: task.ID, Type: model.ActivityTaskCreated, ToStatus: task.Status})
+			case mode == ConflictFail:
+				return gorm.ErrDuplicatedKey
+			case mode == ConflictUpdate && previous.ProjectID == s.project:
+				types = append(types, events.TaskUpdated)
+				if previous.Status != task.Status {
+					history = append(history, model.Activity{TaskID: task.ID, Type: model.ActivityStatusChanged, FromStatus: previous.Status, ToStatus: task.Status})
+				}
+			default:
+				continue
+			}
+			rows = append(rows, task)
+			// A later record with the same ID finds this one.
+			stored[task.ID] = task
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if written, err = insertBatch(tx, rows, mode, s.project); err != nil {
+			return err
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		for i := range rows {
+			if err := enqueueEvent(tx, events.Event{Type: types[i], Task: &rows[i]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return written, err
+}
+
+// partialImport notes in the error of an import how many tasks were written
+// before it failed, keeping the error's type.
+func partialImport(err error, written int) error {
+	if written == 0 {
+		return err
+	}
+	note := func(err error) error {
+		return fmt.Errorf("%w (%d tasks were imported before it)", err, written)
+	}
+	var invalid *ValidationError
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &invalid):
+		return &ValidationError{Err: note(invalid.Err)}
+	case errors.As(err, &conflict):
+		return &ConflictError{Err: note(conflict.Err)}
+	}
+	return err
+}
+
+func insertBatch(tx *gorm.DB, batch []model.Task, mode ConflictMode, projectID uuid.UUID) (int, error) {
+	switch mode {
+	case ConflictSkip:
+		tx = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true})
+	case ConflictUpdate:
+		tx = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "status", "created_at", "updated_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "tasks", Name: "project_id"}, Value: projectID}}},
+		})
+	}
+	// Labels in the input are ignored, like the other read-only fields.
+	result := tx.Omit(clause.Associations).Create(&batch)
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"task_manager/internal/events"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookResource = "webhook"
+	// AllEvents subscribes a webhook to every event type.
+	AllEvents = "*"
+)
+
+var webhookEventTypes = map[string]bool{
+	AllEvents:                     true,
+	string(events.TaskCreated):    true,
+	string(events.TaskUpdated):    true,
+	string(events.TaskDeleted):    true,
+	string(events.TaskProcessing): true,
+	string(events.TaskCompleted):  true,
+	string(events.TaskFailed):     true,
+	string(events.TasksImported):  true,
+}
+
+type WebhookService struct {
+	db *gorm.DB
+}
+
+func NewWebhookService(db *gorm.DB) WebhookService {
+	return WebhookService{db: db}
+}
+
+// CreateWebhook stores a subscription. A random secret is generated when
+// none is given.
+func (s *WebhookService) CreateWebhook(ctx context.Context, hook model.Webhook) (model.Webhook, error) {
+	if err := validateWebhook(hook); err != nil {
+		return model.Webhook{}, err
+	}
+	if hook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return model.Webhook{}, err
+		}
+		hook.Secret = hex.EncodeToString(secret)
+	}
+	hook.Active = true
+	if err := s.db.WithContext(ctx).Create(&hook).Error; err != nil {
+		return model.Webhook{}, translateError(err, webhookResource, hook.ID)
+	}
+	return hook, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	var hooks []model.Webhook
+	if err := s.db.WithContext(ctx).Order("created_at").Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	var hook model.Webhook
+	if err := s.db.WithContext(ctx).First(&hook, id).Error; err != nil {
+		return model.Webhook{}, translateError(err, webhookResource, id)
+	}
+	return hook, nil
+}
+
+// DeleteWebhook removes a subscription together with its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &NotFoundError{Resource: webhookResource, ID: id}
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error
+	})
+}
+
+// ActiveWebhooks returns the webhooks that receive events.
+func (s *WebhookService) ActiveWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	var active []model.Webhook
+	if err := s.db.WithContext(ctx).Where("active = ?", true).Order("created_at").Find(&active).Error; err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// Subscribed reports whether hook wants events of this type.
+func Subscribed(hook model.Webhook, eventType events.Type) bool {
+	for _, t := range hook.Events {
+		if t == AllEvents || t == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueEvent writes event to the webhook outbox. Called with the
+// transaction making the change the event describes, the event is delivered
+// if and only if the change is committed.
+func enqueueEvent(tx *gorm.DB, event events.Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Task != nil && event.TaskID == uuid.Nil {
+		event.TaskID = event.Task.ID
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&model.WebhookEvent{Type: string(event.Type), Payload: payload}).Error
+}
+
+// claimable selects the outbox events owner may claim: those nobody has
+// claimed, those whose claim has expired and those owner already holds.
+const claimable = "claimed_by = ? OR locked_until IS NULL OR locked_until < ?"
+
+// ClaimEvents claims up to limit events of the outbox after the one with ID
+// after for owner until lease has passed, and returns them oldest first.
+// Events held by another owner are skipped until their claim expires. The
+// events owner already holds are claimed again, which is how a dispatcher
+// keeps the events it is still delivering. Their IDs are those of the
+// outbox rows.
+func (s *WebhookService) ClaimEvents(ctx context.Context, owner string, after uint64, limit int, lease time.Duration) ([]events.Event, error) {
+	now := time.Now().UTC()
+	var rows []model.WebhookEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint64
+		err := tx.Model(&model.WebhookEvent{}).Where("id > ?", after).Where(claimable, owner, now).
+			Order("id").Limit(limit).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		// Conditional, so that an event claimed by another process in the
+		// meantime stays with it.
+		err = tx.Model(&model.WebhookEvent{}).Where("id IN ?", ids).Where(claimable, owner, now).
+			Updates(map[string]any{"claimed_by": owner, "locked_until": now.Add(lease)}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id IN ? AND claimed_by = ?", ids, owner).Order("id").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]events.Event, 0, len(rows))
+	for _, row := range rows {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			return nil, fmt.Errorf("outbox event %d: %w", row.ID, err)
+		}
+		event.ID = row.ID
+		claimed = append(claimed, event)
+	}
+	return claimed, nil
+}
+
+// ReleaseEvents gives up the claims of owner, so that the events it didn't
+// deliver are picked up without waiting for the claims to expire.
+func (s *WebhookService) ReleaseEvents(ctx context.Context, owner string) error {
+	return s.db.WithContext(ctx).Model(&model.WebhookEvent{}).Where("claimed_by = ?", owner).
+		Updates(map[string]any{"claimed_by": "", "locked_until": nil}).Error
+}
+
+// EventDelivered removes an event from the outbox once every webhook has
+// been dealt with.
+func (s *WebhookService) EventDelivered(ctx context.Context, id uint64) error {
+	return s.db.WithContext(ctx).Delete(&model.WebhookEvent{}, id).Error
+}
+
+func (s *WebhookService) RecordDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	if err := s.db.WithContext(ctx).Create(&delivery).Error; err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id uuid.UUID) ([]model.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	var deliveries []model.WebhookDelivery
+	if err := s.db.WithContext(ctx).Where("webhook_id = ?", id).Order("created_at desc").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func validateWebhook(hook model.Webhook) error {
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Err: fmt.Errorf("invalid url %q: must be an absolute http or https URL", hook.URL)}
+	}
+	if len(hook.Events) == 0 {
+		return &ValidationError{Err: errors.New("at least one event type is required")}
+	}
+	for _, t := range hook.Events {
+		if !webhookEventTypes[t] {
+			return &ValidationError{Err: fmt.Errorf("unknown event type %q", t)}
+		}
+	}
+	return nil
+}
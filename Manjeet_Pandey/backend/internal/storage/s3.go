@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// uploadPartSize is the part size of uploads, which the client buffers in
+// memory one part at a time. Without it, an upload of unknown size is cut
+// into parts big enough for the largest object S3 allows, 512 MiB each.
+const uploadPartSize = 5 << 20
+
+// S3Options point the S3 backend at a bucket on AWS S3 or a compatible
+// server such as MinIO.
+type S3Options struct {
+	// Endpoint is the host and port, e.g. s3.amazonaws.com or
+	// localhost:9000.
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// UseSSL connects over HTTPS.
+	UseSSL bool
+}
+
+// S3 keeps objects in a bucket.
+type S3 struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 connects to the bucket, creating it when it doesn't exist yet.
+func NewS3(ctx context.Context, opts S3Options) (*S3, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, errors.New("the s3 backend needs an endpoint and a bucket")
+	}
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check bucket %s: %w", opts.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, fmt.Errorf("create bucket %s: %w", opts.Bucket, err)
+		}
+	}
+	return &S3{client: client, bucket: opts.Bucket}, nil
+}
+
+// Put streams r to the bucket as a multipart upload, since its size isn't
+// known in advance. An upload that fails is aborted.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, -1, minio.PutObjectOptions{ContentType: contentType, PartSize: uploadPartSize})
+	return err
+}
+
+// Open checks the object exists before returning it, so a missing object
+// is reported here rather than on the first read.
+func (s *S3) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,61 @@
+// Package storage keeps the contents of task attachments. Metadata lives in
+// the database; a Storage only maps keys to bytes.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned by Open for keys that hold nothing.
+var ErrNotFound = errors.New("object not found")
+
+// Storage is where attachment contents are written. Keys are slash
+// separated paths such as tasks/<task id>/<attachment id>.
+type Storage interface {
+	// Put stores everything read from r under key, replacing what was
+	// there. Nothing is left behind when it fails.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns the contents of key. The reader seeks, so ranges can be
+	// served from it without reading the whole object.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes key. Deleting a key that holds nothing succeeds.
+	Delete(ctx context.Context, key string) error
+}
+
+// Options selects and configures a backend.
+type Options struct {
+	// Backend is local or s3.
+	Backend string
+	// Dir is the directory the local backend writes to.
+	Dir string
+	S3  S3Options
+}
+
+// New opens the backend named in opts.
+func New(ctx context.Context, opts Options) (Storage, error) {
+	switch opts.Backend {
+	case "local":
+		return NewLocal(opts.Dir)
+	case "s3":
+		return NewS3(ctx, opts.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, want local or s3", opts.Backend)
+	}
+}
+
+// validKey rejects keys that could escape the storage root.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid key %q", key)
+		}
+	}
+	return nil
+}
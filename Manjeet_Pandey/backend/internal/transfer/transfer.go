@@ -0,0 +1,258 @@
+// Package transfer encodes and decodes streams of tasks for import and
+// export. Every format carries all model.Task fields, including IDs and
+// timestamps, so a dump can be loaded into another database unchanged.
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var csvHeader = []string{"id", "name", "description", "status", "created_at", "updated_at"}
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatJSON, FormatCSV, FormatNDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: must be json, csv or ndjson", name)
+	}
+}
+
+// FormatFromContentType maps a media type onto a format, defaulting to JSON.
+func FormatFromContentType(contentType string) Format {
+	switch contentType {
+	case "text/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/ndjson":
+		return FormatNDJSON
+	default:
+		return FormatJSON
+	}
+}
+
+// ContentType is the media type written for a format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// Encoder writes tasks one at a time. Close must be called to finish the
+// document.
+type Encoder interface {
+	Encode(task model.Task) error
+	Close() error
+}
+
+// Decoder reads tasks one at a time and returns io.EOF after the last one.
+type Decoder interface {
+	Decode() (model.Task, error)
+}
+
+func NewEncoder(format Format, w io.Writer) Encoder {
+	switch format {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}
+	case FormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}
+	default:
+		return &jsonEncoder{w: w}
+	}
+}
+
+func NewDecoder(format Format, r io.Reader) Decoder {
+	switch format {
+	case FormatCSV:
+		return &csvDecoder{r: csv.NewReader(r)}
+	case FormatNDJSON:
+		return &ndjsonDecoder{dec: json.NewDecoder(r)}
+	default:
+		return &jsonDecoder{dec: json.NewDecoder(r)}
+	}
+}
+
+// jsonEncoder streams a JSON array without holding it in memory.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(task model.Task) error {
+	sep := ","
+	if e.count == 0 {
+		sep = "["
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, sep+"\n"); err != nil {
+		return err
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(task model.Task) error {
+	return e.enc.Encode(task)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(task model.Task) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	return e.w.Write([]string{
+		task.ID.String(),
+		task.Name,
+		task.Description,
+		string(task.Status),
+		task.CreatedAt.Format(time.RFC3339Nano),
+		task.UpdatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonDecoder reads the elements of a JSON array one by one.
+type jsonDecoder struct {
+	dec     *json.Decoder
+	started bool
+}
+
+func (d *jsonDecoder) Decode() (model.Task, error) {
+	if !d.started {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return model.Task{}, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return model.Task{}, errors.New("expected a JSON array of tasks")
+		}
+		d.started = true
+	}
+	if !d.dec.More() {
+		return model.Task{}, io.EOF
+	}
+	var task model.Task
+	err := d.dec.Decode(&task)
+	return task, err
+}
+
+type ndjsonDecoder struct {
+	dec *json.Decoder
+}
+
+func (d *ndjsonDecoder) Decode() (model.Task, error) {
+	var task model.Task
+	err := d.dec.Decode(&task)
+	return task, err
+}
+
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func (d *csvDecoder) Decode() (model.Task, error) {
+	if d.columns == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			return model.Task{}, err
+		}
+		d.columns = make(map[string]int, len(header))
+		for i, name := range header {
+			d.columns[strings.TrimSpace(name)] = i
+		}
+		for _, name := range csvHeader {
+			if _, ok := d.columns[name]; !ok {
+				return model.Task{}, fmt.Errorf("csv header is missing column %q", name)
+			}
+		}
+	}
+	record, err := d.r.Read()
+	if err != nil {
+		return model.Task{}, err
+	}
+	field := func(name string) string {
+		return record[d.columns[name]]
+	}
+
+	task := model.Task{
+		Name:        field("name"),
+		Description: field("description"),
+		Status:      model.TaskStatus(field("status")),
+	}
+	if v := field("id"); v != "" {
+		if task.ID, err = uuid.Parse(v); err != nil {
+			return model.Task{}, fmt.Errorf("invalid id %q: %w", v, err)
+		}
+	}
+	if v := field("created_at"); v != "" {
+		if task.CreatedAt, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return model.Task{}, fmt.Errorf("invalid created_at %q: %w", v, err)
+		}
+	}
+	if v := field("updated_at"); v != "" {
+		if task.UpdatedAt, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return model.Task{}, fmt.Errorf("invalid updated_at %q: %w", v, err)
+		}
+	}
+	return task, nil
+}
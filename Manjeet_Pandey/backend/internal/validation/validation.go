@@ -0,0 +1,94 @@
+// Package validation checks request structs against their `validate` tags
+// and describes each violation in terms of the JSON field a client sent.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+// FieldError describes why one field of a request was rejected.
+type FieldError struct {
+	// Field is the JSON name of the field, dotted for nested fields.
+	Field string `json:"field" example:"name"`
+	// Rule is the validation rule that failed, e.g. required or max.
+	Rule    string `json:"rule" example:"required"`
+	Message string `json:"message" example:"name is required"`
+}
+
+// Errors lists every invalid field of a request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	if err := v.RegisterValidation("notblank", validators.NotBlank); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Struct validates s and returns Errors when any field is invalid.
+func Struct(s any) error {
+	err := validate.Struct(s)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	result := make(Errors, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		field := fe.Namespace()
+		// Drop the struct name the namespace starts with.
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest
+		}
+		result[i] = FieldError{Field: field, Rule: fe.Tag(), Message: message(field, fe)}
+	}
+	return result
+}
+
+func message(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "notblank":
+		return field + " is required"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "hexcolor":
+		return field + " must be a hex colour such as #1d76db"
+	case "excludesall":
+		return fmt.Sprintf("%s must not contain %q", field, strings.ReplaceAll(fe.Param(), "0x2C", ","))
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s is invalid (%s)", field, fe.Tag())
+	}
+}
@@ -0,0 +1,57 @@
+// Package version reports how the binary was built. Version, Commit and
+// BuildTime are set at link time, e.g.
+//
+//	go build -ldflags "-X task_manager/internal/version.Version=v1.4.0 \
+//	  -X task_manager/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X task_manager/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+	"task_manager/internal/database"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version   string `json:"version" example:"v1.4.0"`
+	Commit    string `json:"commit" example:"3f9c2ab"`
+	BuildTime string `json:"build_time" example:"2025-01-31T12:00:00Z"`
+	GoVersion string `json:"go_version" example:"go1.25.0"`
+	// SchemaVersion is the database schema this build migrates to.
+	SchemaVersion int `json:"schema_version" example:"1"`
+}
+
+// Get returns the build information. When the linker flags were not set,
+// the commit and time recorded by the Go toolchain are used instead.
+func Get() Info {
+	info := Info{
+		Version:       Version,
+		Commit:        Commit,
+		BuildTime:     BuildTime,
+		GoVersion:     runtime.Version(),
+		SchemaVersion: database.SchemaVersion,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,164 @@
+// Package web serves the React frontend. `npm run build` in frontend/ writes
+// it to static/dist, which is embedded in the binary.
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed all:static
+var static embed.FS
+
+// immutablePrefix holds the assets Vite names after their content hash, so
+// they never change under the same URL.
+const immutablePrefix = "assets/"
+
+// encodings are the precompressed variants written by
+// frontend/scripts/compress.mjs, in order of preference.
+var encodings = []struct{ name, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+type file struct {
+	data []byte
+	etag string
+}
+
+// Frontend serves a built frontend from memory.
+type Frontend struct {
+	files map[string]file
+}
+
+// Embedded returns the frontend embedded in the binary, or false when it
+// wasn't built before the binary was.
+func Embedded() (*Frontend, bool) {
+	dist, err := fs.Sub(static, "static/dist")
+	if err != nil {
+		return nil, false
+	}
+	return New(dist)
+}
+
+// New loads the frontend in dist, or returns false when dist has no
+// index.html.
+func New(dist fs.FS) (*Frontend, bool) {
+	f := &Frontend{files: map[string]file{}}
+	err := fs.WalkDir(dist, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(dist, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		f.files[name] = file{data: data, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+		return nil
+	})
+	if err != nil {
+		return nil, false
+	}
+	if _, ok := f.files["index.html"]; !ok {
+		return nil, false
+	}
+	return f, true
+}
+
+// Handler serves the frontend for GET and HEAD requests that no route
+// matched. Paths that aren't files get index.html when the client accepts
+// HTML, so client-side routes survive a reload; everything else is passed
+// to next.
+func (f *Frontend) Handler(next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			next(c)
+			return
+		}
+		name := strings.TrimPrefix(path.Clean("/"+c.Request.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
+		}
+		if _, ok := f.files[name]; !ok {
+			if path.Ext(name) != "" || !acceptsHTML(c.Request) {
+				next(c)
+				return
+			}
+			name = "index.html"
+		}
+		f.serve(c, name)
+	}
+}
+
+func (f *Frontend) serve(c *gin.Context, name string) {
+	h := c.Writer.Header()
+	h.Add("Vary", "Accept-Encoding")
+	if strings.HasPrefix(name, immutablePrefix) {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		// index.html names the current assets, so it is revalidated on
+		// every load.
+		h.Set("Cache-Control", "no-cache")
+	}
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	h.Set("Content-Type", ctype)
+
+	served := f.files[name]
+	for _, enc := range encodings {
+		if compressed, ok := f.files[name+enc.ext]; ok && acceptsEncoding(c.Request, enc.name) {
+			served = compressed
+			h.Set("Content-Encoding", enc.name)
+			break
+		}
+	}
+	h.Set("ETag", served.etag)
+	http.ServeContent(c.Writer, c.Request, name, time.Time{}, bytes.NewReader(served.data))
+}
+
+// acceptsHTML reports whether the request looks like a browser navigation.
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// acceptsEncoding reports whether Accept-Encoding lists encoding without
+// ruling it out with q=0.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+			continue
+		}
+		q := strings.ReplaceAll(params, " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+// DevProxy forwards requests that no route matched to the Vite dev server
+// at target, including its hot reload WebSocket.
+func DevProxy(target *url.URL) gin.HandlerFunc {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return func(c *gin.Context) {
+		// The server's timeouts would cut off the hot reload connection.
+		rc := http.NewResponseController(c.Writer)
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
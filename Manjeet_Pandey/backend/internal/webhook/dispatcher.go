@@ -0,0 +1,367 @@
+// Package webhook delivers task events to subscribed HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"task_manager/internal/events"
+	"task_manager/internal/logging"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Options tunes delivery. Zero values fall back to the defaults.
+type Options struct {
+	Workers     int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	Timeout     time.Duration
+	// PollInterval is how often the outbox is read when no event on the
+	// bus says it has changed.
+	PollInterval time.Duration
+	// ClaimTimeout is how long other processes leave the events this one
+	// has claimed alone. Claims are renewed on every poll, so it only needs
+	// to cover a few PollIntervals; a crashed process's events are
+	// delivered by another once it has passed.
+	ClaimTimeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 5
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 5 * time.Second
+	}
+	if o.ClaimTimeout <= 0 {
+		o.ClaimTimeout = time.Minute
+	}
+	return o
+}
+
+// outboxBatch is how many outbox events are read at once.
+const outboxBatch = 100
+
+type job struct {
+	hook  model.Webhook
+	event events.Event
+}
+
+// Dispatcher delivers the events of the webhook outbox, which services
+// write in the same transaction as the changes they describe, to every
+// subscribed webhook. Deliveries run in the background and are retried
+// with exponential backoff; every attempt is written to the delivery log.
+// An event leaves the outbox once each of its deliveries has succeeded or
+// been given up on, so events still queued at shutdown are delivered on
+// the next start, at least once. Events are claimed before they are
+// delivered, so dispatchers in several processes sharing the database
+// don't deliver the same event.
+//
+// The bus only wakes the dispatcher up: an event the bus drops is still
+// read from the outbox.
+type Dispatcher struct {
+	webhooks service.WebhookService
+	bus      *events.Bus
+	// owner names this dispatcher in the claims of the outbox.
+	owner  string
+	client *http.Client
+	opts   Options
+	jobs   chan job
+	wg     sync.WaitGroup
+	sub    *events.Subscription
+	// quit stops reading the outbox; stop abandons retries too.
+	quit chan struct{}
+	stop chan struct{}
+
+	mu sync.Mutex
+	// outstanding counts the deliveries of each event read from the outbox
+	// that haven't finished.
+	outstanding map[uint64]int
+}
+
+func NewDispatcher(webhooks service.WebhookService, bus *events.Bus, opts Options) *Dispatcher {
+	opts = opts.withDefaults()
+	return &Dispatcher{
+		webhooks:    webhooks,
+		bus:         bus,
+		owner:       uuid.NewString(),
+		client:      &http.Client{Timeout: opts.Timeout},
+		opts:        opts,
+		jobs:        make(chan job, 256),
+		quit:        make(chan struct{}),
+		stop:        make(chan struct{}),
+		outstanding: map[uint64]int{},
+	}
+}
+
+// Start subscribes to the bus and starts the delivery workers.
+func (d *Dispatcher) Start() {
+	d.sub = d.bus.Subscribe(events.Filter{})
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.deliverLoop()
+	}
+	d.wg.Add(1)
+	go d.fanOut()
+}
+
+// Shutdown stops reading the outbox and waits for the deliveries already
+// read to finish or for ctx to expire. Retries still waiting on backoff
+// are abandoned; their events stay in the outbox and are released to the
+// other processes.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.sub.Close()
+	close(d.quit)
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		close(d.stop)
+		err = ctx.Err()
+	}
+	if releaseErr := d.webhooks.ReleaseEvents(context.Background(), d.owner); releaseErr != nil {
+		log.Err(releaseErr).Msg("Cannot release the claimed webhook events")
+	}
+	return err
+}
+
+// fanOut reads the outbox whenever an event is published, and every
+// PollInterval for events written by other processes or left by an earlier
+// run, and queues a job for each webhook subscribed to each event it claims.
+func (d *Dispatcher) fanOut() {
+	defer d.wg.Done()
+	defer close(d.jobs)
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	// after is the last event read. Polls start over from the beginning,
+	// renewing the claims of the events still being delivered and skipping
+	// them.
+	var after uint64
+	for {
+		var ok bool
+		if after, ok = d.dispatch(after); !ok {
+			return
+		}
+		select {
+		case <-d.quit:
+			return
+		case _, open := <-d.sub.C:
+			// One read covers every event published meanwhile.
+			for open {
+				select {
+				case _, open = <-d.sub.C:
+				default:
+					open = false
+				}
+			}
+		case <-ticker.C:
+			after = 0
+		}
+	}
+}
+
+// dispatch claims the outbox events after the given one, queues their jobs
+// and returns the last event read. It reports false when the dispatcher is
+// shutting down.
+func (d *Dispatcher) dispatch(after uint64) (uint64, bool) {
+	ctx := context.Background()
+	for {
+		pending, err := d.webhooks.ClaimEvents(ctx, d.owner, after, outboxBatch, d.opts.ClaimTimeout)
+		if err != nil {
+			log.Err(err).Msg("Cannot read the webhook outbox")
+			return after, true
+		}
+		if len(pending) == 0 {
+			return after, true
+		}
+		// Read once per batch rather than once per event.
+		hooks, err := d.webhooks.ActiveWebhooks(ctx)
+		if err != nil {
+			log.Err(err).Msg("Cannot load webhooks")
+			return after, true
+		}
+		for _, event := range pending {
+			var subscribed []model.Webhook
+			for _, hook := range hooks {
+				if service.Subscribed(hook, event.Type) {
+					subscribed = append(subscribed, hook)
+				}
+			}
+			if !d.track(event.ID, len(subscribed)) {
+				after = event.ID
+				continue
+			}
+			if len(subscribed) == 0 {
+				d.finish(event.ID)
+			}
+			for _, hook := range subscribed {
+				select {
+				case d.jobs <- job{hook: hook, event: event}:
+				case <-d.quit:
+					return after, false
+				}
+			}
+			after = event.ID
+		}
+	}
+}
+
+// track records that an event read from the outbox has deliveries
+// outstanding, and reports false if it already had.
+func (d *Dispatcher) track(eventID uint64, deliveries int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.outstanding[eventID]; ok {
+		return false
+	}
+	d.outstanding[eventID] = deliveries
+	return true
+}
+
+// finish records that a delivery of an event is over, and removes the event
+// from the outbox after the last one.
+func (d *Dispatcher) finish(eventID uint64) {
+	d.mu.Lock()
+	d.outstanding[eventID]--
+	last := d.outstanding[eventID] <= 0
+	d.mu.Unlock()
+	if !last {
+		return
+	}
+	if err := d.webhooks.EventDelivered(context.Background(), eventID); err != nil {
+		// Left in the outbox, the event is delivered again by a later poll.
+		log.Err(err).Uint64("event_id", eventID).Msg("Cannot remove the event from the webhook outbox")
+	}
+	d.mu.Lock()
+	delete(d.outstanding, eventID)
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) deliverLoop() {
+	defer d.wg.Done()
+	for j := range d.jobs {
+		if d.deliverWithRetry(j.hook, j.event) {
+			d.finish(j.event.ID)
+		}
+	}
+}
+
+// deliverWithRetry reports false if the delivery was abandoned at shutdown
+// rather than made or given up on.
+func (d *Dispatcher) deliverWithRetry(hook model.Webhook, event events.Event) bool {
+	backoff := d.opts.BaseBackoff
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		delivery := d.Deliver(context.Background(), hook, event, attempt)
+		if delivery.Success {
+			return true
+		}
+		if attempt == d.opts.MaxAttempts {
+			log.Error().Str("webhook_id", hook.ID.String()).Str("event", string(event.Type)).Msg("Giving up on webhook delivery")
+			return true
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-d.stop:
+			return false
+		}
+	}
+	return true
+}
+
+// Deliver makes a single signed attempt to post event to hook and records
+// it in the delivery log.
+func (d *Dispatcher) Deliver(ctx context.Context, hook model.Webhook, event events.Event, attempt int) model.WebhookDelivery {
+	delivery := model.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   event.ID,
+		EventType: string(event.Type),
+		Attempt:   attempt,
+	}
+	start := time.Now()
+	status, err := d.post(ctx, hook, event, delivery)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.StatusCode = status
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	recorded, recErr := d.webhooks.RecordDelivery(ctx, delivery)
+	if recErr != nil {
+		logging.Ctx(ctx).Err(recErr).Msg("Cannot record webhook delivery")
+		return delivery
+	}
+	return recorded
+}
+
+func (d *Dispatcher) post(ctx context.Context, hook model.Webhook, event events.Event, delivery model.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, fmt.Sprintf("%d-%d", event.ID, delivery.Attempt))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign computes the signature header value for a payload. Receivers verify
+// it by computing HMAC-SHA256 over "<timestamp>.<body>" with the shared
+// secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,354 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"task_manager/internal/database"
+	"task_manager/internal/events"
+	"task_manager/internal/logging"
+	"task_manager/internal/service"
+	"task_manager/internal/webhook"
+	"task_manager/model"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logging.Setup("disabled", "console")
+	os.Exit(m.Run())
+}
+
+// request is a delivery as the receiver saw it.
+type request struct {
+	header http.Header
+	body   []byte
+	at     time.Time
+}
+
+// receiver is a webhook endpoint that records what it receives and answers
+// with the status status returns for the nth request, counting from 1.
+type receiver struct {
+	*httptest.Server
+	status func(n int) int
+
+	mu       sync.Mutex
+	requests []request
+}
+
+func newReceiver(t *testing.T, status func(n int) int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, request{header: req.Header.Clone(), body: body, at: time.Now()})
+		n := len(r.requests)
+		r.mu.Unlock()
+		w.WriteHeader(r.status(n))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func ok(int) int { return http.StatusOK }
+
+func (r *receiver) received() []request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]request(nil), r.requests...)
+}
+
+// env is a scratch database with the services the dispatcher works with.
+type env struct {
+	db       *gorm.DB
+	bus      *events.Bus
+	tasks    service.TaskService
+	webhooks service.WebhookService
+}
+
+func newEnv(t *testing.T) env {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "tasks.db") + database.DSNOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+	bus := events.NewBus()
+	return env{db: db, bus: bus, tasks: service.NewTaskService(db, bus), webhooks: service.NewWebhookService(db)}
+}
+
+// start runs a dispatcher until the test ends.
+func (e env) start(t *testing.T, opts webhook.Options) *webhook.Dispatcher {
+	d := webhook.NewDispatcher(e.webhooks, e.bus, opts)
+	d.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		d.Shutdown(ctx)
+	})
+	return d
+}
+
+func (e env) hook(t *testing.T, url string, types ...string) model.Webhook {
+	t.Helper()
+	hook, err := e.webhooks.CreateWebhook(context.Background(), model.Webhook{URL: url, Events: types, Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hook
+}
+
+func (e env) createTasks(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := e.tasks.CreateTask(context.Background(), model.Task{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// outbox counts the events waiting for delivery.
+func (e env) outbox(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	if err := e.db.Model(&model.WebhookEvent{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// eventually fails the test unless cond holds within a few seconds.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDeliveryIsSigned(t *testing.T) {
+	e := newEnv(t)
+	r := newReceiver(t, ok)
+	hook := e.hook(t, r.URL, string(events.TaskCreated))
+	e.start(t, webhook.Options{})
+
+	e.createTasks(t, "signed")
+	eventually(t, "the delivery", func() bool { return len(r.received()) == 1 })
+	req := r.received()[0]
+
+	timestamp := req.header.Get(webhook.TimestampHeader)
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(req.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); req.header.Get(webhook.SignatureHeader) != want {
+		t.Errorf("signature is %q, want %q", req.header.Get(webhook.SignatureHeader), want)
+	}
+	if got := webhook.Sign("wrong", timestamp, req.body); got == req.header.Get(webhook.SignatureHeader) {
+		t.Error("signature doesn't depend on the secret")
+	}
+	if req.header.Get(webhook.EventHeader) != string(events.TaskCreated) {
+		t.Errorf("event header is %q", req.header.Get(webhook.EventHeader))
+	}
+	var event events.Event
+	if err := json.Unmarshal(req.body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.TaskCreated || event.Task == nil || event.Task.Name != "signed" || event.TaskID != event.Task.ID {
+		t.Errorf("delivered %s", req.body)
+	}
+	if want := fmt.Sprintf("%d-1", event.ID); req.header.Get(webhook.DeliveryHeader) != want {
+		t.Errorf("delivery header is %q, want %q", req.header.Get(webhook.DeliveryHeader), want)
+	}
+
+	eventually(t, "the delivery log", func() bool {
+		deliveries, _ := e.webhooks.ListDeliveries(context.Background(), hook.ID)
+		return len(deliveries) == 1 && deliveries[0].Success && deliveries[0].StatusCode == http.StatusOK && deliveries[0].EventID == event.ID
+	})
+	eventually(t, "the outbox to empty", func() bool { return e.outbox(t) == 0 })
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	e := newEnv(t)
+	// Two failures, then success.
+	r := newReceiver(t, func(n int) int {
+		if n <= 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	hook := e.hook(t, r.URL, service.AllEvents)
+	const backoff = 50 * time.Millisecond
+	e.start(t, webhook.Options{MaxAttempts: 5, BaseBackoff: backoff})
+
+	e.createTasks(t, "flaky")
+	eventually(t, "three attempts", func() bool { return len(r.received()) == 3 })
+	reqs := r.received()
+	if gap := reqs[1].at.Sub(reqs[0].at); gap < backoff {
+		t.Errorf("second attempt came %v after the first, want at least %v", gap, backoff)
+	}
+	if gap := reqs[2].at.Sub(reqs[1].at); gap < 2*backoff {
+		t.Errorf("third attempt came %v after the second, want at least %v", gap, 2*backoff)
+	}
+	for i, req := range reqs {
+		if !hmac.Equal([]byte(req.header.Get(webhook.SignatureHeader)), []byte(webhook.Sign(hook.Secret, req.header.Get(webhook.TimestampHeader), req.body))) {
+			t.Errorf("attempt %d has a bad signature", i+1)
+		}
+	}
+
+	var deliveries []model.WebhookDelivery
+	eventually(t, "the delivery log", func() bool {
+		deliveries, _ = e.webhooks.ListDeliveries(context.Background(), hook.ID)
+		return len(deliveries) == 3
+	})
+	// Newest first.
+	for i, d := range deliveries {
+		attempt := 3 - i
+		if d.Attempt != attempt || d.Success != (attempt == 3) {
+			t.Errorf("delivery %d is attempt %d, success %v", i, d.Attempt, d.Success)
+		}
+		if attempt < 3 && (d.StatusCode != http.StatusServiceUnavailable || d.Error == "") {
+			t.Errorf("failed attempt %d recorded status %d and error %q", attempt, d.StatusCode, d.Error)
+		}
+	}
+	eventually(t, "the outbox to empty", func() bool { return e.outbox(t) == 0 })
+}
+
+func TestGiveUp(t *testing.T) {
+	e := newEnv(t)
+	r := newReceiver(t, func(int) int { return http.StatusInternalServerError })
+	hook := e.hook(t, r.URL, string(events.TaskCreated))
+	e.start(t, webhook.Options{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+	e.createTasks(t, "doomed")
+	eventually(t, "the outbox to empty", func() bool { return e.outbox(t) == 0 })
+	deliveries, err := e.webhooks.ListDeliveries(context.Background(), hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Success || deliveries[1].Success {
+		t.Errorf("gave up after %+v, want two failed attempts", deliveries)
+	}
+}
+
+func TestBulkCreateLosesNoEvents(t *testing.T) {
+	e := newEnv(t)
+	r := newReceiver(t, ok)
+	e.hook(t, r.URL, string(events.TaskCreated))
+	e.start(t, webhook.Options{})
+
+	// Far more events at once than the bus buffers for a subscriber.
+	const count = 300
+	req := service.BulkRequest{}
+	for i := 0; i < count; i++ {
+		req.Operations = append(req.Operations, service.BulkOperation{Op: service.BulkCreate, Task: &model.Task{Name: fmt.Sprint("task ", i)}})
+	}
+	if _, err := e.tasks.Bulk(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "every delivery", func() bool { return len(r.received()) >= count })
+	seen := map[string]bool{}
+	for _, req := range r.received() {
+		var event events.Event
+		if err := json.Unmarshal(req.body, &event); err != nil {
+			t.Fatal(err)
+		}
+		seen[event.TaskID.String()] = true
+	}
+	if len(seen) != count {
+		t.Errorf("%d tasks were announced, want %d", len(seen), count)
+	}
+}
+
+func TestOutboxOutlivesRestart(t *testing.T) {
+	e := newEnv(t)
+	down := true
+	var mu sync.Mutex
+	r := newReceiver(t, func(int) int {
+		mu.Lock()
+		defer mu.Unlock()
+		if down {
+			return http.StatusBadGateway
+		}
+		return http.StatusOK
+	})
+	e.hook(t, r.URL, string(events.TaskCreated))
+
+	// Written while no dispatcher runs.
+	e.createTasks(t, "early")
+
+	// Shut down while the delivery waits to be retried.
+	d := webhook.NewDispatcher(e.webhooks, e.bus, webhook.Options{MaxAttempts: 5, BaseBackoff: time.Hour})
+	d.Start()
+	eventually(t, "the first attempt", func() bool { return len(r.received()) == 1 })
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(ctx); err == nil {
+		t.Fatal("shutdown didn't abandon the retry")
+	}
+	if n := e.outbox(t); n != 1 {
+		t.Fatalf("%d events left in the outbox, want 1", n)
+	}
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	e.start(t, webhook.Options{})
+	eventually(t, "the redelivery", func() bool { return len(r.received()) == 2 })
+	eventually(t, "the outbox to empty", func() bool { return e.outbox(t) == 0 })
+}
+
+func TestDispatchersShareTheOutbox(t *testing.T) {
+	e := newEnv(t)
+	r := newReceiver(t, ok)
+	e.hook(t, r.URL, string(events.TaskCreated))
+	// As if two processes shared the database.
+	e.start(t, webhook.Options{PollInterval: 10 * time.Millisecond})
+	e.start(t, webhook.Options{PollInterval: 10 * time.Millisecond})
+
+	const count = 50
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprint("task ", i)
+	}
+	e.createTasks(t, names...)
+	eventually(t, "the outbox to empty", func() bool { return e.outbox(t) == 0 })
+	// Leave time for a second delivery of the last events to arrive.
+	time.Sleep(50 * time.Millisecond)
+	if n := len(r.received()); n != count {
+		t.Errorf("%d deliveries of %d events", n, count)
+	}
+}
+
+func TestClaimedEventsWaitForTheClaimToExpire(t *testing.T) {
+	e := newEnv(t)
+	r := newReceiver(t, ok)
+	e.hook(t, r.URL, string(events.TaskCreated))
+	e.createTasks(t, "claimed")
+
+	// Claimed by a process that went away without delivering it.
+	const lease = 200 * time.Millisecond
+	claimed, err := e.webhooks.ClaimEvents(context.Background(), "crashed", 0, 10, lease)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("claimed %v, %v", claimed, err)
+	}
+	started := time.Now()
+	e.start(t, webhook.Options{PollInterval: 10 * time.Millisecond})
+	eventually(t, "the delivery", func() bool { return len(r.received()) == 1 })
+	if waited := r.received()[0].at.Sub(started); waited < lease/2 {
+		t.Errorf("delivered %v after the start, while another process held the event", waited)
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ActivityType string
+
+const (
+	ActivityTaskCreated   ActivityType = "task_created"
+	ActivityStatusChanged ActivityType = "status_changed"
+	ActivityComment       ActivityType = "comment"
+)
+
+// Activity is one entry of a task's history. Entries are written in the
+// same transaction as the change they describe.
+type Activity struct {
+	// ID increases with every entry, so it orders entries written within
+	// the same clock tick.
+	ID     uint64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID uuid.UUID    `gorm:"type:uuid;index;not null" json:"task_id"`
+	Type   ActivityType `gorm:"not null" json:"type" enums:"task_created,status_changed,comment"`
+	// FromStatus and ToStatus are set for status changes; ToStatus is also
+	// the initial status of a created task.
+	FromStatus TaskStatus `json:"from_status,omitempty"`
+	ToStatus   TaskStatus `json:"to_status,omitempty"`
+	// CommentID is set for comments. Comment holds the comment as it is
+	// now, and is nil once the comment has been deleted.
+	CommentID *uuid.UUID `gorm:"type:uuid" json:"comment_id,omitempty"`
+	Comment   *Comment   `gorm:"-" json:"comment,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxAttachmentNameLength caps the stored file name of an attachment.
+const MaxAttachmentNameLength = 255
+
+// Attachment describes a file attached to a task. The contents are kept in
+// the attachment storage under StorageKey.
+type Attachment struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TaskID   uuid.UUID `gorm:"type:uuid;index;not null" json:"task_id"`
+	FileName string    `gorm:"not null" json:"file_name"`
+	// ContentType is detected from the contents rather than taken from the
+	// upload.
+	ContentType string `gorm:"not null" json:"content_type"`
+	Size        int64  `json:"size"`
+	// SHA256 is the hex encoded SHA-256 digest of the contents.
+	SHA256    string    `gorm:"not null" json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// StorageKey is where the contents of the attachment are stored.
+func (a Attachment) StorageKey() string {
+	return "tasks/" + a.TaskID.String() + "/" + a.ID.String()
+}
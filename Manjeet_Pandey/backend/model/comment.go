@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Comment is a note left on a task. Body is markdown and is stored as
+// written; rendering it is up to the client.
+type Comment struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TaskID    uuid.UUID `gorm:"type:uuid;index;not null" json:"task_id"`
+	Author    string    `gorm:"not null" json:"author"`
+	Body      string    `gorm:"not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// EditedAt is set once the body has been changed after posting.
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateCommentRequest is the body accepted when commenting on a task.
+type CreateCommentRequest struct {
+	Author string `json:"author" validate:"required,notblank,max=100" example:"manjeet"`
+	Body   string `json:"body" validate:"required,notblank,max=10000" example:"Blocked on the **import** command"`
+}
+
+func (r CreateCommentRequest) Comment() Comment {
+	return Comment{Author: r.Author, Body: r.Body}
+}
+
+// UpdateCommentRequest is the body accepted when editing a comment. Only
+// the body can change.
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,notblank,max=10000" example:"Unblocked, the import command is documented"`
+}
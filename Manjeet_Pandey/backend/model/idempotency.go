@@ -0,0 +1,26 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key so that retries of it can be answered without running it
+// again. Keys are scoped to the client that sent them.
+type IdempotencyRecord struct {
+	Client string `gorm:"primaryKey"`
+	Key    string `gorm:"primaryKey"`
+	// Fingerprint identifies the method, path, Accept and Content-Type
+	// headers and body of the request.
+	Fingerprint string `gorm:"not null"`
+	// Status is 0 while the first request is still being handled.
+	Status      int
+	ContentType string
+	// Header holds the response headers replayed along with the body, such
+	// as Location.
+	Header    http.Header `gorm:"serializer:json"`
+	Body      []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultLabelColor is given to labels created without a colour.
+const DefaultLabelColor = "#6b7280"
+
+// Label categorises tasks, by team or component for example. A task can
+// carry any number of labels and a label any number of tasks.
+type Label struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name string    `gorm:"not null;uniqueIndex" json:"name"`
+	// Color is a CSS hex colour such as #1d76db.
+	Color     string    `gorm:"not null" json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (l *Label) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateLabelRequest is the body accepted when creating a label. Names are
+// unique and can't contain commas, which separate names in filters.
+type CreateLabelRequest struct {
+	Name  string `json:"name" validate:"required,notblank,max=50,excludesall=0x2C" example:"backend"`
+	Color string `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#1d76db"`
+}
+
+func (r CreateLabelRequest) Label() Label {
+	color := r.Color
+	if color == "" {
+		color = DefaultLabelColor
+	}
+	return Label{Name: r.Name, Color: color}
+}
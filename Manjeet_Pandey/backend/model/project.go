@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultProjectID identifies the project holding the tasks created
+// without one, and every task that predates projects.
+var DefaultProjectID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+const DefaultProjectName = "Default"
+
+// Project groups tasks. Every task belongs to exactly one project.
+type Project struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string    `gorm:"not null;uniqueIndex" json:"name"`
+	Description string    `json:"description"`
+	// MaxConcurrency caps how many of the project's tasks a worker pool
+	// processes at once; 0 leaves it to the size of the pool.
+	MaxConcurrency int       `gorm:"not null;default:0" json:"max_concurrency"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (p *Project) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateProjectRequest is the body accepted when creating a project. Names
+// are unique.
+type CreateProjectRequest struct {
+	Name           string `json:"name" validate:"required,notblank,max=100" example:"Mobile app"`
+	Description    string `json:"description" validate:"max=1000" example:"Tasks for the iOS and Android apps"`
+	MaxConcurrency int    `json:"max_concurrency" validate:"min=0,max=100" example:"2"`
+}
+
+func (r CreateProjectRequest) Project() Project {
+	return Project{Name: r.Name, Description: r.Description, MaxConcurrency: r.MaxConcurrency}
+}
+
+// UpdateProjectRequest is the body accepted when replacing a project.
+type UpdateProjectRequest struct {
+	Name           string `json:"name" validate:"required,notblank,max=100" example:"Mobile app"`
+	Description    string `json:"description" validate:"max=1000" example:"Tasks for the iOS and Android apps"`
+	MaxConcurrency int    `json:"max_concurrency" validate:"min=0,max=100" example:"2"`
+}
+
+func (r UpdateProjectRequest) Project() Project {
+	return Project{Name: r.Name, Description: r.Description, MaxConcurrency: r.MaxConcurrency}
+}
@@ -10,18 +10,52 @@ import (
 
 type TaskStatus string
 
+var ErrInvalidStatus = errors.New("invalid status: must be 'Pending' or 'Completed'")
+
 const (
 	StatusPending   TaskStatus = "Pending"
 	StatusCompleted TaskStatus = "Completed"
 )
 
 type Task struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
-	Name        string         `gorm:"not null" json:"name"`
-	Description string         `json:"description"`
-	Status      TaskStatus     `gorm:"default:Pending" json:"status"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Description string     `json:"description"`
+	Status      TaskStatus `gorm:"default:Pending" json:"status"`
+	// ProjectID is set by the project the task is created in and never
+	// changes.
+	ProjectID uuid.UUID `gorm:"type:uuid;index" json:"project_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Labels are set through the label endpoints, never by writing the
+	// task.
+	Labels []Label `gorm:"many2many:task_labels" json:"labels,omitempty"`
+	// TraceParent is the W3C trace context of the request that created the
+	// task, so the span that later processes it can link back to it.
+	TraceParent string `json:"-"`
+	// ClaimedAt is when a worker claimed the pending task for processing,
+	// so no other worker picks it up. A status change releases the claim.
+	ClaimedAt *time.Time `json:"-"`
+}
+
+// The longest name and description a task may have. TaskRequest enforces
+// them; struct tags can't name constants, so its max tags repeat them.
+const (
+	MaxTaskNameLength        = 100
+	MaxTaskDescriptionLength = 1000
+)
+
+// TaskRequest is the body accepted when creating or replacing a task. The
+// ID and timestamps are always assigned by the server. A missing status
+// makes a new task Pending and resets a replaced one to Pending.
+type TaskRequest struct {
+	Name        string     `json:"name" validate:"required,notblank,max=100" example:"Write docs"`
+	Description string     `json:"description" validate:"max=1000" example:"Cover the import command"`
+	Status      TaskStatus `json:"status,omitempty" validate:"omitempty,oneof=Pending Completed"`
+}
+
+func (r TaskRequest) Task() Task {
+	return Task{Name: r.Name, Description: r.Description, Status: r.Status}
 }
 
 func (s TaskStatus) Validate() error {
@@ -29,7 +63,7 @@ func (s TaskStatus) Validate() error {
 	case StatusPending, StatusCompleted:
 		return nil
 	default:
-		return errors.New("invalid status: must be 'Pending' or 'Completed'")
+		return ErrInvalidStatus
 	}
 }
 
@@ -37,6 +71,9 @@ func (t *Task) BeforeCreate(tx *gorm.DB) error {
 	if t.ID == uuid.Nil {
 		t.ID = uuid.New()
 	}
+	if t.ProjectID == uuid.Nil {
+		t.ProjectID = DefaultProjectID
+	}
 	return t.Status.Validate()
 }
 
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook is a subscription that receives task events over HTTP.
+type Webhook struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Events    []string  `gorm:"serializer:json" json:"events"`
+	Secret    string    `gorm:"not null" json:"secret,omitempty"`
+	Active    bool      `gorm:"default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	WebhookID  uuid.UUID `gorm:"type:uuid;index;not null" json:"webhook_id"`
+	EventID    uint64    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookEvent is an event waiting in the outbox for delivery to webhooks.
+// It is written in the same transaction as the change it describes, and
+// deleted once every subscribed webhook has received it or been given up
+// on, so events outlive a restart. Payload is the event as JSON; its ID is
+// the row's.
+//
+// A dispatcher claims an event before delivering it: ClaimedBy names the
+// dispatcher and other dispatchers leave the event alone until LockedUntil,
+// so each event is delivered by one process even when several share the
+// database.
+type WebhookEvent struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	Type        string `gorm:"not null"`
+	Payload     []byte `gorm:"not null"`
+	ClaimedBy   string `gorm:"not null;default:''"`
+	LockedUntil *time.Time
+	CreatedAt   time.Time
+}
@@ -1,64 +1,265 @@
 package util
 
 import (
-	"fmt"
+	"context"
+	"maps"
 	"sync"
+	"task_manager/internal/events"
+	"task_manager/internal/metrics"
 	"task_manager/internal/service"
+	"task_manager/internal/tracing"
 	"task_manager/model"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// job is a queued task together with the trace context it was queued under
+// and the one it was created under.
+type job struct {
+	taskID    uuid.UUID
+	projectID uuid.UUID
+	queued    trace.SpanContext
+	created   trace.SpanContext
+}
+
+// Worker processes queued tasks, whichever project they belong to, with a
+// fixed number of goroutines. A project's MaxConcurrency caps how many of
+// them work on its tasks at once; tasks beyond the cap wait aside, so they
+// never hold up the tasks of other projects.
 type Worker struct {
 	workers     int
-	channel     chan uuid.UUID
+	channel     chan job
 	wg          sync.WaitGroup
 	taskService service.TaskService
+	// submitting counts the batches Submit is still queuing.
+	submitting sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+	// limits, running and waiting are kept per project.
+	limits  map[uuid.UUID]int
+	running map[uuid.UUID]int
+	waiting map[uuid.UUID][]job
 }
 
 func NewWorker(workers int, taskService service.TaskService) *Worker {
 	return &Worker{
 		workers:     workers,
-		channel:     make(chan uuid.UUID, 50),
+		channel:     make(chan job, 50),
 		taskService: taskService,
+		limits:      map[uuid.UUID]int{},
+		running:     map[uuid.UUID]int{},
+		waiting:     map[uuid.UUID][]job{},
 	}
 }
 
 func (w *Worker) StartWorker() {
+	metrics.TrackQueue(w, w.depth)
 	for i := 0; i < w.workers; i++ {
 		w.wg.Add(1)
-		go w.processTask()
+		go w.processTask(i)
 	}
 }
 
-func (w *Worker) AddToQueue(taskId uuid.UUID) {
-	w.channel <- taskId
+// AddToQueue queues task for processing. The span that processes it is a
+// child of the span in ctx and links to the request that created the task.
+func (w *Worker) AddToQueue(ctx context.Context, task model.Task) {
+	w.loadLimit(ctx, task.ProjectID)
+	w.channel <- job{
+		taskID:    task.ID,
+		projectID: task.ProjectID,
+		queued:    trace.SpanContextFromContext(ctx),
+		created:   tracing.SpanContextFrom(task.TraceParent),
+	}
 }
 
-func (w *Worker) processTask() {
+// Submit queues tasks for processing in the background, so the caller
+// doesn't wait for room in the queue. The concurrency limits of their
+// projects are read again first, so a long-running worker applies limits
+// changed since the last batch. It reports false, queuing nothing, once
+// the worker is closed.
+func (w *Worker) Submit(ctx context.Context, tasks []model.Task) bool {
+	limits := map[uuid.UUID]int{}
+	for _, task := range tasks {
+		if _, ok := limits[task.ProjectID]; !ok {
+			limits[task.ProjectID] = w.readLimit(ctx, task.ProjectID)
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return false
+	}
+	maps.Copy(w.limits, limits)
+	w.submitting.Add(1)
+	go func() {
+		defer w.submitting.Done()
+		for _, task := range tasks {
+			w.AddToQueue(ctx, task)
+		}
+	}()
+	return true
+}
+
+// loadLimit reads the concurrency limit of a project the first time one of
+// its tasks is queued. Without it, the project is only limited by the pool.
+func (w *Worker) loadLimit(ctx context.Context, projectID uuid.UUID) {
+	w.mu.Lock()
+	_, ok := w.limits[projectID]
+	w.mu.Unlock()
+	if ok {
+		return
+	}
+	limit := w.readLimit(ctx, projectID)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.limits[projectID]; !ok {
+		w.limits[projectID] = limit
+	}
+}
+
+func (w *Worker) readLimit(ctx context.Context, projectID uuid.UUID) int {
+	tasks := w.taskService.InProject(projectID)
+	project, err := tasks.Project(ctx)
+	if err != nil {
+		log.Warn().Err(err).Str("project_id", projectID.String()).Msg("Cannot read the concurrency limit of the project")
+	}
+	return project.MaxConcurrency
+}
+
+// start reports whether a worker may process j now. If the project is at
+// its limit, j waits until one of its running tasks finishes.
+func (w *Worker) start(j job) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if limit := w.limits[j.projectID]; limit > 0 && w.running[j.projectID] >= limit {
+		w.waiting[j.projectID] = append(w.waiting[j.projectID], j)
+		return false
+	}
+	w.running[j.projectID]++
+	return true
+}
+
+// finish records that a task of the project is done and hands back the
+// next one waiting for the slot it frees, if any.
+func (w *Worker) finish(projectID uuid.UUID) (job, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if waiting := w.waiting[projectID]; len(waiting) > 0 {
+		w.waiting[projectID] = waiting[1:]
+		return waiting[0], true
+	}
+	w.running[projectID]--
+	return job{}, false
+}
+
+// depth counts the tasks queued or waiting for their project's limit.
+func (w *Worker) depth() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	depth := len(w.channel)
+	for _, waiting := range w.waiting {
+		depth += len(waiting)
+	}
+	return depth
+}
+
+// Close tells the workers that no more tasks will be queued, once the
+// batches already submitted are. They exit when the queue is drained.
+// Closing a closed worker does nothing.
+func (w *Worker) Close() {
+	w.mu.Lock()
+	closed := w.closed
+	w.closed = true
+	w.mu.Unlock()
+	if closed {
+		return
+	}
+	w.submitting.Wait()
+	close(w.channel)
+}
+
+// Shutdown closes the worker and waits for the queued tasks to be processed
+// or for ctx to expire. Tasks not processed by then keep their claim until
+// service.ClaimTimeout, after which they can be claimed again.
+func (w *Worker) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		w.Close()
+		w.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) processTask(workerID int) {
 	defer w.wg.Done()
-	for {
-		select {
-		case taskId := <-w.channel:
-			fmt.Println("Processing Task with ID", taskId)
-			// Do something here , Like some actual work
-			fmt.Println("Cooking something here")
-			err := w.taskService.UpdateTask(taskId, model.Task{
-				Status: model.StatusCompleted,
-			})
-			if err != nil {
-				fmt.Println("Cannot Process Task")
-				log.Err(err).Msg("Cannot update task")
-			}
-			fmt.Println("Processed Task with ID", taskId)
-		default:
-			return
+	workerLog := log.With().Int("worker_id", workerID).Logger()
+	for job := range w.channel {
+		if !w.start(job) {
+			continue
+		}
+		// Tasks waiting for this project's limit are run by whoever frees a
+		// slot, so none is left behind once the queue is drained.
+		for ok := true; ok; job, ok = w.finish(job.projectID) {
+			w.process(workerLog, workerID, job)
 		}
 	}
+}
+
+func (w *Worker) process(workerLog zerolog.Logger, workerID int, job job) {
+	taskId := job.taskID
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("task.id", taskId.String()), attribute.Int("worker.id", workerID)),
+	}
+	if job.created.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: job.created}))
+	}
+	ctx := trace.ContextWithSpanContext(context.Background(), job.queued)
+	ctx, span := tracing.Tracer().Start(ctx, "worker.processTask", opts...)
+	var err error
+	defer func() { tracing.End(span, err) }()
 
+	taskLog := workerLog.With().Str("task_id", taskId.String()).Logger()
+	ctx = tracing.WithLogger(taskLog.WithContext(ctx))
+	taskLog = *zerolog.Ctx(ctx)
+	taskLog.Info().Msg("Processing task")
+	tasks := w.taskService.InProject(job.projectID)
+	w.announce(ctx, tasks, events.Event{Type: events.TaskProcessing, TaskID: taskId})
+	start := time.Now()
+	// Do something here , Like some actual work
+	taskLog.Debug().Msg("Cooking something here")
+	_, err = tasks.CompleteTask(ctx, taskId)
+	elapsed := time.Since(start)
+	metrics.ProcessingDuration.Observe(elapsed.Seconds())
+	if err != nil {
+		taskLog.Err(err).Dur("duration", elapsed).Msg("Cannot process task")
+		metrics.TasksFailed.Inc()
+		w.announce(ctx, tasks, events.Event{Type: events.TaskFailed, TaskID: taskId, Error: err.Error()})
+		return
+	}
+	metrics.TasksProcessed.Inc()
+	taskLog.Info().Dur("duration", elapsed).Msg("Processed task")
+}
+
+func (w *Worker) announce(ctx context.Context, tasks service.TaskService, event events.Event) {
+	if err := tasks.Announce(ctx, event); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("event", string(event.Type)).Msg("Cannot queue the event for webhooks")
+	}
 }
 
 func (w *Worker) Wait() {
 	w.wg.Wait()
+	metrics.UntrackQueue(w)
 }
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"task_manager/internal/database"
+	"task_manager/internal/logging"
+	"task_manager/internal/service"
+	"task_manager/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logging.Setup("disabled", "console")
+	os.Exit(m.Run())
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "tasks.db") + database.DSNOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+	return db
+}
+
+func TestWorkerProjectLimit(t *testing.T) {
+	limited, other, unlimited := uuid.New(), uuid.New(), uuid.New()
+	w := NewWorker(4, service.TaskService{})
+	w.limits[limited] = 1
+	w.limits[other] = 2
+
+	first, second, third := job{taskID: uuid.New(), projectID: limited}, job{taskID: uuid.New(), projectID: limited}, job{taskID: uuid.New(), projectID: limited}
+	if !w.start(first) {
+		t.Fatal("first task of the project doesn't start")
+	}
+	if w.start(second) || w.start(third) {
+		t.Fatal("a task starts beyond the project's limit")
+	}
+	// Other projects aren't held up by the waiting tasks.
+	if !w.start(job{taskID: uuid.New(), projectID: other}) || !w.start(job{taskID: uuid.New(), projectID: other}) {
+		t.Error("a task of another project doesn't start")
+	}
+	for range 3 {
+		if !w.start(job{taskID: uuid.New(), projectID: unlimited}) {
+			t.Fatal("a project without a limit is limited")
+		}
+	}
+	if depth := w.depth(); depth != 2 {
+		t.Errorf("depth is %d, want the 2 waiting tasks", depth)
+	}
+
+	// Finishing hands the freed slot to the waiting tasks in order.
+	if next, ok := w.finish(limited); !ok || next.taskID != second.taskID {
+		t.Fatalf("finish got %v, %v, want the second task", next, ok)
+	}
+	if next, ok := w.finish(limited); !ok || next.taskID != third.taskID {
+		t.Fatalf("finish got %v, %v, want the third task", next, ok)
+	}
+	if _, ok := w.finish(limited); ok {
+		t.Fatal("finish hands back a task nobody queued")
+	}
+	if w.running[limited] != 0 || w.depth() != 0 {
+		t.Errorf("%d running and %d waiting after every task finished", w.running[limited], w.depth())
+	}
+	if !w.start(job{taskID: uuid.New(), projectID: limited}) {
+		t.Error("the project's slot isn't freed")
+	}
+}
+
+func TestWorkerProcessesLimitedProjects(t *testing.T) {
+	db := openDB(t)
+	ctx := context.Background()
+	projects := service.NewProjectService(db)
+	limited, err := projects.CreateProject(ctx, model.CreateProjectRequest{Name: "Limited", MaxConcurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := service.NewTaskService(db, nil)
+
+	var queued []model.Task
+	for _, projectID := range []uuid.UUID{limited.ID, model.DefaultProjectID} {
+		in := tasks.InProject(projectID)
+		for range 5 {
+			task, err := in.CreateTask(ctx, model.Task{Name: "work"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			queued = append(queued, task)
+		}
+	}
+
+	w := NewWorker(3, tasks)
+	w.StartWorker()
+	if !w.Submit(ctx, queued) {
+		t.Fatal("Submit refused the batch")
+	}
+	shutdown, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := w.Shutdown(shutdown); err != nil {
+		t.Fatalf("workers didn't drain the queue: %v", err)
+	}
+	if w.limits[limited.ID] != 1 {
+		t.Errorf("limit of the project is %d, want 1", w.limits[limited.ID])
+	}
+	if w.running[limited.ID] != 0 || w.depth() != 0 {
+		t.Errorf("%d running and %d waiting after shutdown", w.running[limited.ID], w.depth())
+	}
+	for _, task := range queued {
+		in := tasks.InProject(task.ProjectID)
+		stored, err := in.GetTask(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != model.StatusCompleted {
+			t.Errorf("task of project %s is %s, want completed", task.ProjectID, stored.Status)
+		}
+	}
+}
+
+func TestSubmitRereadsLimits(t *testing.T) {
+	db := openDB(t)
+	ctx := context.Background()
+	projects := service.NewProjectService(db)
+	project, err := projects.CreateProject(ctx, model.CreateProjectRequest{Name: "Tuned", MaxConcurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := service.NewTaskService(db, nil).InProject(project.ID)
+	task, err := tasks.CreateTask(ctx, model.Task{Name: "work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Workers aren't started, so nothing is processed.
+	w := NewWorker(1, tasks)
+	w.Submit(ctx, []model.Task{task})
+	if _, err := projects.ReplaceProject(ctx, project.ID, model.UpdateProjectRequest{Name: "Tuned", MaxConcurrency: 3}); err != nil {
+		t.Fatal(err)
+	}
+	w.Submit(ctx, []model.Task{task})
+	w.mu.Lock()
+	limit := w.limits[project.ID]
+	w.mu.Unlock()
+	if limit != 3 {
+		t.Errorf("limit is %d after the project changed, want 3", limit)
+	}
+	w.Close()
+	if w.Submit(ctx, []model.Task{task}) {
+		t.Error("a closed worker accepts a batch")
+	}
+}
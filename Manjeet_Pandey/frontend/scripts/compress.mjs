@@ -0,0 +1,38 @@
+// Writes gzip and brotli copies of the compressible files in the build, so
+// the API can serve them precompressed.
+import { readdirSync, readFileSync, statSync, writeFileSync } from "node:fs";
+import { extname, join } from "node:path";
+import { brotliCompressSync, constants, gzipSync } from "node:zlib";
+
+const outDir = new URL("../../backend/internal/web/static/dist", import.meta.url)
+  .pathname;
+const compressible = new Set([
+  ".html",
+  ".js",
+  ".css",
+  ".svg",
+  ".json",
+  ".txt",
+  ".map",
+]);
+// Smaller files aren't worth the extra request handling.
+const minSize = 1024;
+
+const walk = (dir) =>
+  readdirSync(dir).flatMap((name) => {
+    const path = join(dir, name);
+    return statSync(path).isDirectory() ? walk(path) : [path];
+  });
+
+for (const path of walk(outDir)) {
+  if (!compressible.has(extname(path))) continue;
+  const data = readFileSync(path);
+  if (data.length < minSize) continue;
+  writeFileSync(`${path}.gz`, gzipSync(data, { level: 9 }));
+  writeFileSync(
+    `${path}.br`,
+    brotliCompressSync(data, {
+      params: { [constants.BROTLI_PARAM_QUALITY]: constants.BROTLI_MAX_QUALITY },
+    }),
+  );
+}
@@ -1,9 +1,13 @@
+import type { ResponseProblem } from "../models";
+import { apiBaseUrl } from "./baseUrl";
+
 export const customInstance = async <T>({
   url,
   method,
   params,
   headers,
   data,
+  responseType,
   signal,
 }: {
   url: string;
@@ -14,24 +18,52 @@ export const customInstance = async <T>({
   responseType?: string;
   signal?: AbortSignal;
 }): Promise<T> => {
-  const requestUrl = `${import.meta.env.VITE_API_BASE_URL}${url}`;
+  const requestUrl = `${apiBaseUrl}${url}`;
+  // Form data is sent as is, and the browser sets the multipart
+  // Content-Type with its boundary.
+  const isForm = data instanceof FormData;
+  const requestHeaders: Record<string, any> = {
+    "Content-Type": "application/json",
+    ...headers,
+  };
+  if (isForm) {
+    delete requestHeaders["Content-Type"];
+  }
   const options = {
     method,
-    headers: {
-      "Content-Type": "application/json",
-      ...headers,
-    },
-    ...(data && { body: JSON.stringify(data) }),
+    headers: requestHeaders,
+    ...(data && { body: isForm ? data : JSON.stringify(data) }),
     signal
   };
-  const queryParams = params
-    ? "?" + new URLSearchParams(params).toString()
-    : "";
+  // Arrays repeat their key, as in label=a&label=b, and unset values are
+  // left out.
+  const search = new URLSearchParams();
+  for (const [key, value] of Object.entries(params ?? {})) {
+    for (const item of [value].flat()) {
+      if (item !== undefined && item !== null) {
+        search.append(key, String(item));
+      }
+    }
+  }
+  const query = search.toString();
+  const queryParams = query ? `?${query}` : "";
 
   const fullUrl = `${requestUrl}${queryParams}`;
 
   const response = await fetch(fullUrl, options);
-  return response.json();
+  if (response.status === 204) {
+    return undefined as T;
+  }
+  if (responseType === "blob" && response.ok) {
+    return (await response.blob()) as T;
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    // Errors arrive as RFC 7807 problem details. Rejecting with them lets
+    // react-query and callers' catch blocks see the failure.
+    throw body as ResponseProblem;
+  }
+  return body;
 };
 
 export default customInstance;
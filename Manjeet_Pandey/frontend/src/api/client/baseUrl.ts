@@ -0,0 +1,5 @@
+/**
+ * Where the API is served. Empty when the frontend is served by the API
+ * itself, so requests go to the same origin.
+ */
+export const apiBaseUrl = import.meta.env.VITE_API_BASE_URL ?? "";
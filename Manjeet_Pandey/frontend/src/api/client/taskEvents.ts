@@ -0,0 +1,91 @@
+import { useEffect } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import {
+  getListActivityQueryKey,
+  getListTasksQueryKey,
+} from "../generated/taskManagerApis";
+import type { ModelTask, V2TaskList } from "../models";
+import { apiBaseUrl } from "./baseUrl";
+
+export type TaskEventType =
+  | "task.created"
+  | "task.updated"
+  | "task.deleted"
+  | "task.processing"
+  | "task.completed"
+  | "task.failed"
+  | "tasks.imported";
+
+export interface TaskEvent {
+  id: number;
+  type: TaskEventType;
+  task_id: string;
+  task?: ModelTask;
+  error?: string;
+  time: string;
+}
+
+/**
+ * Subscribes to the /api/v2/events stream and keeps the cached task list in
+ * sync, so changes made by other clients or the worker show up without a
+ * refetch. Lists filtered by label are refetched instead, since a changed
+ * task may have entered or left them. The activity feed of a changed task is
+ * refetched if it is open.
+ */
+export const useTaskEvents = () => {
+  const queryClient = useQueryClient();
+
+  useEffect(() => {
+    const source = new EventSource(`${apiBaseUrl}/api/v2/events`);
+    const queryKey = getListTasksQueryKey();
+
+    const updateList = (update: (tasks: ModelTask[]) => ModelTask[]) => {
+      queryClient.setQueryData<V2TaskList>(queryKey, (list) =>
+        list ? { ...list, items: update(list.items ?? []) } : list,
+      );
+    };
+
+    // The filtered lists have the filter after the path in their key.
+    const refetchFiltered = () => {
+      queryClient.invalidateQueries({
+        queryKey,
+        predicate: (query) => query.queryKey.length > queryKey.length,
+      });
+    };
+
+    const upsert = (message: MessageEvent<string>) => {
+      const event: TaskEvent = JSON.parse(message.data);
+      const task = event.task;
+      if (!task) return;
+      queryClient.invalidateQueries({
+        queryKey: getListActivityQueryKey(event.task_id),
+      });
+      updateList((tasks) =>
+        tasks.some((t) => t.id === task.id)
+          ? tasks.map((t) => (t.id === task.id ? task : t))
+          : [...tasks, task],
+      );
+      refetchFiltered();
+    };
+
+    const remove = (message: MessageEvent<string>) => {
+      const event: TaskEvent = JSON.parse(message.data);
+      updateList((tasks) => tasks.filter((t) => t.id !== event.task_id));
+      refetchFiltered();
+    };
+
+    const refetch = () => {
+      queryClient.invalidateQueries({ queryKey });
+    };
+
+    source.addEventListener("task.created", upsert);
+    source.addEventListener("task.updated", upsert);
+    source.addEventListener("task.completed", upsert);
+    source.addEventListener("task.deleted", remove);
+    source.addEventListener("tasks.imported", refetch);
+    // Events may have been missed while disconnected.
+    source.addEventListener("open", refetch);
+
+    return () => source.close();
+  }, [queryClient]);
+};
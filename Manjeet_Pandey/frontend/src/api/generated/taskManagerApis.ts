@@ -2,8 +2,8 @@
  * Generated by orval v7.5.0 🍺
  * Do not edit manually.
  * Task Manager API
- * A simple task management API built with Go and Gin.
- * OpenAPI spec version: 1.0
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
  */
 import { useMutation, useQuery } from "@tanstack/react-query";
 import type {
@@ -20,116 +20,5699 @@ import type {
   UseQueryResult,
 } from "@tanstack/react-query";
 import type {
-  CreateTask201,
-  GetTaskByID200,
-  ListTasks200,
+  CreateAttachmentBody,
+  CreateProjectAttachmentBody,
+  EventsEvent,
+  ExportTasksParams,
+  HandlerBulkResponse,
+  HandlerWebhookRequest,
+  ImportTasksParams,
+  ListProjectTasksParams,
+  ListTasksParams,
+  ModelAttachment,
+  ModelComment,
+  ModelCreateCommentRequest,
+  ModelCreateLabelRequest,
+  ModelCreateProjectRequest,
+  ModelLabel,
+  ModelProject,
   ModelTask,
-  TaskManagerInternalResponseResponse,
-  UpdateTask200,
+  ModelTaskRequest,
+  ModelUpdateCommentRequest,
+  ModelUpdateProjectRequest,
+  ModelWebhook,
+  ModelWebhookDelivery,
+  PatchProjectTaskBody,
+  PatchTaskBody,
+  ResponseProblem,
+  ServiceBulkRequest,
+  ServiceImportSummary,
+  StreamEventsParams,
+  V2ActivityList,
+  V2AttachmentList,
+  V2CommentList,
+  V2DeliveryList,
+  V2LabelList,
+  V2ProjectList,
+  V2TaskList,
+  V2WebhookList,
+  WatchEventsParams,
 } from "../models";
 import { customInstance } from "../client/apiClient";
 
 /**
- * Retrieves a list of all tasks stored in the database.
+ * Streams task changes as Server-Sent Events. Each event is named after its type and carries an events.Event as JSON.
+ * @summary Stream task events
+ */
+export const streamEvents = (
+  params?: StreamEventsParams,
+  signal?: AbortSignal,
+) => {
+  return customInstance<EventsEvent>({
+    url: `/api/v2/events`,
+    method: "GET",
+    params,
+    signal,
+  });
+};
+
+export const getStreamEventsQueryKey = (params?: StreamEventsParams) => {
+  return [`/api/v2/events`, ...(params ? [params] : [])] as const;
+};
+
+export const getStreamEventsQueryOptions = <
+  TData = Awaited<ReturnType<typeof streamEvents>>,
+  TError = ResponseProblem,
+>(
+  params?: StreamEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof streamEvents>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getStreamEventsQueryKey(params);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof streamEvents>>> = ({
+    signal,
+  }) => streamEvents(params, signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof streamEvents>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type StreamEventsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof streamEvents>>
+>;
+export type StreamEventsQueryError = ResponseProblem;
+
+export function useStreamEvents<
+  TData = Awaited<ReturnType<typeof streamEvents>>,
+  TError = ResponseProblem,
+>(
+  params: undefined | StreamEventsParams,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof streamEvents>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof streamEvents>>,
+          TError,
+          Awaited<ReturnType<typeof streamEvents>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useStreamEvents<
+  TData = Awaited<ReturnType<typeof streamEvents>>,
+  TError = ResponseProblem,
+>(
+  params?: StreamEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof streamEvents>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof streamEvents>>,
+          TError,
+          Awaited<ReturnType<typeof streamEvents>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useStreamEvents<
+  TData = Awaited<ReturnType<typeof streamEvents>>,
+  TError = ResponseProblem,
+>(
+  params?: StreamEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof streamEvents>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Stream task events
+ */
+
+export function useStreamEvents<
+  TData = Awaited<ReturnType<typeof streamEvents>>,
+  TError = ResponseProblem,
+>(
+  params?: StreamEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof streamEvents>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getStreamEventsQueryOptions(params, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Retrieves every label, by name.
+ * @summary List labels
+ */
+export const listLabels = (signal?: AbortSignal) => {
+  return customInstance<V2LabelList>({
+    url: `/api/v2/labels`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListLabelsQueryKey = () => {
+  return [`/api/v2/labels`] as const;
+};
+
+export const getListLabelsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listLabels>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listLabels>>, TError, TData>
+  >;
+}) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListLabelsQueryKey();
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listLabels>>> = ({
+    signal,
+  }) => listLabels(signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof listLabels>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListLabelsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listLabels>>
+>;
+export type ListLabelsQueryError = ResponseProblem;
+
+export function useListLabels<
+  TData = Awaited<ReturnType<typeof listLabels>>,
+  TError = ResponseProblem,
+>(options: {
+  query: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listLabels>>, TError, TData>
+  > &
+    Pick<
+      DefinedInitialDataOptions<
+        Awaited<ReturnType<typeof listLabels>>,
+        TError,
+        Awaited<ReturnType<typeof listLabels>>
+      >,
+      "initialData"
+    >;
+}): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListLabels<
+  TData = Awaited<ReturnType<typeof listLabels>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listLabels>>, TError, TData>
+  > &
+    Pick<
+      UndefinedInitialDataOptions<
+        Awaited<ReturnType<typeof listLabels>>,
+        TError,
+        Awaited<ReturnType<typeof listLabels>>
+      >,
+      "initialData"
+    >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListLabels<
+  TData = Awaited<ReturnType<typeof listLabels>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listLabels>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List labels
+ */
+
+export function useListLabels<
+  TData = Awaited<ReturnType<typeof listLabels>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listLabels>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListLabelsQueryOptions(options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Creates a label that can then be attached to tasks. Names are unique; the colour defaults to grey.
+ * @summary Create a label
+ */
+export const createLabel = (
+  modelCreateLabelRequest: ModelCreateLabelRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelLabel>({
+    url: `/api/v2/labels`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelCreateLabelRequest,
+    signal,
+  });
+};
+
+export const getCreateLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createLabel>>,
+    TError,
+    { data: ModelCreateLabelRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createLabel>>,
+  TError,
+  { data: ModelCreateLabelRequest },
+  TContext
+> => {
+  const mutationKey = ["createLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createLabel>>,
+    { data: ModelCreateLabelRequest }
+  > = (props) => {
+    const { data } = props ?? {};
+
+    return createLabel(data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createLabel>>
+>;
+export type CreateLabelMutationBody = ModelCreateLabelRequest;
+export type CreateLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Create a label
+ */
+export const useCreateLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createLabel>>,
+    TError,
+    { data: ModelCreateLabelRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createLabel>>,
+  TError,
+  { data: ModelCreateLabelRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes a label and removes it from every task carrying it.
+ * @summary Delete a label
+ */
+export const deleteLabel = (labelId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/labels/${labelId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteLabel>>,
+    TError,
+    { labelId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteLabel>>,
+  TError,
+  { labelId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteLabel>>,
+    { labelId: string }
+  > = (props) => {
+    const { labelId } = props ?? {};
+
+    return deleteLabel(labelId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteLabel>>
+>;
+
+export type DeleteLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a label
+ */
+export const useDeleteLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteLabel>>,
+    TError,
+    { labelId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteLabel>>,
+  TError,
+  { labelId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves every project, by name, the default project included.
+ * @summary List projects
+ */
+export const listProjects = (signal?: AbortSignal) => {
+  return customInstance<V2ProjectList>({
+    url: `/api/v2/projects`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListProjectsQueryKey = () => {
+  return [`/api/v2/projects`] as const;
+};
+
+export const getListProjectsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listProjects>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listProjects>>, TError, TData>
+  >;
+}) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListProjectsQueryKey();
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listProjects>>> = ({
+    signal,
+  }) => listProjects(signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof listProjects>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListProjectsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listProjects>>
+>;
+export type ListProjectsQueryError = ResponseProblem;
+
+export function useListProjects<
+  TData = Awaited<ReturnType<typeof listProjects>>,
+  TError = ResponseProblem,
+>(options: {
+  query: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listProjects>>, TError, TData>
+  > &
+    Pick<
+      DefinedInitialDataOptions<
+        Awaited<ReturnType<typeof listProjects>>,
+        TError,
+        Awaited<ReturnType<typeof listProjects>>
+      >,
+      "initialData"
+    >;
+}): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjects<
+  TData = Awaited<ReturnType<typeof listProjects>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listProjects>>, TError, TData>
+  > &
+    Pick<
+      UndefinedInitialDataOptions<
+        Awaited<ReturnType<typeof listProjects>>,
+        TError,
+        Awaited<ReturnType<typeof listProjects>>
+      >,
+      "initialData"
+    >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjects<
+  TData = Awaited<ReturnType<typeof listProjects>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listProjects>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List projects
+ */
+
+export function useListProjects<
+  TData = Awaited<ReturnType<typeof listProjects>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listProjects>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListProjectsQueryOptions(options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Creates a project to group tasks. Names are unique. max_concurrency caps how many of the project's tasks a worker pool processes at once; 0 leaves it to the size of the pool.
+ * @summary Create a project
+ */
+export const createProject = (
+  modelCreateProjectRequest: ModelCreateProjectRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelProject>({
+    url: `/api/v2/projects`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelCreateProjectRequest,
+    signal,
+  });
+};
+
+export const getCreateProjectMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProject>>,
+    TError,
+    { data: ModelCreateProjectRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createProject>>,
+  TError,
+  { data: ModelCreateProjectRequest },
+  TContext
+> => {
+  const mutationKey = ["createProject"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createProject>>,
+    { data: ModelCreateProjectRequest }
+  > = (props) => {
+    const { data } = props ?? {};
+
+    return createProject(data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateProjectMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createProject>>
+>;
+export type CreateProjectMutationBody = ModelCreateProjectRequest;
+export type CreateProjectMutationError = ResponseProblem;
+
+/**
+ * @summary Create a project
+ */
+export const useCreateProject = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProject>>,
+    TError,
+    { data: ModelCreateProjectRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createProject>>,
+  TError,
+  { data: ModelCreateProjectRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateProjectMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves a project by its unique identifier.
+ * @summary Get a project
+ */
+export const getProject = (projectId: string, signal?: AbortSignal) => {
+  return customInstance<ModelProject>({
+    url: `/api/v2/projects/${projectId}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetProjectQueryKey = (projectId: string) => {
+  return [`/api/v2/projects/${projectId}`] as const;
+};
+
+export const getGetProjectQueryOptions = <
+  TData = Awaited<ReturnType<typeof getProject>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProject>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getGetProjectQueryKey(projectId);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof getProject>>> = ({
+    signal,
+  }) => getProject(projectId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!projectId,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getProject>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetProjectQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getProject>>
+>;
+export type GetProjectQueryError = ResponseProblem;
+
+export function useGetProject<
+  TData = Awaited<ReturnType<typeof getProject>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProject>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProject>>,
+          TError,
+          Awaited<ReturnType<typeof getProject>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProject<
+  TData = Awaited<ReturnType<typeof getProject>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProject>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProject>>,
+          TError,
+          Awaited<ReturnType<typeof getProject>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProject<
+  TData = Awaited<ReturnType<typeof getProject>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProject>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get a project
+ */
+
+export function useGetProject<
+  TData = Awaited<ReturnType<typeof getProject>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProject>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getGetProjectQueryOptions(projectId, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Replaces the name, description and concurrency limit of the project. Omitted fields are reset to their defaults.
+ * @summary Replace a project
+ */
+export const updateProject = (
+  projectId: string,
+  modelUpdateProjectRequest: ModelUpdateProjectRequest,
+) => {
+  return customInstance<ModelProject>({
+    url: `/api/v2/projects/${projectId}`,
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    data: modelUpdateProjectRequest,
+  });
+};
+
+export const getUpdateProjectMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProject>>,
+    TError,
+    { projectId: string; data: ModelUpdateProjectRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof updateProject>>,
+  TError,
+  { projectId: string; data: ModelUpdateProjectRequest },
+  TContext
+> => {
+  const mutationKey = ["updateProject"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof updateProject>>,
+    { projectId: string; data: ModelUpdateProjectRequest }
+  > = (props) => {
+    const { projectId, data } = props ?? {};
+
+    return updateProject(projectId, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type UpdateProjectMutationResult = NonNullable<
+  Awaited<ReturnType<typeof updateProject>>
+>;
+export type UpdateProjectMutationBody = ModelUpdateProjectRequest;
+export type UpdateProjectMutationError = ResponseProblem;
+
+/**
+ * @summary Replace a project
+ */
+export const useUpdateProject = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProject>>,
+    TError,
+    { projectId: string; data: ModelUpdateProjectRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof updateProject>>,
+  TError,
+  { projectId: string; data: ModelUpdateProjectRequest },
+  TContext
+> => {
+  const mutationOptions = getUpdateProjectMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes a project that has no tasks left. The default project can't be deleted.
+ * @summary Delete a project
+ */
+export const deleteProject = (projectId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/projects/${projectId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteProjectMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProject>>,
+    TError,
+    { projectId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteProject>>,
+  TError,
+  { projectId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteProject"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteProject>>,
+    { projectId: string }
+  > = (props) => {
+    const { projectId } = props ?? {};
+
+    return deleteProject(projectId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteProjectMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteProject>>
+>;
+
+export type DeleteProjectMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a project
+ */
+export const useDeleteProject = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProject>>,
+    TError,
+    { projectId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteProject>>,
+  TError,
+  { projectId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteProjectMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the tasks of the project, with their labels. With label, only tasks carrying every one of the named labels are listed, or any of them with match=any.
+ * @summary List the tasks of a project
+ */
+export const listProjectTasks = (
+  projectId: string,
+  params?: ListProjectTasksParams,
+  signal?: AbortSignal,
+) => {
+  return customInstance<V2TaskList>({
+    url: `/api/v2/projects/${projectId}/tasks`,
+    method: "GET",
+    params,
+    signal,
+  });
+};
+
+export const getListProjectTasksQueryKey = (
+  projectId: string,
+  params?: ListProjectTasksParams,
+) => {
+  return [
+    `/api/v2/projects/${projectId}/tasks`,
+    ...(params ? [params] : []),
+  ] as const;
+};
+
+export const getListProjectTasksQueryOptions = <
+  TData = Awaited<ReturnType<typeof listProjectTasks>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  params?: ListProjectTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectTasks>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getListProjectTasksQueryKey(projectId, params);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof listProjectTasks>>
+  > = ({ signal }) => listProjectTasks(projectId, params, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!projectId,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listProjectTasks>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListProjectTasksQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listProjectTasks>>
+>;
+export type ListProjectTasksQueryError = ResponseProblem;
+
+export function useListProjectTasks<
+  TData = Awaited<ReturnType<typeof listProjectTasks>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  params: undefined | ListProjectTasksParams,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectTasks>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectTasks>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectTasks<
+  TData = Awaited<ReturnType<typeof listProjectTasks>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  params?: ListProjectTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectTasks>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectTasks>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectTasks<
+  TData = Awaited<ReturnType<typeof listProjectTasks>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  params?: ListProjectTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectTasks>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List the tasks of a project
+ */
+
+export function useListProjectTasks<
+  TData = Awaited<ReturnType<typeof listProjectTasks>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  params?: ListProjectTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectTasks>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListProjectTasksQueryOptions(
+    projectId,
+    params,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Creates a task in the project and returns it, with its URL in Location. Send an Idempotency-Key to make retries safe: a retry with the same key and body gets the original response, marked with Idempotent-Replayed.
+ * @summary Create a task in a project
+ */
+export const createProjectTask = (
+  projectId: string,
+  modelTaskRequest: ModelTaskRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/projects/${projectId}/tasks`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelTaskRequest,
+    signal,
+  });
+};
+
+export const getCreateProjectTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectTask>>,
+    TError,
+    { projectId: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createProjectTask>>,
+  TError,
+  { projectId: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationKey = ["createProjectTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createProjectTask>>,
+    { projectId: string; data: ModelTaskRequest }
+  > = (props) => {
+    const { projectId, data } = props ?? {};
+
+    return createProjectTask(projectId, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateProjectTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createProjectTask>>
+>;
+export type CreateProjectTaskMutationBody = ModelTaskRequest;
+export type CreateProjectTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Create a task in a project
+ */
+export const useCreateProjectTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectTask>>,
+    TError,
+    { projectId: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createProjectTask>>,
+  TError,
+  { projectId: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateProjectTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves a task of the project by its unique identifier.
+ * @summary Get a task of a project
+ */
+export const getProjectTask = (
+  projectId: string,
+  id: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetProjectTaskQueryKey = (projectId: string, id: string) => {
+  return [`/api/v2/projects/${projectId}/tasks/${id}`] as const;
+};
+
+export const getGetProjectTaskQueryOptions = <
+  TData = Awaited<ReturnType<typeof getProjectTask>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProjectTask>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getGetProjectTaskQueryKey(projectId, id);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof getProjectTask>>> = ({
+    signal,
+  }) => getProjectTask(projectId, id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getProjectTask>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetProjectTaskQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getProjectTask>>
+>;
+export type GetProjectTaskQueryError = ResponseProblem;
+
+export function useGetProjectTask<
+  TData = Awaited<ReturnType<typeof getProjectTask>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProjectTask>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProjectTask>>,
+          TError,
+          Awaited<ReturnType<typeof getProjectTask>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProjectTask<
+  TData = Awaited<ReturnType<typeof getProjectTask>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProjectTask>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProjectTask>>,
+          TError,
+          Awaited<ReturnType<typeof getProjectTask>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProjectTask<
+  TData = Awaited<ReturnType<typeof getProjectTask>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProjectTask>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get a task of a project
+ */
+
+export function useGetProjectTask<
+  TData = Awaited<ReturnType<typeof getProjectTask>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getProjectTask>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getGetProjectTaskQueryOptions(projectId, id, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Replaces all writable fields of the task of the project identified by its ID. Omitted fields are reset to their defaults.
+ * @summary Replace a task of a project
+ */
+export const updateProjectTask = (
+  projectId: string,
+  id: string,
+  modelTaskRequest: ModelTaskRequest,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}`,
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    data: modelTaskRequest,
+  });
+};
+
+export const getUpdateProjectTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProjectTask>>,
+    TError,
+    { projectId: string; id: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof updateProjectTask>>,
+  TError,
+  { projectId: string; id: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationKey = ["updateProjectTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof updateProjectTask>>,
+    { projectId: string; id: string; data: ModelTaskRequest }
+  > = (props) => {
+    const { projectId, id, data } = props ?? {};
+
+    return updateProjectTask(projectId, id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type UpdateProjectTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof updateProjectTask>>
+>;
+export type UpdateProjectTaskMutationBody = ModelTaskRequest;
+export type UpdateProjectTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Replace a task of a project
+ */
+export const useUpdateProjectTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProjectTask>>,
+    TError,
+    { projectId: string; id: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof updateProjectTask>>,
+  TError,
+  { projectId: string; id: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationOptions = getUpdateProjectTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Applies an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON patch (application/json-patch+json) to the task of the project identified by its ID. Changes to project_id are ignored.
+ * @summary Patch a task of a project
+ */
+export const patchProjectTask = (
+  projectId: string,
+  id: string,
+  patchProjectTaskBody: PatchProjectTaskBody,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}`,
+    method: "PATCH",
+    headers: { "Content-Type": "application/merge-patch+json" },
+    data: patchProjectTaskBody,
+  });
+};
+
+export const getPatchProjectTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof patchProjectTask>>,
+    TError,
+    { projectId: string; id: string; data: PatchProjectTaskBody },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof patchProjectTask>>,
+  TError,
+  { projectId: string; id: string; data: PatchProjectTaskBody },
+  TContext
+> => {
+  const mutationKey = ["patchProjectTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof patchProjectTask>>,
+    { projectId: string; id: string; data: PatchProjectTaskBody }
+  > = (props) => {
+    const { projectId, id, data } = props ?? {};
+
+    return patchProjectTask(projectId, id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type PatchProjectTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof patchProjectTask>>
+>;
+export type PatchProjectTaskMutationBody = PatchProjectTaskBody;
+export type PatchProjectTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Patch a task of a project
+ */
+export const usePatchProjectTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof patchProjectTask>>,
+    TError,
+    { projectId: string; id: string; data: PatchProjectTaskBody },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof patchProjectTask>>,
+  TError,
+  { projectId: string; id: string; data: PatchProjectTaskBody },
+  TContext
+> => {
+  const mutationOptions = getPatchProjectTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes the task of the project identified by its ID.
+ * @summary Delete a task of a project
+ */
+export const deleteProjectTask = (projectId: string, id: string) => {
+  return customInstance<void>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteProjectTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectTask>>,
+    TError,
+    { projectId: string; id: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteProjectTask>>,
+  TError,
+  { projectId: string; id: string },
+  TContext
+> => {
+  const mutationKey = ["deleteProjectTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteProjectTask>>,
+    { projectId: string; id: string }
+  > = (props) => {
+    const { projectId, id } = props ?? {};
+
+    return deleteProjectTask(projectId, id);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteProjectTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteProjectTask>>
+>;
+
+export type DeleteProjectTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a task of a project
+ */
+export const useDeleteProjectTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectTask>>,
+    TError,
+    { projectId: string; id: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteProjectTask>>,
+  TError,
+  { projectId: string; id: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteProjectTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the history of the task of the project, oldest first: its creation, status changes and comments. Comment entries carry the comment as it is now, or none once it has been deleted. Imports add a creation entry for the tasks they create, and a status change for the tasks whose status they change.
+ * @summary Get the activity feed of a task of a project
+ */
+export const listProjectActivity = (
+  projectId: string,
+  id: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<V2ActivityList>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/activity`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListProjectActivityQueryKey = (
+  projectId: string,
+  id: string,
+) => {
+  return [`/api/v2/projects/${projectId}/tasks/${id}/activity`] as const;
+};
+
+export const getListProjectActivityQueryOptions = <
+  TData = Awaited<ReturnType<typeof listProjectActivity>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectActivity>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getListProjectActivityQueryKey(projectId, id);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof listProjectActivity>>
+  > = ({ signal }) => listProjectActivity(projectId, id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listProjectActivity>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListProjectActivityQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listProjectActivity>>
+>;
+export type ListProjectActivityQueryError = ResponseProblem;
+
+export function useListProjectActivity<
+  TData = Awaited<ReturnType<typeof listProjectActivity>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectActivity>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectActivity>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectActivity>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectActivity<
+  TData = Awaited<ReturnType<typeof listProjectActivity>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectActivity>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectActivity>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectActivity>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectActivity<
+  TData = Awaited<ReturnType<typeof listProjectActivity>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectActivity>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get the activity feed of a task of a project
+ */
+
+export function useListProjectActivity<
+  TData = Awaited<ReturnType<typeof listProjectActivity>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectActivity>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListProjectActivityQueryOptions(
+    projectId,
+    id,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Retrieves the metadata of the files attached to the task of the project, oldest first.
+ * @summary List the attachments of a task of a project
+ */
+export const listProjectAttachments = (
+  projectId: string,
+  id: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<V2AttachmentList>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/attachments`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListProjectAttachmentsQueryKey = (
+  projectId: string,
+  id: string,
+) => {
+  return [`/api/v2/projects/${projectId}/tasks/${id}/attachments`] as const;
+};
+
+export const getListProjectAttachmentsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listProjectAttachments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getListProjectAttachmentsQueryKey(projectId, id);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof listProjectAttachments>>
+  > = ({ signal }) => listProjectAttachments(projectId, id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listProjectAttachments>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListProjectAttachmentsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listProjectAttachments>>
+>;
+export type ListProjectAttachmentsQueryError = ResponseProblem;
+
+export function useListProjectAttachments<
+  TData = Awaited<ReturnType<typeof listProjectAttachments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectAttachments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectAttachments>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectAttachments>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectAttachments<
+  TData = Awaited<ReturnType<typeof listProjectAttachments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectAttachments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectAttachments>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectAttachments>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectAttachments<
+  TData = Awaited<ReturnType<typeof listProjectAttachments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List the attachments of a task of a project
+ */
+
+export function useListProjectAttachments<
+  TData = Awaited<ReturnType<typeof listProjectAttachments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListProjectAttachmentsQueryOptions(
+    projectId,
+    id,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Streams the file in the file field of a multipart/form-data body to storage. The content type is detected from the contents and must be one of the accepted types; the SHA-256 digest is computed on the way. Idempotency-Key is not honoured here.
+ * @summary Attach a file to a task of a project
+ */
+export const createProjectAttachment = (
+  projectId: string,
+  id: string,
+  createProjectAttachmentBody: CreateProjectAttachmentBody,
+  signal?: AbortSignal,
+) => {
+  const formData = new FormData();
+  formData.append("file", createProjectAttachmentBody.file);
+
+  return customInstance<ModelAttachment>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/attachments`,
+    method: "POST",
+    headers: { "Content-Type": "multipart/form-data" },
+    data: formData,
+    signal,
+  });
+};
+
+export const getCreateProjectAttachmentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectAttachment>>,
+    TError,
+    { projectId: string; id: string; data: CreateProjectAttachmentBody },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createProjectAttachment>>,
+  TError,
+  { projectId: string; id: string; data: CreateProjectAttachmentBody },
+  TContext
+> => {
+  const mutationKey = ["createProjectAttachment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createProjectAttachment>>,
+    { projectId: string; id: string; data: CreateProjectAttachmentBody }
+  > = (props) => {
+    const { projectId, id, data } = props ?? {};
+
+    return createProjectAttachment(projectId, id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateProjectAttachmentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createProjectAttachment>>
+>;
+export type CreateProjectAttachmentMutationBody = CreateProjectAttachmentBody;
+export type CreateProjectAttachmentMutationError = ResponseProblem;
+
+/**
+ * @summary Attach a file to a task of a project
+ */
+export const useCreateProjectAttachment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectAttachment>>,
+    TError,
+    { projectId: string; id: string; data: CreateProjectAttachmentBody },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createProjectAttachment>>,
+  TError,
+  { projectId: string; id: string; data: CreateProjectAttachmentBody },
+  TContext
+> => {
+  const mutationOptions = getCreateProjectAttachmentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the metadata of a file attached to the task of the project: its name, detected content type, size and SHA-256 digest.
+ * @summary Get an attachment of a task of a project
+ */
+export const getProjectAttachment = (
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelAttachment>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/attachments/${attachmentId}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetProjectAttachmentQueryKey = (
+  projectId: string,
+  id: string,
+  attachmentId: string,
+) => {
+  return [
+    `/api/v2/projects/${projectId}/tasks/${id}/attachments/${attachmentId}`,
+  ] as const;
+};
+
+export const getGetProjectAttachmentQueryOptions = <
+  TData = Awaited<ReturnType<typeof getProjectAttachment>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof getProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getGetProjectAttachmentQueryKey(projectId, id, attachmentId);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof getProjectAttachment>>
+  > = ({ signal }) => getProjectAttachment(projectId, id, attachmentId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id && attachmentId),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getProjectAttachment>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetProjectAttachmentQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getProjectAttachment>>
+>;
+export type GetProjectAttachmentQueryError = ResponseProblem;
+
+export function useGetProjectAttachment<
+  TData = Awaited<ReturnType<typeof getProjectAttachment>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof getProjectAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProjectAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof getProjectAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProjectAttachment<
+  TData = Awaited<ReturnType<typeof getProjectAttachment>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof getProjectAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getProjectAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof getProjectAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetProjectAttachment<
+  TData = Awaited<ReturnType<typeof getProjectAttachment>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof getProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get an attachment of a task of a project
+ */
+
+export function useGetProjectAttachment<
+  TData = Awaited<ReturnType<typeof getProjectAttachment>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof getProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getGetProjectAttachmentQueryOptions(
+    projectId,
+    id,
+    attachmentId,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Deletes a file attached to the task of the project, contents and metadata.
+ * @summary Delete an attachment of a task of a project
+ */
+export const deleteProjectAttachment = (
+  projectId: string,
+  id: string,
+  attachmentId: string,
+) => {
+  return customInstance<void>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/attachments/${attachmentId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteProjectAttachmentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectAttachment>>,
+    TError,
+    { projectId: string; id: string; attachmentId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteProjectAttachment>>,
+  TError,
+  { projectId: string; id: string; attachmentId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteProjectAttachment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteProjectAttachment>>,
+    { projectId: string; id: string; attachmentId: string }
+  > = (props) => {
+    const { projectId, id, attachmentId } = props ?? {};
+
+    return deleteProjectAttachment(projectId, id, attachmentId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteProjectAttachmentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteProjectAttachment>>
+>;
+
+export type DeleteProjectAttachmentMutationError = ResponseProblem;
+
+/**
+ * @summary Delete an attachment of a task of a project
+ */
+export const useDeleteProjectAttachment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectAttachment>>,
+    TError,
+    { projectId: string; id: string; attachmentId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteProjectAttachment>>,
+  TError,
+  { projectId: string; id: string; attachmentId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteProjectAttachmentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Streams the file with its detected content type. Range requests are supported, as are If-None-Match and If-Range against the ETag, which is the SHA-256 digest. Images are shown inline; other types are downloaded.
+ * @summary Download an attachment of a task of a project
+ */
+export const downloadProjectAttachment = (
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<Blob>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/attachments/${attachmentId}/content`,
+    method: "GET",
+    responseType: "blob",
+    signal,
+  });
+};
+
+export const getDownloadProjectAttachmentQueryKey = (
+  projectId: string,
+  id: string,
+  attachmentId: string,
+) => {
+  return [
+    `/api/v2/projects/${projectId}/tasks/${id}/attachments/${attachmentId}/content`,
+  ] as const;
+};
+
+export const getDownloadProjectAttachmentQueryOptions = <
+  TData = Awaited<ReturnType<typeof downloadProjectAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getDownloadProjectAttachmentQueryKey(projectId, id, attachmentId);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof downloadProjectAttachment>>
+  > = ({ signal }) => downloadProjectAttachment(projectId, id, attachmentId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id && attachmentId),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof downloadProjectAttachment>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type DownloadProjectAttachmentQueryResult = NonNullable<
+  Awaited<ReturnType<typeof downloadProjectAttachment>>
+>;
+export type DownloadProjectAttachmentQueryError = ResponseProblem | string;
+
+export function useDownloadProjectAttachment<
+  TData = Awaited<ReturnType<typeof downloadProjectAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadProjectAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof downloadProjectAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof downloadProjectAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useDownloadProjectAttachment<
+  TData = Awaited<ReturnType<typeof downloadProjectAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadProjectAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof downloadProjectAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof downloadProjectAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useDownloadProjectAttachment<
+  TData = Awaited<ReturnType<typeof downloadProjectAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Download an attachment of a task of a project
+ */
+
+export function useDownloadProjectAttachment<
+  TData = Awaited<ReturnType<typeof downloadProjectAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  projectId: string,
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadProjectAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getDownloadProjectAttachmentQueryOptions(
+    projectId,
+    id,
+    attachmentId,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Retrieves the comments on the task of the project, oldest first.
+ * @summary List the comments on a task of a project
+ */
+export const listProjectComments = (
+  projectId: string,
+  id: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<V2CommentList>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/comments`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListProjectCommentsQueryKey = (
+  projectId: string,
+  id: string,
+) => {
+  return [`/api/v2/projects/${projectId}/tasks/${id}/comments`] as const;
+};
+
+export const getListProjectCommentsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listProjectComments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectComments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getListProjectCommentsQueryKey(projectId, id);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof listProjectComments>>
+  > = ({ signal }) => listProjectComments(projectId, id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(projectId && id),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listProjectComments>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListProjectCommentsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listProjectComments>>
+>;
+export type ListProjectCommentsQueryError = ResponseProblem;
+
+export function useListProjectComments<
+  TData = Awaited<ReturnType<typeof listProjectComments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectComments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectComments>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectComments>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectComments<
+  TData = Awaited<ReturnType<typeof listProjectComments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectComments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listProjectComments>>,
+          TError,
+          Awaited<ReturnType<typeof listProjectComments>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListProjectComments<
+  TData = Awaited<ReturnType<typeof listProjectComments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectComments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List the comments on a task of a project
+ */
+
+export function useListProjectComments<
+  TData = Awaited<ReturnType<typeof listProjectComments>>,
+  TError = ResponseProblem,
+>(
+  projectId: string,
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listProjectComments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListProjectCommentsQueryOptions(
+    projectId,
+    id,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Adds a comment to the task of the project and to its activity feed. The body is markdown.
+ * @summary Comment on a task of a project
+ */
+export const createProjectComment = (
+  projectId: string,
+  id: string,
+  modelCreateCommentRequest: ModelCreateCommentRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelComment>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/comments`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelCreateCommentRequest,
+    signal,
+  });
+};
+
+export const getCreateProjectCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectComment>>,
+    TError,
+    { projectId: string; id: string; data: ModelCreateCommentRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createProjectComment>>,
+  TError,
+  { projectId: string; id: string; data: ModelCreateCommentRequest },
+  TContext
+> => {
+  const mutationKey = ["createProjectComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createProjectComment>>,
+    { projectId: string; id: string; data: ModelCreateCommentRequest }
+  > = (props) => {
+    const { projectId, id, data } = props ?? {};
+
+    return createProjectComment(projectId, id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateProjectCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createProjectComment>>
+>;
+export type CreateProjectCommentMutationBody = ModelCreateCommentRequest;
+export type CreateProjectCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Comment on a task of a project
+ */
+export const useCreateProjectComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createProjectComment>>,
+    TError,
+    { projectId: string; id: string; data: ModelCreateCommentRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createProjectComment>>,
+  TError,
+  { projectId: string; id: string; data: ModelCreateCommentRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateProjectCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Replaces the body of a comment on the task of the project and marks it as edited.
+ * @summary Edit a comment on a task of a project
+ */
+export const updateProjectComment = (
+  projectId: string,
+  id: string,
+  commentId: string,
+  modelUpdateCommentRequest: ModelUpdateCommentRequest,
+) => {
+  return customInstance<ModelComment>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/comments/${commentId}`,
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    data: modelUpdateCommentRequest,
+  });
+};
+
+export const getUpdateProjectCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProjectComment>>,
+    TError,
+    { projectId: string; id: string; commentId: string; data: ModelUpdateCommentRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof updateProjectComment>>,
+  TError,
+  { projectId: string; id: string; commentId: string; data: ModelUpdateCommentRequest },
+  TContext
+> => {
+  const mutationKey = ["updateProjectComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof updateProjectComment>>,
+    { projectId: string; id: string; commentId: string; data: ModelUpdateCommentRequest }
+  > = (props) => {
+    const { projectId, id, commentId, data } = props ?? {};
+
+    return updateProjectComment(projectId, id, commentId, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type UpdateProjectCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof updateProjectComment>>
+>;
+export type UpdateProjectCommentMutationBody = ModelUpdateCommentRequest;
+export type UpdateProjectCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Edit a comment on a task of a project
+ */
+export const useUpdateProjectComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateProjectComment>>,
+    TError,
+    { projectId: string; id: string; commentId: string; data: ModelUpdateCommentRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof updateProjectComment>>,
+  TError,
+  { projectId: string; id: string; commentId: string; data: ModelUpdateCommentRequest },
+  TContext
+> => {
+  const mutationOptions = getUpdateProjectCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes a comment on the task of the project. Its entry stays in the activity feed, without the comment.
+ * @summary Delete a comment on a task of a project
+ */
+export const deleteProjectComment = (
+  projectId: string,
+  id: string,
+  commentId: string,
+) => {
+  return customInstance<void>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/comments/${commentId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteProjectCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectComment>>,
+    TError,
+    { projectId: string; id: string; commentId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteProjectComment>>,
+  TError,
+  { projectId: string; id: string; commentId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteProjectComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteProjectComment>>,
+    { projectId: string; id: string; commentId: string }
+  > = (props) => {
+    const { projectId, id, commentId } = props ?? {};
+
+    return deleteProjectComment(projectId, id, commentId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteProjectCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteProjectComment>>
+>;
+
+export type DeleteProjectCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a comment on a task of a project
+ */
+export const useDeleteProjectComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteProjectComment>>,
+    TError,
+    { projectId: string; id: string; commentId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteProjectComment>>,
+  TError,
+  { projectId: string; id: string; commentId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteProjectCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Puts the label on the task of the project and returns the task with its labels. Attaching a label the task already carries changes nothing.
+ * @summary Attach a label to a task of a project
+ */
+export const attachProjectLabel = (
+  projectId: string,
+  id: string,
+  labelId: string,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/labels/${labelId}`,
+    method: "PUT",
+  });
+};
+
+export const getAttachProjectLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof attachProjectLabel>>,
+    TError,
+    { projectId: string; id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof attachProjectLabel>>,
+  TError,
+  { projectId: string; id: string; labelId: string },
+  TContext
+> => {
+  const mutationKey = ["attachProjectLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof attachProjectLabel>>,
+    { projectId: string; id: string; labelId: string }
+  > = (props) => {
+    const { projectId, id, labelId } = props ?? {};
+
+    return attachProjectLabel(projectId, id, labelId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type AttachProjectLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof attachProjectLabel>>
+>;
+
+export type AttachProjectLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Attach a label to a task of a project
+ */
+export const useAttachProjectLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof attachProjectLabel>>,
+    TError,
+    { projectId: string; id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof attachProjectLabel>>,
+  TError,
+  { projectId: string; id: string; labelId: string },
+  TContext
+> => {
+  const mutationOptions = getAttachProjectLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Takes the label off the task of the project. The label itself is kept.
+ * @summary Detach a label from a task of a project
+ */
+export const detachProjectLabel = (
+  projectId: string,
+  id: string,
+  labelId: string,
+) => {
+  return customInstance<void>({
+    url: `/api/v2/projects/${projectId}/tasks/${id}/labels/${labelId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDetachProjectLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof detachProjectLabel>>,
+    TError,
+    { projectId: string; id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof detachProjectLabel>>,
+  TError,
+  { projectId: string; id: string; labelId: string },
+  TContext
+> => {
+  const mutationKey = ["detachProjectLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof detachProjectLabel>>,
+    { projectId: string; id: string; labelId: string }
+  > = (props) => {
+    const { projectId, id, labelId } = props ?? {};
+
+    return detachProjectLabel(projectId, id, labelId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DetachProjectLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof detachProjectLabel>>
+>;
+
+export type DetachProjectLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Detach a label from a task of a project
+ */
+export const useDetachProjectLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof detachProjectLabel>>,
+    TError,
+    { projectId: string; id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof detachProjectLabel>>,
+  TError,
+  { projectId: string; id: string; labelId: string },
+  TContext
+> => {
+  const mutationOptions = getDetachProjectLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the tasks of the default project, with their labels. With label, only tasks carrying every one of the named labels are listed, or any of them with match=any.
+ * @summary List all tasks
+ */
+export const listTasks = (params?: ListTasksParams, signal?: AbortSignal) => {
+  return customInstance<V2TaskList>({
+    url: `/api/v2/tasks`,
+    method: "GET",
+    params,
+    signal,
+  });
+};
+
+export const getListTasksQueryKey = (params?: ListTasksParams) => {
+  return [`/api/v2/tasks`, ...(params ? [params] : [])] as const;
+};
+
+export const getListTasksQueryOptions = <
+  TData = Awaited<ReturnType<typeof listTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ListTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListTasksQueryKey(params);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listTasks>>> = ({
+    signal,
+  }) => listTasks(params, signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof listTasks>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListTasksQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listTasks>>
+>;
+export type ListTasksQueryError = ResponseProblem;
+
+export function useListTasks<
+  TData = Awaited<ReturnType<typeof listTasks>>,
+  TError = ResponseProblem,
+>(
+  params: undefined | ListTasksParams,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listTasks>>,
+          TError,
+          Awaited<ReturnType<typeof listTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListTasks<
+  TData = Awaited<ReturnType<typeof listTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ListTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listTasks>>,
+          TError,
+          Awaited<ReturnType<typeof listTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListTasks<
+  TData = Awaited<ReturnType<typeof listTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ListTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
  * @summary List all tasks
  */
-export const listTasks = (signal?: AbortSignal) => {
-  return customInstance<ListTasks200>({ url: `/tasks`, method: "GET", signal });
+
+export function useListTasks<
+  TData = Awaited<ReturnType<typeof listTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ListTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListTasksQueryOptions(params, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Creates a task in the default project and returns it, with its URL in Location. Send an Idempotency-Key to make retries safe: a retry with the same key and body gets the original response, marked with Idempotent-Replayed.
+ * @summary Create a new task
+ */
+export const createTask = (
+  modelTaskRequest: ModelTaskRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/tasks`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelTaskRequest,
+    signal,
+  });
+};
+
+export const getCreateTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createTask>>,
+    TError,
+    { data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createTask>>,
+  TError,
+  { data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationKey = ["createTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createTask>>,
+    { data: ModelTaskRequest }
+  > = (props) => {
+    const { data } = props ?? {};
+
+    return createTask(data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createTask>>
+>;
+export type CreateTaskMutationBody = ModelTaskRequest;
+export type CreateTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Create a new task
+ */
+export const useCreateTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createTask>>,
+    TError,
+    { data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createTask>>,
+  TError,
+  { data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Runs a batch of create, update, delete and status operations on the tasks of the default project in a single transaction. With atomic set, any failure rolls back the whole batch; otherwise each operation succeeds or fails on its own.
+ * @summary Run bulk task operations
+ */
+export const bulkTasks = (
+  serviceBulkRequest: ServiceBulkRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<HandlerBulkResponse>({
+    url: `/api/v2/tasks/bulk`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: serviceBulkRequest,
+    signal,
+  });
+};
+
+export const getBulkTasksMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof bulkTasks>>,
+    TError,
+    { data: ServiceBulkRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof bulkTasks>>,
+  TError,
+  { data: ServiceBulkRequest },
+  TContext
+> => {
+  const mutationKey = ["bulkTasks"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof bulkTasks>>,
+    { data: ServiceBulkRequest }
+  > = (props) => {
+    const { data } = props ?? {};
+
+    return bulkTasks(data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type BulkTasksMutationResult = NonNullable<
+  Awaited<ReturnType<typeof bulkTasks>>
+>;
+export type BulkTasksMutationBody = ServiceBulkRequest;
+export type BulkTasksMutationError = ResponseProblem;
+
+/**
+ * @summary Run bulk task operations
+ */
+export const useBulkTasks = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof bulkTasks>>,
+    TError,
+    { data: ServiceBulkRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof bulkTasks>>,
+  TError,
+  { data: ServiceBulkRequest },
+  TContext
+> => {
+  const mutationOptions = getBulkTasksMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Streams every task of the default project, including IDs and timestamps, as a JSON array, CSV or newline-delimited JSON.
+ * @summary Export tasks
+ */
+export const exportTasks = (
+  params?: ExportTasksParams,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelTask[]>({
+    url: `/api/v2/tasks/export`,
+    method: "GET",
+    params,
+    signal,
+  });
+};
+
+export const getExportTasksQueryKey = (params?: ExportTasksParams) => {
+  return [`/api/v2/tasks/export`, ...(params ? [params] : [])] as const;
+};
+
+export const getExportTasksQueryOptions = <
+  TData = Awaited<ReturnType<typeof exportTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ExportTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof exportTasks>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getExportTasksQueryKey(params);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof exportTasks>>> = ({
+    signal,
+  }) => exportTasks(params, signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof exportTasks>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ExportTasksQueryResult = NonNullable<
+  Awaited<ReturnType<typeof exportTasks>>
+>;
+export type ExportTasksQueryError = ResponseProblem;
+
+export function useExportTasks<
+  TData = Awaited<ReturnType<typeof exportTasks>>,
+  TError = ResponseProblem,
+>(
+  params: undefined | ExportTasksParams,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof exportTasks>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof exportTasks>>,
+          TError,
+          Awaited<ReturnType<typeof exportTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useExportTasks<
+  TData = Awaited<ReturnType<typeof exportTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ExportTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof exportTasks>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof exportTasks>>,
+          TError,
+          Awaited<ReturnType<typeof exportTasks>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useExportTasks<
+  TData = Awaited<ReturnType<typeof exportTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ExportTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof exportTasks>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Export tasks
+ */
+
+export function useExportTasks<
+  TData = Awaited<ReturnType<typeof exportTasks>>,
+  TError = ResponseProblem,
+>(
+  params?: ExportTasksParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof exportTasks>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getExportTasksQueryOptions(params, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Loads tasks into the default project from a JSON array, CSV or newline-delimited JSON body, 500 tasks per transaction. A failed import keeps the tasks written before the failing record. IDs and timestamps are kept. The format defaults to the request content type.
+ * @summary Import tasks
+ */
+export const importTasks = (
+  modelTask: ModelTask[],
+  params?: ImportTasksParams,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ServiceImportSummary>({
+    url: `/api/v2/tasks/import`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelTask,
+    params,
+    signal,
+  });
+};
+
+export const getImportTasksMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof importTasks>>,
+    TError,
+    { data: ModelTask[]; params?: ImportTasksParams },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof importTasks>>,
+  TError,
+  { data: ModelTask[]; params?: ImportTasksParams },
+  TContext
+> => {
+  const mutationKey = ["importTasks"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof importTasks>>,
+    { data: ModelTask[]; params?: ImportTasksParams }
+  > = (props) => {
+    const { data, params } = props ?? {};
+
+    return importTasks(data, params);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type ImportTasksMutationResult = NonNullable<
+  Awaited<ReturnType<typeof importTasks>>
+>;
+export type ImportTasksMutationBody = ModelTask[];
+export type ImportTasksMutationError = ResponseProblem;
+
+/**
+ * @summary Import tasks
+ */
+export const useImportTasks = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof importTasks>>,
+    TError,
+    { data: ModelTask[]; params?: ImportTasksParams },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof importTasks>>,
+  TError,
+  { data: ModelTask[]; params?: ImportTasksParams },
+  TContext
+> => {
+  const mutationOptions = getImportTasksMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Claims every pending task of the default project not already being processed, queues it for the in-process workers and returns immediately. Tasks claimed by an earlier call are left out. Progress is published on /api/v2/events and /api/v2/ws.
+ * @summary Process pending tasks
+ */
+export const processTasks = (signal?: AbortSignal) => {
+  return customInstance<V2TaskList>({
+    url: `/api/v2/tasks/process`,
+    method: "POST",
+    signal,
+  });
+};
+
+export const getProcessTasksMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof processTasks>>,
+    TError,
+    void,
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof processTasks>>,
+  TError,
+  void,
+  TContext
+> => {
+  const mutationKey = ["processTasks"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof processTasks>>,
+    void
+  > = () => {
+    return processTasks();
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type ProcessTasksMutationResult = NonNullable<
+  Awaited<ReturnType<typeof processTasks>>
+>;
+
+export type ProcessTasksMutationError = ResponseProblem;
+
+/**
+ * @summary Process pending tasks
+ */
+export const useProcessTasks = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof processTasks>>,
+    TError,
+    void,
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof processTasks>>,
+  TError,
+  void,
+  TContext
+> => {
+  const mutationOptions = getProcessTasksMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves a task of the default project by its unique identifier.
+ * @summary Get a task
+ */
+export const getTaskByID = (id: string, signal?: AbortSignal) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/tasks/${id}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetTaskByIDQueryKey = (id: string) => {
+  return [`/api/v2/tasks/${id}`] as const;
+};
+
+export const getGetTaskByIDQueryOptions = <
+  TData = Awaited<ReturnType<typeof getTaskByID>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getGetTaskByIDQueryKey(id);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof getTaskByID>>> = ({
+    signal,
+  }) => getTaskByID(id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!id,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getTaskByID>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetTaskByIDQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getTaskByID>>
+>;
+export type GetTaskByIDQueryError = ResponseProblem;
+
+export function useGetTaskByID<
+  TData = Awaited<ReturnType<typeof getTaskByID>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getTaskByID>>,
+          TError,
+          Awaited<ReturnType<typeof getTaskByID>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetTaskByID<
+  TData = Awaited<ReturnType<typeof getTaskByID>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getTaskByID>>,
+          TError,
+          Awaited<ReturnType<typeof getTaskByID>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetTaskByID<
+  TData = Awaited<ReturnType<typeof getTaskByID>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get a task
+ */
+
+export function useGetTaskByID<
+  TData = Awaited<ReturnType<typeof getTaskByID>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getGetTaskByIDQueryOptions(id, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Replaces all writable fields of the task of the default project identified by its ID. Omitted fields are reset to their defaults.
+ * @summary Replace a task
+ */
+export const updateTask = (id: string, modelTaskRequest: ModelTaskRequest) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/tasks/${id}`,
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    data: modelTaskRequest,
+  });
+};
+
+export const getUpdateTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateTask>>,
+    TError,
+    { id: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof updateTask>>,
+  TError,
+  { id: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationKey = ["updateTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof updateTask>>,
+    { id: string; data: ModelTaskRequest }
+  > = (props) => {
+    const { id, data } = props ?? {};
+
+    return updateTask(id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type UpdateTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof updateTask>>
+>;
+export type UpdateTaskMutationBody = ModelTaskRequest;
+export type UpdateTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Replace a task
+ */
+export const useUpdateTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateTask>>,
+    TError,
+    { id: string; data: ModelTaskRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof updateTask>>,
+  TError,
+  { id: string; data: ModelTaskRequest },
+  TContext
+> => {
+  const mutationOptions = getUpdateTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Applies an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON patch (application/json-patch+json) to the task of the default project identified by its ID.
+ * @summary Patch a task
+ */
+export const patchTask = (id: string, patchTaskBody: PatchTaskBody) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/tasks/${id}`,
+    method: "PATCH",
+    headers: { "Content-Type": "application/merge-patch+json" },
+    data: patchTaskBody,
+  });
+};
+
+export const getPatchTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof patchTask>>,
+    TError,
+    { id: string; data: PatchTaskBody },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof patchTask>>,
+  TError,
+  { id: string; data: PatchTaskBody },
+  TContext
+> => {
+  const mutationKey = ["patchTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof patchTask>>,
+    { id: string; data: PatchTaskBody }
+  > = (props) => {
+    const { id, data } = props ?? {};
+
+    return patchTask(id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type PatchTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof patchTask>>
+>;
+export type PatchTaskMutationBody = PatchTaskBody;
+export type PatchTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Patch a task
+ */
+export const usePatchTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof patchTask>>,
+    TError,
+    { id: string; data: PatchTaskBody },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof patchTask>>,
+  TError,
+  { id: string; data: PatchTaskBody },
+  TContext
+> => {
+  const mutationOptions = getPatchTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes a task of the default project identified by its unique identifier.
+ * @summary Delete a task
+ */
+export const deleteTask = (id: string) => {
+  return customInstance<void>({
+    url: `/api/v2/tasks/${id}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteTaskMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteTask>>,
+    TError,
+    { id: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteTask>>,
+  TError,
+  { id: string },
+  TContext
+> => {
+  const mutationKey = ["deleteTask"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteTask>>,
+    { id: string }
+  > = (props) => {
+    const { id } = props ?? {};
+
+    return deleteTask(id);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteTaskMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteTask>>
+>;
+
+export type DeleteTaskMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a task
+ */
+export const useDeleteTask = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteTask>>,
+    TError,
+    { id: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteTask>>,
+  TError,
+  { id: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteTaskMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the history of the task, oldest first: its creation, status changes and comments. Comment entries carry the comment as it is now, or none once it has been deleted. Imports add a creation entry for the tasks they create, and a status change for the tasks whose status they change.
+ * @summary Get the activity feed of a task
+ */
+export const listActivity = (id: string, signal?: AbortSignal) => {
+  return customInstance<V2ActivityList>({
+    url: `/api/v2/tasks/${id}/activity`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListActivityQueryKey = (id: string) => {
+  return [`/api/v2/tasks/${id}/activity`] as const;
+};
+
+export const getListActivityQueryOptions = <
+  TData = Awaited<ReturnType<typeof listActivity>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listActivity>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListActivityQueryKey(id);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listActivity>>> = ({
+    signal,
+  }) => listActivity(id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!id,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listActivity>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListActivityQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listActivity>>
+>;
+export type ListActivityQueryError = ResponseProblem;
+
+export function useListActivity<
+  TData = Awaited<ReturnType<typeof listActivity>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listActivity>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listActivity>>,
+          TError,
+          Awaited<ReturnType<typeof listActivity>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListActivity<
+  TData = Awaited<ReturnType<typeof listActivity>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listActivity>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listActivity>>,
+          TError,
+          Awaited<ReturnType<typeof listActivity>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListActivity<
+  TData = Awaited<ReturnType<typeof listActivity>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listActivity>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get the activity feed of a task
+ */
+
+export function useListActivity<
+  TData = Awaited<ReturnType<typeof listActivity>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listActivity>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListActivityQueryOptions(id, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Retrieves the metadata of the files attached to the task, oldest first.
+ * @summary List the attachments of a task
+ */
+export const listAttachments = (id: string, signal?: AbortSignal) => {
+  return customInstance<V2AttachmentList>({
+    url: `/api/v2/tasks/${id}/attachments`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListAttachmentsQueryKey = (id: string) => {
+  return [`/api/v2/tasks/${id}/attachments`] as const;
+};
+
+export const getListAttachmentsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listAttachments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListAttachmentsQueryKey(id);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listAttachments>>> = ({
+    signal,
+  }) => listAttachments(id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!id,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listAttachments>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListAttachmentsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listAttachments>>
+>;
+export type ListAttachmentsQueryError = ResponseProblem;
+
+export function useListAttachments<
+  TData = Awaited<ReturnType<typeof listAttachments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listAttachments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listAttachments>>,
+          TError,
+          Awaited<ReturnType<typeof listAttachments>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListAttachments<
+  TData = Awaited<ReturnType<typeof listAttachments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listAttachments>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listAttachments>>,
+          TError,
+          Awaited<ReturnType<typeof listAttachments>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListAttachments<
+  TData = Awaited<ReturnType<typeof listAttachments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List the attachments of a task
+ */
+
+export function useListAttachments<
+  TData = Awaited<ReturnType<typeof listAttachments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listAttachments>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListAttachmentsQueryOptions(id, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Streams the file in the file field of a multipart/form-data body to storage. The content type is detected from the contents and must be one of the accepted types; the SHA-256 digest is computed on the way. Idempotency-Key is not honoured here.
+ * @summary Attach a file to a task
+ */
+export const createAttachment = (
+  id: string,
+  createAttachmentBody: CreateAttachmentBody,
+  signal?: AbortSignal,
+) => {
+  const formData = new FormData();
+  formData.append("file", createAttachmentBody.file);
+
+  return customInstance<ModelAttachment>({
+    url: `/api/v2/tasks/${id}/attachments`,
+    method: "POST",
+    headers: { "Content-Type": "multipart/form-data" },
+    data: formData,
+    signal,
+  });
+};
+
+export const getCreateAttachmentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createAttachment>>,
+    TError,
+    { id: string; data: CreateAttachmentBody },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createAttachment>>,
+  TError,
+  { id: string; data: CreateAttachmentBody },
+  TContext
+> => {
+  const mutationKey = ["createAttachment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createAttachment>>,
+    { id: string; data: CreateAttachmentBody }
+  > = (props) => {
+    const { id, data } = props ?? {};
+
+    return createAttachment(id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateAttachmentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createAttachment>>
+>;
+export type CreateAttachmentMutationBody = CreateAttachmentBody;
+export type CreateAttachmentMutationError = ResponseProblem;
+
+/**
+ * @summary Attach a file to a task
+ */
+export const useCreateAttachment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createAttachment>>,
+    TError,
+    { id: string; data: CreateAttachmentBody },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createAttachment>>,
+  TError,
+  { id: string; data: CreateAttachmentBody },
+  TContext
+> => {
+  const mutationOptions = getCreateAttachmentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves the metadata of a file attached to the task: its name, detected content type, size and SHA-256 digest.
+ * @summary Get an attachment
+ */
+export const getAttachment = (
+  id: string,
+  attachmentId: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelAttachment>({
+    url: `/api/v2/tasks/${id}/attachments/${attachmentId}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetAttachmentQueryKey = (id: string, attachmentId: string) => {
+  return [`/api/v2/tasks/${id}/attachments/${attachmentId}`] as const;
+};
+
+export const getGetAttachmentQueryOptions = <
+  TData = Awaited<ReturnType<typeof getAttachment>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getAttachment>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getGetAttachmentQueryKey(id, attachmentId);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof getAttachment>>> = ({
+    signal,
+  }) => getAttachment(id, attachmentId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(id && attachmentId),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getAttachment>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetAttachmentQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getAttachment>>
+>;
+export type GetAttachmentQueryError = ResponseProblem;
+
+export function useGetAttachment<
+  TData = Awaited<ReturnType<typeof getAttachment>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  attachmentId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getAttachment>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof getAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetAttachment<
+  TData = Awaited<ReturnType<typeof getAttachment>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getAttachment>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof getAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetAttachment<
+  TData = Awaited<ReturnType<typeof getAttachment>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getAttachment>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Get an attachment
+ */
+
+export function useGetAttachment<
+  TData = Awaited<ReturnType<typeof getAttachment>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getAttachment>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getGetAttachmentQueryOptions(id, attachmentId, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Deletes a file attached to the task, contents and metadata.
+ * @summary Delete an attachment
+ */
+export const deleteAttachment = (id: string, attachmentId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/tasks/${id}/attachments/${attachmentId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteAttachmentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteAttachment>>,
+    TError,
+    { id: string; attachmentId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteAttachment>>,
+  TError,
+  { id: string; attachmentId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteAttachment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteAttachment>>,
+    { id: string; attachmentId: string }
+  > = (props) => {
+    const { id, attachmentId } = props ?? {};
+
+    return deleteAttachment(id, attachmentId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteAttachmentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteAttachment>>
+>;
+
+export type DeleteAttachmentMutationError = ResponseProblem;
+
+/**
+ * @summary Delete an attachment
+ */
+export const useDeleteAttachment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteAttachment>>,
+    TError,
+    { id: string; attachmentId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteAttachment>>,
+  TError,
+  { id: string; attachmentId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteAttachmentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Streams the file with its detected content type. Range requests are supported, as are If-None-Match and If-Range against the ETag, which is the SHA-256 digest. Images are shown inline; other types are downloaded.
+ * @summary Download an attachment
+ */
+export const downloadAttachment = (
+  id: string,
+  attachmentId: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<Blob>({
+    url: `/api/v2/tasks/${id}/attachments/${attachmentId}/content`,
+    method: "GET",
+    responseType: "blob",
+    signal,
+  });
+};
+
+export const getDownloadAttachmentQueryKey = (
+  id: string,
+  attachmentId: string,
+) => {
+  return [`/api/v2/tasks/${id}/attachments/${attachmentId}/content`] as const;
+};
+
+export const getDownloadAttachmentQueryOptions = <
+  TData = Awaited<ReturnType<typeof downloadAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey =
+    queryOptions?.queryKey ?? getDownloadAttachmentQueryKey(id, attachmentId);
+
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof downloadAttachment>>
+  > = ({ signal }) => downloadAttachment(id, attachmentId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!(id && attachmentId),
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof downloadAttachment>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type DownloadAttachmentQueryResult = NonNullable<
+  Awaited<ReturnType<typeof downloadAttachment>>
+>;
+export type DownloadAttachmentQueryError = ResponseProblem | string;
+
+export function useDownloadAttachment<
+  TData = Awaited<ReturnType<typeof downloadAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  id: string,
+  attachmentId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof downloadAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof downloadAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useDownloadAttachment<
+  TData = Awaited<ReturnType<typeof downloadAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadAttachment>>,
+        TError,
+        TData
+      >
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof downloadAttachment>>,
+          TError,
+          Awaited<ReturnType<typeof downloadAttachment>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useDownloadAttachment<
+  TData = Awaited<ReturnType<typeof downloadAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary Download an attachment
+ */
+
+export function useDownloadAttachment<
+  TData = Awaited<ReturnType<typeof downloadAttachment>>,
+  TError = ResponseProblem | string,
+>(
+  id: string,
+  attachmentId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<
+        Awaited<ReturnType<typeof downloadAttachment>>,
+        TError,
+        TData
+      >
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getDownloadAttachmentQueryOptions(
+    id,
+    attachmentId,
+    options,
+  );
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Retrieves the comments on the task, oldest first.
+ * @summary List the comments on a task
+ */
+export const listComments = (id: string, signal?: AbortSignal) => {
+  return customInstance<V2CommentList>({
+    url: `/api/v2/tasks/${id}/comments`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListCommentsQueryKey = (id: string) => {
+  return [`/api/v2/tasks/${id}/comments`] as const;
+};
+
+export const getListCommentsQueryOptions = <
+  TData = Awaited<ReturnType<typeof listComments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listComments>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getListCommentsQueryKey(id);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listComments>>> = ({
+    signal,
+  }) => listComments(id, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!id,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof listComments>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type ListCommentsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listComments>>
+>;
+export type ListCommentsQueryError = ResponseProblem;
+
+export function useListComments<
+  TData = Awaited<ReturnType<typeof listComments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listComments>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listComments>>,
+          TError,
+          Awaited<ReturnType<typeof listComments>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListComments<
+  TData = Awaited<ReturnType<typeof listComments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listComments>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof listComments>>,
+          TError,
+          Awaited<ReturnType<typeof listComments>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListComments<
+  TData = Awaited<ReturnType<typeof listComments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listComments>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List the comments on a task
+ */
+
+export function useListComments<
+  TData = Awaited<ReturnType<typeof listComments>>,
+  TError = ResponseProblem,
+>(
+  id: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof listComments>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListCommentsQueryOptions(id, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Adds a comment to the task and to its activity feed. The body is markdown.
+ * @summary Comment on a task
+ */
+export const createComment = (
+  id: string,
+  modelCreateCommentRequest: ModelCreateCommentRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelComment>({
+    url: `/api/v2/tasks/${id}/comments`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: modelCreateCommentRequest,
+    signal,
+  });
+};
+
+export const getCreateCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createComment>>,
+    TError,
+    { id: string; data: ModelCreateCommentRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createComment>>,
+  TError,
+  { id: string; data: ModelCreateCommentRequest },
+  TContext
+> => {
+  const mutationKey = ["createComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createComment>>,
+    { id: string; data: ModelCreateCommentRequest }
+  > = (props) => {
+    const { id, data } = props ?? {};
+
+    return createComment(id, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createComment>>
+>;
+export type CreateCommentMutationBody = ModelCreateCommentRequest;
+export type CreateCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Comment on a task
+ */
+export const useCreateComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createComment>>,
+    TError,
+    { id: string; data: ModelCreateCommentRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createComment>>,
+  TError,
+  { id: string; data: ModelCreateCommentRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Replaces the body of a comment on the task and marks it as edited.
+ * @summary Edit a comment
+ */
+export const updateComment = (
+  id: string,
+  commentId: string,
+  modelUpdateCommentRequest: ModelUpdateCommentRequest,
+) => {
+  return customInstance<ModelComment>({
+    url: `/api/v2/tasks/${id}/comments/${commentId}`,
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    data: modelUpdateCommentRequest,
+  });
+};
+
+export const getUpdateCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateComment>>,
+    TError,
+    { id: string; commentId: string; data: ModelUpdateCommentRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof updateComment>>,
+  TError,
+  { id: string; commentId: string; data: ModelUpdateCommentRequest },
+  TContext
+> => {
+  const mutationKey = ["updateComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof updateComment>>,
+    { id: string; commentId: string; data: ModelUpdateCommentRequest }
+  > = (props) => {
+    const { id, commentId, data } = props ?? {};
+
+    return updateComment(id, commentId, data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type UpdateCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof updateComment>>
+>;
+export type UpdateCommentMutationBody = ModelUpdateCommentRequest;
+export type UpdateCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Edit a comment
+ */
+export const useUpdateComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof updateComment>>,
+    TError,
+    { id: string; commentId: string; data: ModelUpdateCommentRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof updateComment>>,
+  TError,
+  { id: string; commentId: string; data: ModelUpdateCommentRequest },
+  TContext
+> => {
+  const mutationOptions = getUpdateCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Deletes a comment on the task. Its entry stays in the activity feed, without the comment.
+ * @summary Delete a comment
+ */
+export const deleteComment = (id: string, commentId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/tasks/${id}/comments/${commentId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDeleteCommentMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteComment>>,
+    TError,
+    { id: string; commentId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof deleteComment>>,
+  TError,
+  { id: string; commentId: string },
+  TContext
+> => {
+  const mutationKey = ["deleteComment"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof deleteComment>>,
+    { id: string; commentId: string }
+  > = (props) => {
+    const { id, commentId } = props ?? {};
+
+    return deleteComment(id, commentId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DeleteCommentMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteComment>>
+>;
+
+export type DeleteCommentMutationError = ResponseProblem;
+
+/**
+ * @summary Delete a comment
+ */
+export const useDeleteComment = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof deleteComment>>,
+    TError,
+    { id: string; commentId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof deleteComment>>,
+  TError,
+  { id: string; commentId: string },
+  TContext
+> => {
+  const mutationOptions = getDeleteCommentMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Puts the label on the task and returns the task with its labels. Attaching a label the task already carries changes nothing.
+ * @summary Attach a label to a task
+ */
+export const attachLabel = (id: string, labelId: string) => {
+  return customInstance<ModelTask>({
+    url: `/api/v2/tasks/${id}/labels/${labelId}`,
+    method: "PUT",
+  });
 };
 
-export const getListTasksQueryKey = () => {
-  return [`/tasks`] as const;
+export const getAttachLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof attachLabel>>,
+    TError,
+    { id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof attachLabel>>,
+  TError,
+  { id: string; labelId: string },
+  TContext
+> => {
+  const mutationKey = ["attachLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof attachLabel>>,
+    { id: string; labelId: string }
+  > = (props) => {
+    const { id, labelId } = props ?? {};
+
+    return attachLabel(id, labelId);
+  };
+
+  return { mutationFn, ...mutationOptions };
 };
 
-export const getListTasksQueryOptions = <
-  TData = Awaited<ReturnType<typeof listTasks>>,
-  TError = TaskManagerInternalResponseResponse,
+export type AttachLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof attachLabel>>
+>;
+
+export type AttachLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Attach a label to a task
+ */
+export const useAttachLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof attachLabel>>,
+    TError,
+    { id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof attachLabel>>,
+  TError,
+  { id: string; labelId: string },
+  TContext
+> => {
+  const mutationOptions = getAttachLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Takes the label off the task. The label itself is kept.
+ * @summary Detach a label from a task
+ */
+export const detachLabel = (id: string, labelId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/tasks/${id}/labels/${labelId}`,
+    method: "DELETE",
+  });
+};
+
+export const getDetachLabelMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof detachLabel>>,
+    TError,
+    { id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof detachLabel>>,
+  TError,
+  { id: string; labelId: string },
+  TContext
+> => {
+  const mutationKey = ["detachLabel"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof detachLabel>>,
+    { id: string; labelId: string }
+  > = (props) => {
+    const { id, labelId } = props ?? {};
+
+    return detachLabel(id, labelId);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type DetachLabelMutationResult = NonNullable<
+  Awaited<ReturnType<typeof detachLabel>>
+>;
+
+export type DetachLabelMutationError = ResponseProblem;
+
+/**
+ * @summary Detach a label from a task
+ */
+export const useDetachLabel = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof detachLabel>>,
+    TError,
+    { id: string; labelId: string },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof detachLabel>>,
+  TError,
+  { id: string; labelId: string },
+  TContext
+> => {
+  const mutationOptions = getDetachLabelMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves every webhook, without its signing secret.
+ * @summary List webhooks
+ */
+export const listWebhooks = (signal?: AbortSignal) => {
+  return customInstance<V2WebhookList>({
+    url: `/api/v2/webhooks`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getListWebhooksQueryKey = () => {
+  return [`/api/v2/webhooks`] as const;
+};
+
+export const getListWebhooksQueryOptions = <
+  TData = Awaited<ReturnType<typeof listWebhooks>>,
+  TError = ResponseProblem,
 >(options?: {
   query?: Partial<
-    UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    UseQueryOptions<Awaited<ReturnType<typeof listWebhooks>>, TError, TData>
   >;
 }) => {
   const { query: queryOptions } = options ?? {};
 
-  const queryKey = queryOptions?.queryKey ?? getListTasksQueryKey();
+  const queryKey = queryOptions?.queryKey ?? getListWebhooksQueryKey();
 
-  const queryFn: QueryFunction<Awaited<ReturnType<typeof listTasks>>> = ({
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof listWebhooks>>> = ({
     signal,
-  }) => listTasks(signal);
+  }) => listWebhooks(signal);
 
   return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
-    Awaited<ReturnType<typeof listTasks>>,
+    Awaited<ReturnType<typeof listWebhooks>>,
     TError,
     TData
   > & { queryKey: DataTag<QueryKey, TData, TError> };
 };
 
-export type ListTasksQueryResult = NonNullable<
-  Awaited<ReturnType<typeof listTasks>>
+export type ListWebhooksQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listWebhooks>>
 >;
-export type ListTasksQueryError = TaskManagerInternalResponseResponse;
+export type ListWebhooksQueryError = ResponseProblem;
 
-export function useListTasks<
-  TData = Awaited<ReturnType<typeof listTasks>>,
-  TError = TaskManagerInternalResponseResponse,
+export function useListWebhooks<
+  TData = Awaited<ReturnType<typeof listWebhooks>>,
+  TError = ResponseProblem,
 >(options: {
   query: Partial<
-    UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
+    UseQueryOptions<Awaited<ReturnType<typeof listWebhooks>>, TError, TData>
   > &
     Pick<
       DefinedInitialDataOptions<
-        Awaited<ReturnType<typeof listTasks>>,
+        Awaited<ReturnType<typeof listWebhooks>>,
         TError,
-        Awaited<ReturnType<typeof listTasks>>
+        Awaited<ReturnType<typeof listWebhooks>>
       >,
       "initialData"
     >;
 }): DefinedUseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 };
-export function useListTasks<
-  TData = Awaited<ReturnType<typeof listTasks>>,
-  TError = TaskManagerInternalResponseResponse,
->(options?: {
-  query?: Partial<
-    UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
-  > &
-    Pick<
-      UndefinedInitialDataOptions<
-        Awaited<ReturnType<typeof listTasks>>,
-        TError,
-        Awaited<ReturnType<typeof listTasks>>
-      >,
-      "initialData"
+export function useListWebhooks<
+  TData = Awaited<ReturnType<typeof listWebhooks>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listWebhooks>>, TError, TData>
+  > &
+    Pick<
+      UndefinedInitialDataOptions<
+        Awaited<ReturnType<typeof listWebhooks>>,
+        TError,
+        Awaited<ReturnType<typeof listWebhooks>>
+      >,
+      "initialData"
+    >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useListWebhooks<
+  TData = Awaited<ReturnType<typeof listWebhooks>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listWebhooks>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+
+/**
+ * @summary List webhooks
+ */
+
+export function useListWebhooks<
+  TData = Awaited<ReturnType<typeof listWebhooks>>,
+  TError = ResponseProblem,
+>(options?: {
+  query?: Partial<
+    UseQueryOptions<Awaited<ReturnType<typeof listWebhooks>>, TError, TData>
+  >;
+}): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getListWebhooksQueryOptions(options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
+
+/**
+ * Subscribes a URL to task events. The signing secret is generated when omitted and is only returned in this response.
+ * @summary Create a webhook
+ */
+export const createWebhook = (
+  handlerWebhookRequest: HandlerWebhookRequest,
+  signal?: AbortSignal,
+) => {
+  return customInstance<ModelWebhook>({
+    url: `/api/v2/webhooks`,
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    data: handlerWebhookRequest,
+    signal,
+  });
+};
+
+export const getCreateWebhookMutationOptions = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createWebhook>>,
+    TError,
+    { data: HandlerWebhookRequest },
+    TContext
+  >;
+}): UseMutationOptions<
+  Awaited<ReturnType<typeof createWebhook>>,
+  TError,
+  { data: HandlerWebhookRequest },
+  TContext
+> => {
+  const mutationKey = ["createWebhook"];
+  const { mutation: mutationOptions } = options
+    ? options.mutation &&
+      "mutationKey" in options.mutation &&
+      options.mutation.mutationKey
+      ? options
+      : { ...options, mutation: { ...options.mutation, mutationKey } }
+    : { mutation: { mutationKey } };
+
+  const mutationFn: MutationFunction<
+    Awaited<ReturnType<typeof createWebhook>>,
+    { data: HandlerWebhookRequest }
+  > = (props) => {
+    const { data } = props ?? {};
+
+    return createWebhook(data);
+  };
+
+  return { mutationFn, ...mutationOptions };
+};
+
+export type CreateWebhookMutationResult = NonNullable<
+  Awaited<ReturnType<typeof createWebhook>>
+>;
+export type CreateWebhookMutationBody = HandlerWebhookRequest;
+export type CreateWebhookMutationError = ResponseProblem;
+
+/**
+ * @summary Create a webhook
+ */
+export const useCreateWebhook = <
+  TError = ResponseProblem,
+  TContext = unknown,
+>(options?: {
+  mutation?: UseMutationOptions<
+    Awaited<ReturnType<typeof createWebhook>>,
+    TError,
+    { data: HandlerWebhookRequest },
+    TContext
+  >;
+}): UseMutationResult<
+  Awaited<ReturnType<typeof createWebhook>>,
+  TError,
+  { data: HandlerWebhookRequest },
+  TContext
+> => {
+  const mutationOptions = getCreateWebhookMutationOptions(options);
+
+  return useMutation(mutationOptions);
+};
+
+/**
+ * Retrieves a webhook, without its signing secret.
+ * @summary Get a webhook
+ */
+export const getWebhook = (webhookId: string, signal?: AbortSignal) => {
+  return customInstance<ModelWebhook>({
+    url: `/api/v2/webhooks/${webhookId}`,
+    method: "GET",
+    signal,
+  });
+};
+
+export const getGetWebhookQueryKey = (webhookId: string) => {
+  return [`/api/v2/webhooks/${webhookId}`] as const;
+};
+
+export const getGetWebhookQueryOptions = <
+  TData = Awaited<ReturnType<typeof getWebhook>>,
+  TError = ResponseProblem,
+>(
+  webhookId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getWebhook>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
+
+  const queryKey = queryOptions?.queryKey ?? getGetWebhookQueryKey(webhookId);
+
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof getWebhook>>> = ({
+    signal,
+  }) => getWebhook(webhookId, signal);
+
+  return {
+    queryKey,
+    queryFn,
+    enabled: !!webhookId,
+    ...queryOptions,
+  } as UseQueryOptions<
+    Awaited<ReturnType<typeof getWebhook>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
+};
+
+export type GetWebhookQueryResult = NonNullable<
+  Awaited<ReturnType<typeof getWebhook>>
+>;
+export type GetWebhookQueryError = ResponseProblem;
+
+export function useGetWebhook<
+  TData = Awaited<ReturnType<typeof getWebhook>>,
+  TError = ResponseProblem,
+>(
+  webhookId: string,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getWebhook>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getWebhook>>,
+          TError,
+          Awaited<ReturnType<typeof getWebhook>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetWebhook<
+  TData = Awaited<ReturnType<typeof getWebhook>>,
+  TError = ResponseProblem,
+>(
+  webhookId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getWebhook>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof getWebhook>>,
+          TError,
+          Awaited<ReturnType<typeof getWebhook>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useGetWebhook<
+  TData = Awaited<ReturnType<typeof getWebhook>>,
+  TError = ResponseProblem,
+>(
+  webhookId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getWebhook>>, TError, TData>
     >;
-}): UseQueryResult<TData, TError> & {
-  queryKey: DataTag<QueryKey, TData, TError>;
-};
-export function useListTasks<
-  TData = Awaited<ReturnType<typeof listTasks>>,
-  TError = TaskManagerInternalResponseResponse,
->(options?: {
-  query?: Partial<
-    UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
-  >;
-}): UseQueryResult<TData, TError> & {
+  },
+): UseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 };
+
 /**
- * @summary List all tasks
+ * @summary Get a webhook
  */
 
-export function useListTasks<
-  TData = Awaited<ReturnType<typeof listTasks>>,
-  TError = TaskManagerInternalResponseResponse,
->(options?: {
-  query?: Partial<
-    UseQueryOptions<Awaited<ReturnType<typeof listTasks>>, TError, TData>
-  >;
-}): UseQueryResult<TData, TError> & {
+export function useGetWebhook<
+  TData = Awaited<ReturnType<typeof getWebhook>>,
+  TError = ResponseProblem,
+>(
+  webhookId: string,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof getWebhook>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 } {
-  const queryOptions = getListTasksQueryOptions(options);
+  const queryOptions = getGetWebhookQueryOptions(webhookId, options);
 
   const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
     queryKey: DataTag<QueryKey, TData, TError>;
@@ -141,36 +5724,33 @@ export function useListTasks<
 }
 
 /**
- * Creates a task with the provided name and status.
- * @summary Create a new task
+ * Removes a webhook and its delivery log.
+ * @summary Delete a webhook
  */
-export const createTask = (modelTask: ModelTask, signal?: AbortSignal) => {
-  return customInstance<CreateTask201>({
-    url: `/tasks`,
-    method: "POST",
-    headers: { "Content-Type": "application/json" },
-    data: modelTask,
-    signal,
+export const deleteWebhook = (webhookId: string) => {
+  return customInstance<void>({
+    url: `/api/v2/webhooks/${webhookId}`,
+    method: "DELETE",
   });
 };
 
-export const getCreateTaskMutationOptions = <
-  TError = TaskManagerInternalResponseResponse,
+export const getDeleteWebhookMutationOptions = <
+  TError = ResponseProblem,
   TContext = unknown,
 >(options?: {
   mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof createTask>>,
+    Awaited<ReturnType<typeof deleteWebhook>>,
     TError,
-    { data: ModelTask },
+    { webhookId: string },
     TContext
   >;
 }): UseMutationOptions<
-  Awaited<ReturnType<typeof createTask>>,
+  Awaited<ReturnType<typeof deleteWebhook>>,
   TError,
-  { data: ModelTask },
+  { webhookId: string },
   TContext
 > => {
-  const mutationKey = ["createTask"];
+  const mutationKey = ["deleteWebhook"];
   const { mutation: mutationOptions } = options
     ? options.mutation &&
       "mutationKey" in options.mutation &&
@@ -180,113 +5760,125 @@ export const getCreateTaskMutationOptions = <
     : { mutation: { mutationKey } };
 
   const mutationFn: MutationFunction<
-    Awaited<ReturnType<typeof createTask>>,
-    { data: ModelTask }
+    Awaited<ReturnType<typeof deleteWebhook>>,
+    { webhookId: string }
   > = (props) => {
-    const { data } = props ?? {};
+    const { webhookId } = props ?? {};
 
-    return createTask(data);
+    return deleteWebhook(webhookId);
   };
 
   return { mutationFn, ...mutationOptions };
 };
 
-export type CreateTaskMutationResult = NonNullable<
-  Awaited<ReturnType<typeof createTask>>
+export type DeleteWebhookMutationResult = NonNullable<
+  Awaited<ReturnType<typeof deleteWebhook>>
 >;
-export type CreateTaskMutationBody = ModelTask;
-export type CreateTaskMutationError = TaskManagerInternalResponseResponse;
+
+export type DeleteWebhookMutationError = ResponseProblem;
 
 /**
- * @summary Create a new task
+ * @summary Delete a webhook
  */
-export const useCreateTask = <
-  TError = TaskManagerInternalResponseResponse,
+export const useDeleteWebhook = <
+  TError = ResponseProblem,
   TContext = unknown,
 >(options?: {
   mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof createTask>>,
+    Awaited<ReturnType<typeof deleteWebhook>>,
     TError,
-    { data: ModelTask },
+    { webhookId: string },
     TContext
   >;
 }): UseMutationResult<
-  Awaited<ReturnType<typeof createTask>>,
+  Awaited<ReturnType<typeof deleteWebhook>>,
   TError,
-  { data: ModelTask },
+  { webhookId: string },
   TContext
 > => {
-  const mutationOptions = getCreateTaskMutationOptions(options);
+  const mutationOptions = getDeleteWebhookMutationOptions(options);
 
   return useMutation(mutationOptions);
 };
 
 /**
- * Retrieves a task by its unique identifier.
- * @summary Get a task
+ * Returns every delivery attempt for the webhook, newest first.
+ * @summary List webhook deliveries
  */
-export const getTaskByID = (id: string, signal?: AbortSignal) => {
-  return customInstance<GetTaskByID200>({
-    url: `/tasks/${id}`,
+export const listWebhookDeliveries = (
+  webhookId: string,
+  signal?: AbortSignal,
+) => {
+  return customInstance<V2DeliveryList>({
+    url: `/api/v2/webhooks/${webhookId}/deliveries`,
     method: "GET",
     signal,
   });
 };
 
-export const getGetTaskByIDQueryKey = (id: string) => {
-  return [`/tasks/${id}`] as const;
+export const getListWebhookDeliveriesQueryKey = (webhookId: string) => {
+  return [`/api/v2/webhooks/${webhookId}/deliveries`] as const;
 };
 
-export const getGetTaskByIDQueryOptions = <
-  TData = Awaited<ReturnType<typeof getTaskByID>>,
-  TError = TaskManagerInternalResponseResponse,
+export const getListWebhookDeliveriesQueryOptions = <
+  TData = Awaited<ReturnType<typeof listWebhookDeliveries>>,
+  TError = ResponseProblem,
 >(
-  id: string,
+  webhookId: string,
   options?: {
     query?: Partial<
-      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listWebhookDeliveries>>,
+        TError,
+        TData
+      >
     >;
   },
 ) => {
   const { query: queryOptions } = options ?? {};
 
-  const queryKey = queryOptions?.queryKey ?? getGetTaskByIDQueryKey(id);
+  const queryKey =
+    queryOptions?.queryKey ?? getListWebhookDeliveriesQueryKey(webhookId);
 
-  const queryFn: QueryFunction<Awaited<ReturnType<typeof getTaskByID>>> = ({
-    signal,
-  }) => getTaskByID(id, signal);
+  const queryFn: QueryFunction<
+    Awaited<ReturnType<typeof listWebhookDeliveries>>
+  > = ({ signal }) => listWebhookDeliveries(webhookId, signal);
 
   return {
     queryKey,
     queryFn,
-    enabled: !!id,
+    enabled: !!webhookId,
     ...queryOptions,
   } as UseQueryOptions<
-    Awaited<ReturnType<typeof getTaskByID>>,
+    Awaited<ReturnType<typeof listWebhookDeliveries>>,
     TError,
     TData
   > & { queryKey: DataTag<QueryKey, TData, TError> };
 };
 
-export type GetTaskByIDQueryResult = NonNullable<
-  Awaited<ReturnType<typeof getTaskByID>>
+export type ListWebhookDeliveriesQueryResult = NonNullable<
+  Awaited<ReturnType<typeof listWebhookDeliveries>>
 >;
-export type GetTaskByIDQueryError = TaskManagerInternalResponseResponse;
+export type ListWebhookDeliveriesQueryError = ResponseProblem;
 
-export function useGetTaskByID<
-  TData = Awaited<ReturnType<typeof getTaskByID>>,
-  TError = TaskManagerInternalResponseResponse,
+export function useListWebhookDeliveries<
+  TData = Awaited<ReturnType<typeof listWebhookDeliveries>>,
+  TError = ResponseProblem,
 >(
-  id: string,
+  webhookId: string,
   options: {
     query: Partial<
-      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listWebhookDeliveries>>,
+        TError,
+        TData
+      >
     > &
       Pick<
         DefinedInitialDataOptions<
-          Awaited<ReturnType<typeof getTaskByID>>,
+          Awaited<ReturnType<typeof listWebhookDeliveries>>,
           TError,
-          Awaited<ReturnType<typeof getTaskByID>>
+          Awaited<ReturnType<typeof listWebhookDeliveries>>
         >,
         "initialData"
       >;
@@ -294,20 +5886,24 @@ export function useGetTaskByID<
 ): DefinedUseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 };
-export function useGetTaskByID<
-  TData = Awaited<ReturnType<typeof getTaskByID>>,
-  TError = TaskManagerInternalResponseResponse,
+export function useListWebhookDeliveries<
+  TData = Awaited<ReturnType<typeof listWebhookDeliveries>>,
+  TError = ResponseProblem,
 >(
-  id: string,
+  webhookId: string,
   options?: {
     query?: Partial<
-      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listWebhookDeliveries>>,
+        TError,
+        TData
+      >
     > &
       Pick<
         UndefinedInitialDataOptions<
-          Awaited<ReturnType<typeof getTaskByID>>,
+          Awaited<ReturnType<typeof listWebhookDeliveries>>,
           TError,
-          Awaited<ReturnType<typeof getTaskByID>>
+          Awaited<ReturnType<typeof listWebhookDeliveries>>
         >,
         "initialData"
       >;
@@ -315,37 +5911,46 @@ export function useGetTaskByID<
 ): UseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 };
-export function useGetTaskByID<
-  TData = Awaited<ReturnType<typeof getTaskByID>>,
-  TError = TaskManagerInternalResponseResponse,
+export function useListWebhookDeliveries<
+  TData = Awaited<ReturnType<typeof listWebhookDeliveries>>,
+  TError = ResponseProblem,
 >(
-  id: string,
+  webhookId: string,
   options?: {
     query?: Partial<
-      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listWebhookDeliveries>>,
+        TError,
+        TData
+      >
     >;
   },
 ): UseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 };
+
 /**
- * @summary Get a task
+ * @summary List webhook deliveries
  */
 
-export function useGetTaskByID<
-  TData = Awaited<ReturnType<typeof getTaskByID>>,
-  TError = TaskManagerInternalResponseResponse,
+export function useListWebhookDeliveries<
+  TData = Awaited<ReturnType<typeof listWebhookDeliveries>>,
+  TError = ResponseProblem,
 >(
-  id: string,
+  webhookId: string,
   options?: {
     query?: Partial<
-      UseQueryOptions<Awaited<ReturnType<typeof getTaskByID>>, TError, TData>
+      UseQueryOptions<
+        Awaited<ReturnType<typeof listWebhookDeliveries>>,
+        TError,
+        TData
+      >
     >;
   },
 ): UseQueryResult<TData, TError> & {
   queryKey: DataTag<QueryKey, TData, TError>;
 } {
-  const queryOptions = getGetTaskByIDQueryOptions(id, options);
+  const queryOptions = getListWebhookDeliveriesQueryOptions(webhookId, options);
 
   const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
     queryKey: DataTag<QueryKey, TData, TError>;
@@ -357,35 +5962,34 @@ export function useGetTaskByID<
 }
 
 /**
- * Updates the details of a task identified by its ID.
- * @summary Update a task
+ * Synchronously sends a signed webhook.test event once, without retries, and returns the recorded delivery.
+ * @summary Test a webhook
  */
-export const updateTask = (id: string, modelTask: ModelTask) => {
-  return customInstance<UpdateTask200>({
-    url: `/tasks/${id}`,
-    method: "PUT",
-    headers: { "Content-Type": "application/json" },
-    data: modelTask,
+export const testWebhook = (webhookId: string, signal?: AbortSignal) => {
+  return customInstance<ModelWebhookDelivery>({
+    url: `/api/v2/webhooks/${webhookId}/test`,
+    method: "POST",
+    signal,
   });
 };
 
-export const getUpdateTaskMutationOptions = <
-  TError = TaskManagerInternalResponseResponse,
+export const getTestWebhookMutationOptions = <
+  TError = ResponseProblem,
   TContext = unknown,
 >(options?: {
   mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof updateTask>>,
+    Awaited<ReturnType<typeof testWebhook>>,
     TError,
-    { id: string; data: ModelTask },
+    { webhookId: string },
     TContext
   >;
 }): UseMutationOptions<
-  Awaited<ReturnType<typeof updateTask>>,
+  Awaited<ReturnType<typeof testWebhook>>,
   TError,
-  { id: string; data: ModelTask },
+  { webhookId: string },
   TContext
 > => {
-  const mutationKey = ["updateTask"];
+  const mutationKey = ["testWebhook"];
   const { mutation: mutationOptions } = options
     ? options.mutation &&
       "mutationKey" in options.mutation &&
@@ -395,121 +5999,178 @@ export const getUpdateTaskMutationOptions = <
     : { mutation: { mutationKey } };
 
   const mutationFn: MutationFunction<
-    Awaited<ReturnType<typeof updateTask>>,
-    { id: string; data: ModelTask }
+    Awaited<ReturnType<typeof testWebhook>>,
+    { webhookId: string }
   > = (props) => {
-    const { id, data } = props ?? {};
+    const { webhookId } = props ?? {};
 
-    return updateTask(id, data);
+    return testWebhook(webhookId);
   };
 
   return { mutationFn, ...mutationOptions };
 };
 
-export type UpdateTaskMutationResult = NonNullable<
-  Awaited<ReturnType<typeof updateTask>>
+export type TestWebhookMutationResult = NonNullable<
+  Awaited<ReturnType<typeof testWebhook>>
 >;
-export type UpdateTaskMutationBody = ModelTask;
-export type UpdateTaskMutationError = TaskManagerInternalResponseResponse;
+
+export type TestWebhookMutationError = ResponseProblem;
 
 /**
- * @summary Update a task
+ * @summary Test a webhook
  */
-export const useUpdateTask = <
-  TError = TaskManagerInternalResponseResponse,
+export const useTestWebhook = <
+  TError = ResponseProblem,
   TContext = unknown,
 >(options?: {
   mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof updateTask>>,
+    Awaited<ReturnType<typeof testWebhook>>,
     TError,
-    { id: string; data: ModelTask },
+    { webhookId: string },
     TContext
   >;
 }): UseMutationResult<
-  Awaited<ReturnType<typeof updateTask>>,
+  Awaited<ReturnType<typeof testWebhook>>,
   TError,
-  { id: string; data: ModelTask },
+  { webhookId: string },
   TContext
 > => {
-  const mutationOptions = getUpdateTaskMutationOptions(options);
+  const mutationOptions = getTestWebhookMutationOptions(options);
 
   return useMutation(mutationOptions);
 };
 
 /**
- * Deletes a task identified by its unique identifier.
- * @summary Delete a task
+ * Upgrades to a WebSocket and sends each task change as a JSON events.Event text message.
+ * @summary Stream task events over WebSocket
  */
-export const deleteTask = (id: string) => {
-  return customInstance<TaskManagerInternalResponseResponse>({
-    url: `/tasks/${id}`,
-    method: "DELETE",
+export const watchEvents = (
+  params?: WatchEventsParams,
+  signal?: AbortSignal,
+) => {
+  return customInstance<unknown>({
+    url: `/api/v2/ws`,
+    method: "GET",
+    params,
+    signal,
   });
 };
 
-export const getDeleteTaskMutationOptions = <
-  TError = TaskManagerInternalResponseResponse,
-  TContext = unknown,
->(options?: {
-  mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof deleteTask>>,
-    TError,
-    { id: string },
-    TContext
-  >;
-}): UseMutationOptions<
-  Awaited<ReturnType<typeof deleteTask>>,
-  TError,
-  { id: string },
-  TContext
-> => {
-  const mutationKey = ["deleteTask"];
-  const { mutation: mutationOptions } = options
-    ? options.mutation &&
-      "mutationKey" in options.mutation &&
-      options.mutation.mutationKey
-      ? options
-      : { ...options, mutation: { ...options.mutation, mutationKey } }
-    : { mutation: { mutationKey } };
+export const getWatchEventsQueryKey = (params?: WatchEventsParams) => {
+  return [`/api/v2/ws`, ...(params ? [params] : [])] as const;
+};
 
-  const mutationFn: MutationFunction<
-    Awaited<ReturnType<typeof deleteTask>>,
-    { id: string }
-  > = (props) => {
-    const { id } = props ?? {};
+export const getWatchEventsQueryOptions = <
+  TData = Awaited<ReturnType<typeof watchEvents>>,
+  TError = EventsEvent | ResponseProblem,
+>(
+  params?: WatchEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof watchEvents>>, TError, TData>
+    >;
+  },
+) => {
+  const { query: queryOptions } = options ?? {};
 
-    return deleteTask(id);
-  };
+  const queryKey = queryOptions?.queryKey ?? getWatchEventsQueryKey(params);
 
-  return { mutationFn, ...mutationOptions };
+  const queryFn: QueryFunction<Awaited<ReturnType<typeof watchEvents>>> = ({
+    signal,
+  }) => watchEvents(params, signal);
+
+  return { queryKey, queryFn, ...queryOptions } as UseQueryOptions<
+    Awaited<ReturnType<typeof watchEvents>>,
+    TError,
+    TData
+  > & { queryKey: DataTag<QueryKey, TData, TError> };
 };
 
-export type DeleteTaskMutationResult = NonNullable<
-  Awaited<ReturnType<typeof deleteTask>>
+export type WatchEventsQueryResult = NonNullable<
+  Awaited<ReturnType<typeof watchEvents>>
 >;
+export type WatchEventsQueryError = EventsEvent | ResponseProblem;
 
-export type DeleteTaskMutationError = TaskManagerInternalResponseResponse;
+export function useWatchEvents<
+  TData = Awaited<ReturnType<typeof watchEvents>>,
+  TError = EventsEvent | ResponseProblem,
+>(
+  params: undefined | WatchEventsParams,
+  options: {
+    query: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof watchEvents>>, TError, TData>
+    > &
+      Pick<
+        DefinedInitialDataOptions<
+          Awaited<ReturnType<typeof watchEvents>>,
+          TError,
+          Awaited<ReturnType<typeof watchEvents>>
+        >,
+        "initialData"
+      >;
+  },
+): DefinedUseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useWatchEvents<
+  TData = Awaited<ReturnType<typeof watchEvents>>,
+  TError = EventsEvent | ResponseProblem,
+>(
+  params?: WatchEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof watchEvents>>, TError, TData>
+    > &
+      Pick<
+        UndefinedInitialDataOptions<
+          Awaited<ReturnType<typeof watchEvents>>,
+          TError,
+          Awaited<ReturnType<typeof watchEvents>>
+        >,
+        "initialData"
+      >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
+export function useWatchEvents<
+  TData = Awaited<ReturnType<typeof watchEvents>>,
+  TError = EventsEvent | ResponseProblem,
+>(
+  params?: WatchEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof watchEvents>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+};
 
 /**
- * @summary Delete a task
+ * @summary Stream task events over WebSocket
  */
-export const useDeleteTask = <
-  TError = TaskManagerInternalResponseResponse,
-  TContext = unknown,
->(options?: {
-  mutation?: UseMutationOptions<
-    Awaited<ReturnType<typeof deleteTask>>,
-    TError,
-    { id: string },
-    TContext
-  >;
-}): UseMutationResult<
-  Awaited<ReturnType<typeof deleteTask>>,
-  TError,
-  { id: string },
-  TContext
-> => {
-  const mutationOptions = getDeleteTaskMutationOptions(options);
 
-  return useMutation(mutationOptions);
-};
+export function useWatchEvents<
+  TData = Awaited<ReturnType<typeof watchEvents>>,
+  TError = EventsEvent | ResponseProblem,
+>(
+  params?: WatchEventsParams,
+  options?: {
+    query?: Partial<
+      UseQueryOptions<Awaited<ReturnType<typeof watchEvents>>, TError, TData>
+    >;
+  },
+): UseQueryResult<TData, TError> & {
+  queryKey: DataTag<QueryKey, TData, TError>;
+} {
+  const queryOptions = getWatchEventsQueryOptions(params, options);
+
+  const query = useQuery(queryOptions) as UseQueryResult<TData, TError> & {
+    queryKey: DataTag<QueryKey, TData, TError>;
+  };
+
+  query.queryKey = queryOptions.queryKey;
+
+  return query;
+}
@@ -0,0 +1,20 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export type EventsType = (typeof EventsType)[keyof typeof EventsType];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const EventsType = {
+  "task.created": "task.created",
+  "task.updated": "task.updated",
+  "task.deleted": "task.deleted",
+  "task.processing": "task.processing",
+  "task.completed": "task.completed",
+  "task.failed": "task.failed",
+  "tasks.imported": "tasks.imported",
+} as const;
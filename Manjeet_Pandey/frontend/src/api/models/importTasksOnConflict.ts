@@ -0,0 +1,17 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export type ImportTasksOnConflict =
+  (typeof ImportTasksOnConflict)[keyof typeof ImportTasksOnConflict];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const ImportTasksOnConflict = {
+  fail: "fail",
+  skip: "skip",
+  update: "update",
+} as const;
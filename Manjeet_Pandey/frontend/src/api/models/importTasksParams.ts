@@ -0,0 +1,20 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+import type { ImportTasksFormat } from "./importTasksFormat";
+import type { ImportTasksOnConflict } from "./importTasksOnConflict";
+
+export type ImportTasksParams = {
+  /**
+   * Input format
+   */
+  format?: ImportTasksFormat;
+  /**
+   * What to do with tasks whose ID already exists
+   */
+  on_conflict?: ImportTasksOnConflict;
+};
@@ -2,18 +2,57 @@
  * Generated by orval v7.5.0 🍺
  * Do not edit manually.
  * Task Manager API
- * A simple task management API built with Go and Gin.
- * OpenAPI spec version: 1.0
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
  */
 
-export * from "./createTask201";
-export * from "./createTask201AllOf";
-export * from "./getTaskByID200";
-export * from "./getTaskByID200AllOf";
-export * from "./listTasks200";
-export * from "./listTasks200AllOf";
+export * from "./createAttachmentBody";
+export * from "./createProjectAttachmentBody";
+export * from "./eventsEvent";
+export * from "./eventsType";
+export * from "./exportTasksFormat";
+export * from "./exportTasksParams";
+export * from "./handlerBulkOperationResult";
+export * from "./handlerBulkResponse";
+export * from "./handlerWebhookRequest";
+export * from "./importTasksFormat";
+export * from "./importTasksOnConflict";
+export * from "./importTasksParams";
+export * from "./listProjectTasksMatch";
+export * from "./listProjectTasksParams";
+export * from "./listTasksMatch";
+export * from "./listTasksParams";
+export * from "./modelActivity";
+export * from "./modelActivityType";
+export * from "./modelAttachment";
+export * from "./modelComment";
+export * from "./modelCreateCommentRequest";
+export * from "./modelCreateLabelRequest";
+export * from "./modelCreateProjectRequest";
+export * from "./modelLabel";
+export * from "./modelProject";
 export * from "./modelTask";
+export * from "./modelTaskRequest";
 export * from "./modelTaskStatus";
-export * from "./taskManagerInternalResponseResponse";
-export * from "./updateTask200";
-export * from "./updateTask200AllOf";
+export * from "./modelUpdateCommentRequest";
+export * from "./modelUpdateProjectRequest";
+export * from "./modelWebhook";
+export * from "./modelWebhookDelivery";
+export * from "./patchProjectTaskBody";
+export * from "./patchTaskBody";
+export * from "./responseProblem";
+export * from "./serviceBulkOp";
+export * from "./serviceBulkOperation";
+export * from "./serviceBulkRequest";
+export * from "./serviceImportSummary";
+export * from "./streamEventsParams";
+export * from "./v2ActivityList";
+export * from "./v2AttachmentList";
+export * from "./v2CommentList";
+export * from "./v2DeliveryList";
+export * from "./v2LabelList";
+export * from "./v2ProjectList";
+export * from "./v2TaskList";
+export * from "./v2WebhookList";
+export * from "./validationFieldError";
+export * from "./watchEventsParams";
@@ -0,0 +1,16 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export type ListProjectTasksMatch =
+  (typeof ListProjectTasksMatch)[keyof typeof ListProjectTasksMatch];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const ListProjectTasksMatch = {
+  all: "all",
+  any: "any",
+} as const;
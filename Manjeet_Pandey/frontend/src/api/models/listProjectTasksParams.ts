@@ -0,0 +1,19 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+import type { ListProjectTasksMatch } from "./listProjectTasksMatch";
+
+export type ListProjectTasksParams = {
+  /**
+   * Only tasks with these labels, by name
+   */
+  label?: string[];
+  /**
+   * Whether tasks need all the labels or any of them
+   */
+  match?: ListProjectTasksMatch;
+};
@@ -0,0 +1,33 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+import type { ModelComment } from "./modelComment";
+import type { ModelTaskStatus } from "./modelTaskStatus";
+import type { ModelActivityType } from "./modelActivityType";
+
+export interface ModelActivity {
+  comment?: ModelComment;
+  /**
+   * CommentID is set for comments. Comment holds the comment as it is
+   * now, and is nil once the comment has been deleted.
+   */
+  comment_id?: string;
+  created_at?: string;
+  /**
+   * FromStatus and ToStatus are set for status changes; ToStatus is also
+   * the initial status of a created task.
+   */
+  from_status?: ModelTaskStatus;
+  /**
+   * ID increases with every entry, so it orders entries written within
+   * the same clock tick.
+   */
+  id?: number;
+  task_id?: string;
+  to_status?: ModelTaskStatus;
+  type?: ModelActivityType;
+}
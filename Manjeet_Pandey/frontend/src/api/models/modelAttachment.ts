@@ -0,0 +1,22 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export interface ModelAttachment {
+  /**
+   * ContentType is detected from the contents rather than taken from the
+   * upload.
+   */
+  content_type?: string;
+  created_at?: string;
+  file_name?: string;
+  id?: string;
+  /** SHA256 is the hex encoded SHA-256 digest of the contents. */
+  sha256?: string;
+  size?: number;
+  task_id?: string;
+}
@@ -0,0 +1,19 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export interface ModelCreateProjectRequest {
+  /** @maxLength 1000 */
+  description?: string;
+  /**
+   * @minimum 0
+   * @maximum 100
+   */
+  max_concurrency?: number;
+  /** @maxLength 100 */
+  name: string;
+}
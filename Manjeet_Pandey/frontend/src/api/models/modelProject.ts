@@ -0,0 +1,20 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export interface ModelProject {
+  created_at?: string;
+  description?: string;
+  id?: string;
+  /**
+   * MaxConcurrency caps how many of the project's tasks a worker pool
+   * processes at once; 0 leaves it to the size of the pool.
+   */
+  max_concurrency?: number;
+  name?: string;
+  updated_at?: string;
+}
@@ -2,16 +2,27 @@
  * Generated by orval v7.5.0 🍺
  * Do not edit manually.
  * Task Manager API
- * A simple task management API built with Go and Gin.
- * OpenAPI spec version: 1.0
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
  */
+import type { ModelLabel } from "./modelLabel";
 import type { ModelTaskStatus } from "./modelTaskStatus";
 
 export interface ModelTask {
   created_at?: string;
   description?: string;
   id?: string;
+  /**
+   * Labels are set through the label endpoints, never by writing the
+   * task.
+   */
+  labels?: ModelLabel[];
   name?: string;
+  /**
+   * ProjectID is set by the project the task is created in and never
+   * changes.
+   */
+  project_id?: string;
   status?: ModelTaskStatus;
   updated_at?: string;
 }
@@ -0,0 +1,20 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export interface ModelWebhookDelivery {
+  attempt?: number;
+  created_at?: string;
+  duration_ms?: number;
+  error?: string;
+  event_id?: number;
+  event_type?: string;
+  id?: string;
+  status_code?: number;
+  success?: boolean;
+  webhook_id?: string;
+}
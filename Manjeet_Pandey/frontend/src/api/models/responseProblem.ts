@@ -0,0 +1,22 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+import type { ValidationFieldError } from "./validationFieldError";
+
+export interface ResponseProblem {
+  /** Detail explains this occurrence of the problem. */
+  detail?: string;
+  /** Errors lists the invalid fields when a request fails validation. */
+  errors?: ValidationFieldError[];
+  /** Instance is the path of the request that failed. */
+  instance?: string;
+  request_id?: string;
+  status?: number;
+  title?: string;
+  /** Type identifies the kind of problem; see ProblemTypes. */
+  type?: string;
+}
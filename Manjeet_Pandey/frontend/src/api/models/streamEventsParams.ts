@@ -0,0 +1,18 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export type StreamEventsParams = {
+  /**
+   * Only events for tasks in these statuses
+   */
+  status?: string[];
+  /**
+   * Only events for these task IDs
+   */
+  task_id?: string[];
+};
@@ -0,0 +1,15 @@
+/**
+ * Generated by orval v7.5.0 🍺
+ * Do not edit manually.
+ * Task Manager API
+ * A simple task management API built with Go and Gin. Version 2 returns resources without an envelope and reports errors as RFC 7807 problem details.
+ * OpenAPI spec version: 2.0
+ */
+
+export interface ValidationFieldError {
+  /** Field is the JSON name of the field, dotted for nested fields. */
+  field?: string;
+  message?: string;
+  /** Rule is the validation rule that failed, e.g. required or max. */
+  rule?: string;
+}
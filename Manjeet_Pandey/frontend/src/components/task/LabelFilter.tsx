@@ -0,0 +1,75 @@
+import { useListLabels } from "@/api/generated/taskManagerApis";
+import { ListTasksMatch } from "@/api/models";
+import { cn } from "@/lib/utils";
+import { Filter, X } from "lucide-react";
+import { LabelChip } from "./TaskLabels";
+
+interface LabelFilterProps {
+  selected: string[];
+  match: ListTasksMatch;
+  onChange: (selected: string[], match: ListTasksMatch) => void;
+}
+
+/**
+ * Chips to list only the tasks with some labels: every one of them, or any
+ * of them.
+ */
+export const LabelFilter = ({ selected, match, onChange }: LabelFilterProps) => {
+  const { data: labels } = useListLabels();
+
+  if (!labels?.items?.length) {
+    return null;
+  }
+
+  const toggle = (name: string) =>
+    onChange(
+      selected.includes(name)
+        ? selected.filter((other) => other !== name)
+        : [...selected, name],
+      match,
+    );
+
+  return (
+    <div className="flex flex-wrap items-center gap-2">
+      <Filter className="w-4 h-4 text-gray-400" />
+      {labels.items.map((label) => (
+        <LabelChip
+          key={label.id}
+          label={label}
+          selected={selected.includes(label.name as string)}
+          onClick={() => toggle(label.name as string)}
+        />
+      ))}
+      {selected.length > 1 && (
+        <div className="flex rounded-full border border-gray-200 text-xs">
+          {Object.values(ListTasksMatch).map((value) => (
+            <button
+              key={value}
+              type="button"
+              onClick={() => onChange(selected, value)}
+              aria-pressed={match === value}
+              className={cn(
+                "px-2.5 py-0.5 rounded-full transition-all duration-200",
+                match === value
+                  ? "bg-indigo-600 text-white"
+                  : "text-gray-500 hover:text-indigo-600",
+              )}
+            >
+              {value === ListTasksMatch.all ? "All" : "Any"}
+            </button>
+          ))}
+        </div>
+      )}
+      {selected.length > 0 && (
+        <button
+          type="button"
+          onClick={() => onChange([], match)}
+          className="inline-flex items-center gap-1 text-xs text-gray-500 hover:text-indigo-600"
+        >
+          <X className="w-3 h-3" />
+          Clear
+        </button>
+      )}
+    </div>
+  );
+};
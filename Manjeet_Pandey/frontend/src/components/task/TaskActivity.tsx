@@ -0,0 +1,242 @@
+import { useState, type FormEvent, type ReactNode } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import {
+  getListActivityQueryKey,
+  useCreateComment,
+  useDeleteComment,
+  useListActivity,
+  useUpdateComment,
+} from "@/api/generated/taskManagerApis";
+import type { ModelActivity, ModelComment } from "@/api/models";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Markdown } from "@/lib/markdown";
+import {
+  ArrowRight,
+  Loader2,
+  MessageSquare,
+  Pencil,
+  PlusCircle,
+  Trash2,
+} from "lucide-react";
+import { toast } from "sonner";
+
+// The author is remembered between visits; there are no accounts yet.
+const authorStorageKey = "task-flow.comment-author";
+
+const textareaClassName =
+  "w-full min-h-20 rounded-md border border-gray-200 bg-transparent px-3 py-2 text-sm outline-none focus-visible:ring-[3px] focus-visible:ring-indigo-100";
+
+const formatTime = (time?: string) =>
+  time ? new Date(time).toLocaleString() : "";
+
+interface TaskActivityProps {
+  taskId: string;
+}
+
+/** The activity feed of a task, with a form to comment on it. */
+export const TaskActivity = ({ taskId }: TaskActivityProps) => {
+  const queryClient = useQueryClient();
+  const { data: activity, isLoading } = useListActivity(taskId);
+  const { mutateAsync: createComment, isPending } = useCreateComment();
+  const [author, setAuthor] = useState(
+    () => localStorage.getItem(authorStorageKey) ?? "",
+  );
+  const [body, setBody] = useState("");
+
+  const refresh = () =>
+    queryClient.invalidateQueries({ queryKey: getListActivityQueryKey(taskId) });
+
+  const submit = async (event: FormEvent) => {
+    event.preventDefault();
+    try {
+      await createComment({ id: taskId, data: { author, body } });
+      localStorage.setItem(authorStorageKey, author);
+      setBody("");
+      refresh();
+    } catch (error) {
+      toast.error("Error", { description: "Failed to add comment." });
+    }
+  };
+
+  if (isLoading) {
+    return (
+      <div className="flex justify-center py-4">
+        <Loader2 className="h-6 w-6 animate-spin text-indigo-600" />
+      </div>
+    );
+  }
+
+  return (
+    <div className="mt-4 pt-4 border-t border-gray-100 space-y-4">
+      <ol className="space-y-3">
+        {activity?.items?.map((entry) => (
+          <li key={entry.id}>
+            <ActivityEntry taskId={taskId} entry={entry} onChange={refresh} />
+          </li>
+        ))}
+      </ol>
+      <form onSubmit={submit} className="space-y-2">
+        <Input
+          placeholder="Your name"
+          value={author}
+          maxLength={100}
+          onChange={(e) => setAuthor(e.target.value)}
+          required
+        />
+        <textarea
+          className={textareaClassName}
+          placeholder="Leave a comment. Markdown is supported."
+          value={body}
+          maxLength={10000}
+          onChange={(e) => setBody(e.target.value)}
+          required
+        />
+        <div className="flex justify-end">
+          <Button
+            type="submit"
+            disabled={isPending || !author.trim() || !body.trim()}
+            className="bg-indigo-600 hover:bg-indigo-700 text-white"
+          >
+            Comment
+          </Button>
+        </div>
+      </form>
+    </div>
+  );
+};
+
+interface ActivityEntryProps {
+  taskId: string;
+  entry: ModelActivity;
+  onChange: () => void;
+}
+
+const ActivityEntry = ({ taskId, entry, onChange }: ActivityEntryProps) => {
+  switch (entry.type) {
+    case "task_created":
+      return (
+        <ActivityLine icon={<PlusCircle className="w-4 h-4" />} time={entry.created_at}>
+          Task created as {entry.to_status}
+        </ActivityLine>
+      );
+    case "status_changed":
+      return (
+        <ActivityLine icon={<ArrowRight className="w-4 h-4" />} time={entry.created_at}>
+          Status changed from {entry.from_status} to {entry.to_status}
+        </ActivityLine>
+      );
+    case "comment":
+      return entry.comment ? (
+        <Comment taskId={taskId} comment={entry.comment} onChange={onChange} />
+      ) : (
+        <ActivityLine icon={<MessageSquare className="w-4 h-4" />} time={entry.created_at}>
+          A comment was deleted
+        </ActivityLine>
+      );
+    default:
+      return null;
+  }
+};
+
+interface ActivityLineProps {
+  icon: ReactNode;
+  time?: string;
+  children: ReactNode;
+}
+
+const ActivityLine = ({ icon, time, children }: ActivityLineProps) => (
+  <div className="flex items-center gap-2 text-sm text-gray-500">
+    {icon}
+    <span>{children}</span>
+    <span className="text-gray-400">· {formatTime(time)}</span>
+  </div>
+);
+
+interface CommentProps {
+  taskId: string;
+  comment: ModelComment;
+  onChange: () => void;
+}
+
+const Comment = ({ taskId, comment, onChange }: CommentProps) => {
+  const { mutateAsync: updateComment, isPending } = useUpdateComment();
+  const { mutateAsync: deleteComment } = useDeleteComment();
+  const [draft, setDraft] = useState<string | null>(null);
+  const commentId = comment.id as string;
+
+  const save = async () => {
+    if (draft === null) return;
+    try {
+      await updateComment({ id: taskId, commentId, data: { body: draft } });
+      setDraft(null);
+      onChange();
+    } catch (error) {
+      toast.error("Error", { description: "Failed to edit comment." });
+    }
+  };
+
+  const remove = async () => {
+    try {
+      await deleteComment({ id: taskId, commentId });
+      onChange();
+    } catch (error) {
+      toast.error("Error", { description: "Failed to delete comment." });
+    }
+  };
+
+  return (
+    <div className="rounded-lg border border-gray-100 bg-gray-50/50 p-3">
+      <div className="flex items-center gap-2 text-sm mb-2">
+        <MessageSquare className="w-4 h-4 text-indigo-400" />
+        <span className="font-medium text-gray-800">{comment.author}</span>
+        <span className="text-gray-400">
+          · {formatTime(comment.created_at)}
+          {comment.edited_at && " (edited)"}
+        </span>
+        <div className="ml-auto flex gap-1">
+          <button
+            onClick={() => setDraft(comment.body ?? "")}
+            className="p-1 text-gray-400 hover:text-indigo-600 rounded"
+            aria-label="Edit comment"
+          >
+            <Pencil className="w-4 h-4" />
+          </button>
+          <button
+            onClick={remove}
+            className="p-1 text-gray-400 hover:text-red-600 rounded"
+            aria-label="Delete comment"
+          >
+            <Trash2 className="w-4 h-4" />
+          </button>
+        </div>
+      </div>
+      {draft === null ? (
+        <div className="text-sm text-gray-700">
+          <Markdown source={comment.body ?? ""} />
+        </div>
+      ) : (
+        <div className="space-y-2">
+          <textarea
+            className={textareaClassName}
+            value={draft}
+            maxLength={10000}
+            onChange={(e) => setDraft(e.target.value)}
+          />
+          <div className="flex justify-end gap-2">
+            <Button variant="outline" onClick={() => setDraft(null)}>
+              Cancel
+            </Button>
+            <Button
+              onClick={save}
+              disabled={isPending || !draft.trim()}
+              className="bg-indigo-600 hover:bg-indigo-700 text-white"
+            >
+              Save
+            </Button>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,138 @@
+import { useRef, type ChangeEvent } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import {
+  getListAttachmentsQueryKey,
+  useCreateAttachment,
+  useDeleteAttachment,
+  useListAttachments,
+} from "@/api/generated/taskManagerApis";
+import type { ModelAttachment, ResponseProblem } from "@/api/models";
+import { apiBaseUrl } from "@/api/client/baseUrl";
+import { Button } from "@/components/ui/button";
+import { FileText, Image, Loader2, Paperclip, Trash2 } from "lucide-react";
+import { toast } from "sonner";
+
+const units = ["B", "KB", "MB", "GB"];
+
+const formatSize = (size = 0) => {
+  let unit = 0;
+  while (size >= 1024 && unit < units.length - 1) {
+    size /= 1024;
+    unit++;
+  }
+  return `${unit === 0 ? size : size.toFixed(1)} ${units[unit]}`;
+};
+
+const contentUrl = (taskId: string, attachment: ModelAttachment) =>
+  `${apiBaseUrl}/api/v2/tasks/${taskId}/attachments/${attachment.id}/content`;
+
+interface TaskAttachmentsProps {
+  taskId: string;
+}
+
+/** The files attached to a task, with a button to attach more. */
+export const TaskAttachments = ({ taskId }: TaskAttachmentsProps) => {
+  const queryClient = useQueryClient();
+  const { data: attachments, isLoading } = useListAttachments(taskId);
+  const { mutateAsync: createAttachment, isPending } = useCreateAttachment();
+  const { mutateAsync: deleteAttachment } = useDeleteAttachment();
+  const input = useRef<HTMLInputElement>(null);
+
+  const refresh = () =>
+    queryClient.invalidateQueries({
+      queryKey: getListAttachmentsQueryKey(taskId),
+    });
+
+  const upload = async (event: ChangeEvent<HTMLInputElement>) => {
+    const files = Array.from(event.target.files ?? []);
+    event.target.value = "";
+    for (const file of files) {
+      try {
+        await createAttachment({ id: taskId, data: { file } });
+      } catch (error) {
+        // 413 and 415 explain which limit the file broke.
+        const problem = error as ResponseProblem;
+        toast.error("Error", {
+          description: `Failed to attach ${file.name}${problem?.detail ? `: ${problem.detail}` : "."}`,
+        });
+      }
+    }
+    refresh();
+  };
+
+  const remove = async (attachment: ModelAttachment) => {
+    try {
+      await deleteAttachment({
+        id: taskId,
+        attachmentId: attachment.id as string,
+      });
+      refresh();
+    } catch (error) {
+      toast.error("Error", { description: "Failed to delete attachment." });
+    }
+  };
+
+  if (isLoading) {
+    return (
+      <div className="flex justify-center py-4">
+        <Loader2 className="h-6 w-6 animate-spin text-indigo-600" />
+      </div>
+    );
+  }
+
+  return (
+    <div className="mt-4 pt-4 border-t border-gray-100 space-y-2">
+      <ul className="space-y-1">
+        {attachments?.items?.map((attachment) => (
+          <li
+            key={attachment.id}
+            className="flex items-center gap-2 text-sm text-gray-700"
+          >
+            {attachment.content_type?.startsWith("image/") ? (
+              <Image className="w-4 h-4 text-indigo-400" />
+            ) : (
+              <FileText className="w-4 h-4 text-indigo-400" />
+            )}
+            <a
+              href={contentUrl(taskId, attachment)}
+              target="_blank"
+              rel="noopener noreferrer"
+              className="truncate hover:text-indigo-600 hover:underline"
+            >
+              {attachment.file_name}
+            </a>
+            <span className="text-gray-400">
+              · {formatSize(attachment.size)}
+            </span>
+            <button
+              onClick={() => remove(attachment)}
+              className="ml-auto p-1 text-gray-400 hover:text-red-600 rounded"
+              aria-label={`Delete ${attachment.file_name}`}
+            >
+              <Trash2 className="w-4 h-4" />
+            </button>
+          </li>
+        ))}
+      </ul>
+      <input
+        ref={input}
+        type="file"
+        multiple
+        className="hidden"
+        onChange={upload}
+      />
+      <Button
+        variant="outline"
+        onClick={() => input.current?.click()}
+        disabled={isPending}
+      >
+        {isPending ? (
+          <Loader2 className="w-4 h-4 animate-spin" />
+        ) : (
+          <Paperclip className="w-4 h-4" />
+        )}
+        Attach files
+      </Button>
+    </div>
+  );
+};
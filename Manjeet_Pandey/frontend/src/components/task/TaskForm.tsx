@@ -19,18 +19,29 @@ import {
 } from "@/components/ui/form";
 import { Input } from "@/components/ui/input";
 import type { ModelTask } from "@/api/models/modelTask";
+import type { ResponseProblem } from "@/api/models/responseProblem";
 import { Loader2 } from "lucide-react";
 import { toast } from "sonner";
 import { queryClient } from "@/main";
 import { useCreateTask, useUpdateTask } from "@/api/generated/taskManagerApis";
 
+// Keep these limits in step with the validate tags on the API's request
+// types; the server checks them again and reports any field it rejects.
 const taskSchema = z.object({
-  name: z.string().min(1, "Task name is required").max(100),
-  description: z.string()
+  name: z
+    .string()
+    .trim()
+    .min(1, "Task name is required")
+    .max(100, "Task name must be at most 100 characters"),
+  description: z
+    .string()
+    .max(1000, "Description must be at most 1000 characters"),
 });
 
 type TaskFormValues = z.infer<typeof taskSchema>;
 
+const formFields: (keyof TaskFormValues)[] = ["name", "description"];
+
 interface TaskDialogProps {
   open: boolean;
   onOpenChange: (open: boolean) => void;
@@ -64,6 +75,25 @@ export const TaskDialog = ({
     });
   }, [editTask, form]);
 
+  // showErrors puts the server's field errors next to their inputs and
+  // reports whatever is left in a toast.
+  const showErrors = (problem: ResponseProblem) => {
+    let unshown = !problem.errors?.length;
+    for (const fieldError of problem.errors ?? []) {
+      const field = formFields.find((name) => name === fieldError.field);
+      if (field) {
+        form.setError(field, { message: fieldError.message });
+      } else {
+        unshown = true;
+      }
+    }
+    if (unshown) {
+      toast.error(problem.title || "Error", {
+        description: problem.detail || "Something went wrong. Please try again.",
+      });
+    }
+  };
+
   const onSubmit = async (data: TaskFormValues) => {
     try {
       setIsSubmitting(true);
@@ -85,9 +115,7 @@ export const TaskDialog = ({
       form.reset();
       onSuccess?.();
     } catch (error) {
-      toast.error("Error", {
-        description: "Something went wrong. Please try again.",
-      });
+      showErrors(error as ResponseProblem);
     } finally {
       setIsSubmitting(false);
     }
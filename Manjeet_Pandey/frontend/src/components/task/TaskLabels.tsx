@@ -0,0 +1,183 @@
+import { useState, type FormEvent, type ReactNode } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import {
+  getListLabelsQueryKey,
+  getListTasksQueryKey,
+  useAttachLabel,
+  useCreateLabel,
+  useDetachLabel,
+  useListLabels,
+} from "@/api/generated/taskManagerApis";
+import type { ModelLabel, ResponseProblem } from "@/api/models";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { cn } from "@/lib/utils";
+import { Check, Loader2, Plus, Tag } from "lucide-react";
+import { toast } from "sonner";
+
+const defaultColor = "#6b7280";
+
+interface LabelChipProps {
+  label: ModelLabel;
+  selected?: boolean;
+  onClick?: () => void;
+  children?: ReactNode;
+}
+
+/** A label as a chip in its colour, clickable when onClick is given. */
+export const LabelChip = ({
+  label,
+  selected,
+  onClick,
+  children,
+}: LabelChipProps) => {
+  const color = label.color || defaultColor;
+  const className = cn(
+    "inline-flex items-center gap-1.5 rounded-full border px-2.5 py-0.5 text-xs font-medium",
+    selected ? "text-white" : "bg-white text-gray-700",
+    onClick && "cursor-pointer transition-all duration-200 hover:shadow-sm",
+  );
+  const style = {
+    borderColor: color,
+    ...(selected && { backgroundColor: color }),
+  };
+  const content = (
+    <>
+      <span
+        className="h-2 w-2 rounded-full"
+        style={{ backgroundColor: selected ? "white" : color }}
+      />
+      {label.name}
+      {children}
+    </>
+  );
+  if (!onClick) {
+    return (
+      <span className={className} style={style}>
+        {content}
+      </span>
+    );
+  }
+  return (
+    <button
+      type="button"
+      onClick={onClick}
+      aria-pressed={selected}
+      className={className}
+      style={style}
+    >
+      {content}
+    </button>
+  );
+};
+
+interface TaskLabelsProps {
+  taskId: string;
+  labels?: ModelLabel[];
+}
+
+/**
+ * Every label, with those on the task selected. Clicking a label puts it on
+ * or takes it off the task, and new labels can be created here.
+ */
+export const TaskLabels = ({ taskId, labels = [] }: TaskLabelsProps) => {
+  const queryClient = useQueryClient();
+  const { data: allLabels, isLoading } = useListLabels();
+  const { mutateAsync: attachLabel } = useAttachLabel();
+  const { mutateAsync: detachLabel } = useDetachLabel();
+  const { mutateAsync: createLabel, isPending } = useCreateLabel();
+  const [name, setName] = useState("");
+  const [color, setColor] = useState(defaultColor);
+
+  // The event stream updates the list too; this covers a dropped stream.
+  const refreshTasks = () =>
+    queryClient.invalidateQueries({ queryKey: getListTasksQueryKey() });
+
+  const carried = new Set(labels.map((label) => label.id));
+
+  const toggle = async (label: ModelLabel) => {
+    const labelId = label.id as string;
+    try {
+      if (carried.has(labelId)) {
+        await detachLabel({ id: taskId, labelId });
+      } else {
+        await attachLabel({ id: taskId, labelId });
+      }
+      refreshTasks();
+    } catch (error) {
+      toast.error("Error", { description: "Failed to update labels." });
+    }
+  };
+
+  const create = async (event: FormEvent) => {
+    event.preventDefault();
+    try {
+      const label = await createLabel({ data: { name, color } });
+      await attachLabel({ id: taskId, labelId: label.id as string });
+      setName("");
+      queryClient.invalidateQueries({ queryKey: getListLabelsQueryKey() });
+      refreshTasks();
+    } catch (error) {
+      // 409 when the name is taken.
+      const problem = error as ResponseProblem;
+      toast.error("Error", {
+        description: `Failed to create label${problem?.detail ? `: ${problem.detail}` : "."}`,
+      });
+    }
+  };
+
+  if (isLoading) {
+    return (
+      <div className="flex justify-center py-4">
+        <Loader2 className="h-6 w-6 animate-spin text-indigo-600" />
+      </div>
+    );
+  }
+
+  return (
+    <div className="mt-4 pt-4 border-t border-gray-100 space-y-3">
+      <div className="flex flex-wrap items-center gap-2">
+        <Tag className="w-4 h-4 text-indigo-400" />
+        {allLabels?.items?.map((label) => (
+          <LabelChip
+            key={label.id}
+            label={label}
+            selected={carried.has(label.id)}
+            onClick={() => toggle(label)}
+          >
+            {carried.has(label.id) && <Check className="w-3 h-3" />}
+          </LabelChip>
+        ))}
+      </div>
+      <form onSubmit={create} className="flex items-center gap-2">
+        <Input
+          value={name}
+          onChange={(event) => setName(event.target.value)}
+          placeholder="New label"
+          maxLength={50}
+          className="max-w-48"
+          required
+        />
+        <input
+          type="color"
+          value={color}
+          onChange={(event) => setColor(event.target.value)}
+          className="h-9 w-9 cursor-pointer rounded-md border border-gray-200 bg-transparent p-1"
+          aria-label="Label colour"
+        />
+        <Button
+          type="submit"
+          variant="outline"
+          disabled={isPending || !name.trim()}
+        >
+          {isPending ? (
+            <Loader2 className="w-4 h-4 animate-spin" />
+          ) : (
+            <Plus className="w-4 h-4" />
+          )}
+          Add label
+        </Button>
+      </form>
+    </div>
+  );
+};
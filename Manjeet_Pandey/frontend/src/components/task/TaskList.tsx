@@ -1,30 +1,47 @@
-import { useDeleteTask, useListTasks, useUpdateTask } from "@/api/generated/taskManagerApis";
+import { useState } from "react";
+import { keepPreviousData } from "@tanstack/react-query";
+import { useDeleteTask, useListTasks, usePatchTask } from "@/api/generated/taskManagerApis";
 import type { ModelTask } from "@/api/models/modelTask";
+import { ListTasksMatch } from "@/api/models";
+import { useTaskEvents } from "@/api/client/taskEvents";
 import {
   Loader2,
   Pencil,
   Trash2,
   ClipboardList,
   Check,
+  MessageSquare,
 } from "lucide-react";
 import { toast } from "sonner";
 import { cn } from "@/lib/utils";
+import { TaskActivity } from "./TaskActivity";
+import { TaskAttachments } from "./TaskAttachments";
+import { LabelFilter } from "./LabelFilter";
+import { LabelChip, TaskLabels } from "./TaskLabels";
 
 interface TaskListProps {
   onEdit: (task: ModelTask) => void;
 }
 
 export const TaskList = ({ onEdit }: TaskListProps) => {
-  const { data: tasks, isLoading, refetch } = useListTasks();
+  const [labels, setLabels] = useState<string[]>([]);
+  const [match, setMatch] = useState<ListTasksMatch>(ListTasksMatch.all);
+  const { data: tasks, isLoading, refetch } = useListTasks(
+    labels.length ? { label: labels, match } : undefined,
+    // The list stays up while a new filter loads.
+    { query: { placeholderData: keepPreviousData } },
+  );
   const { mutateAsync: deleteTask } = useDeleteTask();
-  const { mutateAsync: updateTask } = useUpdateTask();
+  const { mutateAsync: patchTask } = usePatchTask();
+  const [openTaskId, setOpenTaskId] = useState<string | null>(null);
+  useTaskEvents();
 
   const toggleStatus = async (task: ModelTask) => {
     try {
       const newStatus = task.status === "Pending" ? "Completed" : "Pending";
-      await updateTask({
+      await patchTask({
         id: task.id as string,
-        data: { ...task, status: newStatus },
+        data: { status: newStatus },
       });
       toast.success("Status updated", {
         description: `Task marked as ${newStatus.toLowerCase()}.`,
@@ -59,7 +76,30 @@ export const TaskList = ({ onEdit }: TaskListProps) => {
     );
   }
 
-  if (!tasks?.data?.length) {
+  const filter = (
+    <LabelFilter
+      selected={labels}
+      match={match}
+      onChange={(selected, selectedMatch) => {
+        setLabels(selected);
+        setMatch(selectedMatch);
+      }}
+    />
+  );
+
+  if (!tasks?.items?.length && labels.length) {
+    return (
+      <div className="space-y-3">
+        {filter}
+        <p className="py-12 text-center text-gray-500">
+          No tasks have {match === ListTasksMatch.all ? "all" : "any"} of these
+          labels.
+        </p>
+      </div>
+    );
+  }
+
+  if (!tasks?.items?.length) {
     return (
       <div className="flex flex-col items-center justify-center min-h-[400px] rounded-xl p-8">
         <ClipboardList className="h-20 w-20 text-indigo-200 mb-6" />
@@ -75,7 +115,8 @@ export const TaskList = ({ onEdit }: TaskListProps) => {
 
   return (
     <div className="space-y-3">
-      {tasks.data.map((task: ModelTask) => (
+      {filter}
+      {tasks.items.map((task: ModelTask) => (
         <div
           key={task.id}
           className="bg-white rounded-xl border border-gray-100 hover:border-indigo-100 transition-all duration-200 p-4 sm:p-5"
@@ -116,9 +157,29 @@ export const TaskList = ({ onEdit }: TaskListProps) => {
                 {task.description && (
                   <p className="text-sm text-gray-500 mt-1">{task.description}</p>
                 )}
+                {!!task.labels?.length && (
+                  <div className="flex flex-wrap gap-1.5 mt-2">
+                    {task.labels.map((label) => (
+                      <LabelChip key={label.id} label={label} />
+                    ))}
+                  </div>
+                )}
               </div>
             </div>
             <div className="flex items-center gap-2 self-end sm:self-center">
+              <button
+                onClick={() =>
+                  setOpenTaskId(openTaskId === task.id ? null : (task.id as string))
+                }
+                aria-expanded={openTaskId === task.id}
+                aria-label="Labels, activity, comments and attachments"
+                className={cn(
+                  "p-2 rounded-lg transition-all duration-200 hover:text-indigo-600 hover:bg-indigo-50",
+                  openTaskId === task.id ? "text-indigo-600" : "text-gray-400",
+                )}
+              >
+                <MessageSquare className="w-5 h-5" />
+              </button>
               <button
                 onClick={() => onEdit(task)}
                 className="p-2 text-gray-400 hover:text-indigo-600 hover:bg-indigo-50 rounded-lg transition-all duration-200"
@@ -133,6 +194,13 @@ export const TaskList = ({ onEdit }: TaskListProps) => {
               </button>
             </div>
           </div>
+          {openTaskId === task.id && (
+            <>
+              <TaskLabels taskId={task.id as string} labels={task.labels} />
+              <TaskAttachments taskId={task.id as string} />
+              <TaskActivity taskId={task.id as string} />
+            </>
+          )}
         </div>
       ))}
     </div>
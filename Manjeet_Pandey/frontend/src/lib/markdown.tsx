@@ -0,0 +1,146 @@
+import { Fragment, type ReactNode } from "react";
+
+/**
+ * Renders the subset of markdown used in comments: paragraphs, headings,
+ * lists, quotes, fenced code, and inline code, bold, italics and links.
+ * Everything is built as React elements, never as HTML, so a comment can't
+ * inject markup; links are only kept for http, https and mailto URLs.
+ */
+export const Markdown = ({ source }: { source: string }) => (
+  <div className="space-y-2 break-words">{renderBlocks(source)}</div>
+);
+
+const fence = /^```/;
+const heading = /^(#{1,6})\s+(.*)$/;
+const bullet = /^\s*[-*+]\s+(.*)$/;
+const numbered = /^\s*\d+[.)]\s+(.*)$/;
+const quote = /^>\s?(.*)$/;
+
+const renderBlocks = (source: string): ReactNode[] => {
+  const lines = source.replace(/\r\n?/g, "\n").split("\n");
+  const blocks: ReactNode[] = [];
+  let i = 0;
+
+  const collect = (pattern: RegExp) => {
+    const items: string[] = [];
+    while (i < lines.length && pattern.test(lines[i])) {
+      items.push(lines[i].match(pattern)![1]);
+      i++;
+    }
+    return items;
+  };
+
+  while (i < lines.length) {
+    const line = lines[i];
+    const key = blocks.length;
+    if (line.trim() === "") {
+      i++;
+    } else if (fence.test(line)) {
+      i++;
+      const code: string[] = [];
+      while (i < lines.length && !fence.test(lines[i])) {
+        code.push(lines[i]);
+        i++;
+      }
+      i++;
+      blocks.push(
+        <pre key={key} className="bg-gray-50 rounded-md p-3 text-sm overflow-x-auto">
+          <code>{code.join("\n")}</code>
+        </pre>,
+      );
+    } else if (heading.test(line)) {
+      blocks.push(
+        <p key={key} className="font-semibold text-gray-900">
+          {renderInline(line.match(heading)![2])}
+        </p>,
+      );
+      i++;
+    } else if (bullet.test(line)) {
+      blocks.push(
+        <ul key={key} className="list-disc pl-5">
+          {collect(bullet).map((item, j) => (
+            <li key={j}>{renderInline(item)}</li>
+          ))}
+        </ul>,
+      );
+    } else if (numbered.test(line)) {
+      blocks.push(
+        <ol key={key} className="list-decimal pl-5">
+          {collect(numbered).map((item, j) => (
+            <li key={j}>{renderInline(item)}</li>
+          ))}
+        </ol>,
+      );
+    } else if (quote.test(line)) {
+      blocks.push(
+        <blockquote key={key} className="border-l-4 border-gray-200 pl-3 text-gray-500">
+          {renderLines(collect(quote))}
+        </blockquote>,
+      );
+    } else {
+      const paragraph: string[] = [];
+      while (
+        i < lines.length &&
+        lines[i].trim() !== "" &&
+        ![fence, heading, bullet, numbered, quote].some((p) => p.test(lines[i]))
+      ) {
+        paragraph.push(lines[i]);
+        i++;
+      }
+      blocks.push(<p key={key}>{renderLines(paragraph)}</p>);
+    }
+  }
+  return blocks;
+};
+
+const renderLines = (lines: string[]) =>
+  lines.map((line, i) => (
+    <Fragment key={i}>
+      {i > 0 && <br />}
+      {renderInline(line)}
+    </Fragment>
+  ));
+
+const inline =
+  /`([^`]+)`|\*\*(.+?)\*\*|__(.+?)__|\*([^*]+)\*|_([^_]+)_|\[([^\]]+)\]\(([^)\s]+)\)/g;
+
+const safeUrl = (url: string) => /^(https?:|mailto:)/i.test(url);
+
+const renderInline = (text: string): ReactNode[] => {
+  const nodes: ReactNode[] = [];
+  let last = 0;
+  for (const match of text.matchAll(inline)) {
+    const [whole, code, bold, bold2, em, em2, label, url] = match;
+    const index = match.index ?? 0;
+    if (index > last) nodes.push(text.slice(last, index));
+    const key = nodes.length;
+    if (code !== undefined) {
+      nodes.push(
+        <code key={key} className="bg-gray-100 rounded px-1 text-sm">
+          {code}
+        </code>,
+      );
+    } else if (bold !== undefined || bold2 !== undefined) {
+      nodes.push(<strong key={key}>{renderInline(bold ?? bold2)}</strong>);
+    } else if (em !== undefined || em2 !== undefined) {
+      nodes.push(<em key={key}>{renderInline(em ?? em2)}</em>);
+    } else if (safeUrl(url)) {
+      nodes.push(
+        <a
+          key={key}
+          href={url}
+          target="_blank"
+          rel="noopener noreferrer nofollow"
+          className="text-indigo-600 underline"
+        >
+          {renderInline(label)}
+        </a>,
+      );
+    } else {
+      nodes.push(whole);
+    }
+    last = index + whole.length;
+  }
+  if (last < text.length) nodes.push(text.slice(last));
+  return nodes;
+};